@@ -0,0 +1,26 @@
+// Package api содержит контракт HTTP API, встроенный в бинарник
+package api
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// OpenAPI — спецификация OpenAPI 3 в YAML; по ней отдаётся /openapi.json и проверяются запросы
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
+
+// Load разбирает встроенную спецификацию и проверяет её корректность
+func Load() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(OpenAPI)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
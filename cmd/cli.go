@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/oidc/oidctest"
+	rosterfmt "ReviewAssigner/internal/pkg/roster"
+	"ReviewAssigner/internal/repository/postgres"
+	"ReviewAssigner/internal/usecase/roster"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const cliUsage = `usage:
+  main                                       start HTTP server
+  main import -file teams.yaml [-format yaml|csv] [-dry-run]
+  main export [-format yaml|csv] [-out teams.yaml]
+  main sync -file teams.yaml [-apply]        plan (default) or enforce config; plan exits 2 on drift
+  main idp [-addr :9000] [-client-id review-assigner] [-claims '{"preferred_username":"u1"}']
+                                             local stand-in OIDC provider for development`
+
+// errDriftDetected — sync в режиме plan нашёл расхождения (код выхода 2)
+var errDriftDetected = errors.New("drift detected: database differs from config")
+
+// runCommand выполняет CLI-команду вместо запуска сервера
+func runCommand(args []string) error {
+	switch args[0] {
+	case "import":
+		return runImport(args[1:])
+	case "export":
+		return runExport(args[1:])
+	case "sync":
+		return runSync(args[1:])
+	case "idp":
+		return runIdP(args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(cliUsage)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q\n%s", args[0], cliUsage)
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := fs.String("file", "", "path to CSV or YAML file (\"-\" for stdin)")
+	format := fs.String("format", "", "csv or yaml (default: by file extension)")
+	dryRun := fs.Bool("dry-run", false, "only print the diff")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := readRoster(*file, *format)
+	if err != nil {
+		return err
+	}
+
+	db := connectDB()
+	defer db.Close()
+	diff, err := newRosterUsecase(db).Import(context.Background(), data, *dryRun)
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]interface{}{"dry_run": *dryRun, "diff": diff})
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "", "csv or yaml (default: by file extension, yaml for stdout)")
+	outPath := fs.String("out", "", "output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f, err := rosterfmt.NormalizeFormat(formatOrExt(*format, *outPath))
+	if err != nil {
+		return err
+	}
+
+	db := connectDB()
+	defer db.Close()
+	data, err := newRosterUsecase(db).Export(context.Background())
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		fh, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		out = fh
+	}
+	return rosterfmt.Encode(f, out, data)
+}
+
+func runSync(args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	file := fs.String("file", "", "path to YAML config (\"-\" for stdin)")
+	apply := fs.Bool("apply", false, "enforce the config instead of only printing the plan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	config, err := readRoster(*file, rosterfmt.FormatYAML)
+	if err != nil {
+		return err
+	}
+
+	db := connectDB()
+	defer db.Close()
+	diff, err := newRosterUsecase(db).Sync(context.Background(), config, *apply)
+	if err != nil {
+		return err
+	}
+
+	mode := "plan"
+	if *apply {
+		mode = "apply"
+	}
+	if err := printJSON(map[string]interface{}{"mode": mode, "in_sync": !diff.HasChanges(), "diff": diff}); err != nil {
+		return err
+	}
+	if !*apply && diff.HasChanges() {
+		return errDriftDetected
+	}
+	return nil
+}
+
+// runIdP запускает тестовый OIDC-провайдер: любой вход подтверждается автоматически с заданными claims
+func runIdP(args []string) error {
+	fs := flag.NewFlagSet("idp", flag.ContinueOnError)
+	addr := fs.String("addr", ":9000", "listen address")
+	issuer := fs.String("issuer", "", "issuer URL (default: http://localhost<addr>)")
+	clientID := fs.String("client-id", "review-assigner", "accepted client_id")
+	claims := fs.String("claims", `{"sub":"u1","preferred_username":"u1","groups":[]}`, "JSON claims of the signed-in user")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *issuer == "" {
+		*issuer = "http://localhost" + *addr
+	}
+
+	var userClaims map[string]interface{}
+	if err := json.Unmarshal([]byte(*claims), &userClaims); err != nil {
+		return fmt.Errorf("invalid -claims: %w", err)
+	}
+	idp, err := oidctest.NewIdP(*issuer, *clientID)
+	if err != nil {
+		return err
+	}
+	idp.SetClaims(userClaims)
+
+	log.Printf("Stand-in OIDC provider listening on %s (issuer %s)", *addr, *issuer)
+	return http.ListenAndServe(*addr, idp)
+}
+
+func newRosterUsecase(db *sqlx.DB) *roster.Usecase {
+	timeouts := loadQueryTimeouts()
+	return roster.NewUsecase(postgres.NewTeamRepository(db, timeouts), postgres.NewUserRepository(db, timeouts))
+}
+
+func readRoster(path, format string) (*schemas.Roster, error) {
+	if path == "" {
+		return nil, fmt.Errorf("-file is required")
+	}
+	f, err := rosterfmt.NormalizeFormat(formatOrExt(format, path))
+	if err != nil {
+		return nil, err
+	}
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		fh, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer fh.Close()
+		in = fh
+	}
+	return rosterfmt.Decode(f, in)
+}
+
+func printJSON(v interface{}) error {
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	return out.Encode(v)
+}
+
+// formatOrExt возвращает явно заданный формат или расширение файла
+func formatOrExt(format, path string) string {
+	if format != "" {
+		return format
+	}
+	if ext := filepath.Ext(path); ext != "" {
+		return strings.TrimPrefix(ext, ".")
+	}
+	return rosterfmt.FormatYAML
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"crypto/rand"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/jwt"
+	"ReviewAssigner/internal/pkg/notify"
+	"ReviewAssigner/internal/pkg/oidc"
+	"ReviewAssigner/internal/pkg/scm"
+	"ReviewAssigner/internal/repository/postgres"
+	"ReviewAssigner/internal/usecase/sso"
+)
+
+// loadJWTConfig читает настройки подписи токенов:
+//
+//	JWT_ALG               HS256 (по умолчанию), RS256 или ES256
+//	JWT_SECRET            секрет для HS256 (не короче 32 байт)
+//	JWT_PRIVATE_KEY_FILE  PEM с приватным ключом для RS256/ES256
+//	JWT_KEY_ID            kid текущего ключа (по умолчанию — отпечаток ключа)
+//	JWT_VERIFY_KEYS       kid=путь,kid=путь — публичные ключи, которые ещё принимаются после ротации
+//	JWT_TTL               время жизни access-токена (по умолчанию 15m)
+func loadJWTConfig() jwt.Config {
+	cfg := jwt.Config{
+		Algorithm:     strings.ToUpper(getEnv("JWT_ALG", jwt.AlgHS256)),
+		KeyID:         getEnv("JWT_KEY_ID", ""),
+		VerifyKeysPEM: map[string][]byte{},
+		TTL:           getEnvDuration("JWT_TTL", 15*time.Minute),
+	}
+
+	if cfg.Algorithm == jwt.AlgHS256 {
+		cfg.Secret = []byte(getEnv("JWT_SECRET", ""))
+		if len(cfg.Secret) == 0 {
+			// Без секрета токены не переживут рестарт, но и не будут подписаны известным всем ключом
+			log.Println("WARNING: JWT_SECRET is not set, using a random key; tokens will be invalid after restart")
+			cfg.Secret = make([]byte, 32)
+			if _, err := rand.Read(cfg.Secret); err != nil {
+				log.Fatal("Failed to generate JWT secret:", err)
+			}
+		}
+	} else {
+		cfg.PrivateKeyPEM = readFileEnv("JWT_PRIVATE_KEY_FILE")
+	}
+
+	for _, entry := range strings.Split(getEnv("JWT_VERIFY_KEYS", ""), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, path, ok := strings.Cut(entry, "=")
+		if !ok {
+			log.Fatalf("Invalid JWT_VERIFY_KEYS entry %q, expected kid=path", entry)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read verification key %q: %v", kid, err)
+		}
+		cfg.VerifyKeysPEM[kid] = data
+	}
+	return cfg
+}
+
+// loadOIDCConfig читает настройки SSO; ok == false, если OIDC_ISSUER не задан:
+//
+//	OIDC_ISSUER          адрес провайдера (discovery: <issuer>/.well-known/openid-configuration)
+//	OIDC_CLIENT_ID       client_id приложения; он же ожидаемый aud токенов
+//	OIDC_CLIENT_SECRET   секрет клиента (пустой — public-клиент, только PKCE)
+//	OIDC_REDIRECT_URL    адрес /auth/oidc/callback, зарегистрированный у провайдера
+//	OIDC_SCOPES          через пробел (по умолчанию "openid profile groups")
+//	OIDC_AUDIENCE        aud access-токенов, если отличается от client_id
+//	OIDC_USER_ID_CLAIM   claim с user_id домена (по умолчанию preferred_username; вложенные — через точку)
+//	OIDC_GROUPS_CLAIM    claim со списком групп (по умолчанию groups)
+//	OIDC_GROUP_ROLES     группа=роль,группа=роль — роли admin, member или read_only
+//	OIDC_DEFAULT_ROLE    роль без сопоставленных групп (по умолчанию member; "none" — запретить вход)
+func loadOIDCConfig() (oidc.Config, sso.Mapping, bool) {
+	issuer := getEnv("OIDC_ISSUER", "")
+	if issuer == "" {
+		return oidc.Config{}, sso.Mapping{}, false
+	}
+
+	cfg := oidc.Config{
+		IssuerURL:    issuer,
+		ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+		ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+		RedirectURL:  getEnv("OIDC_REDIRECT_URL", ""),
+		Scopes:       strings.Fields(getEnv("OIDC_SCOPES", "openid profile groups")),
+		Audience:     getEnv("OIDC_AUDIENCE", ""),
+	}
+	if cfg.ClientID == "" || cfg.RedirectURL == "" {
+		log.Fatal("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC_ISSUER is set")
+	}
+
+	mapping := sso.Mapping{
+		UserIDClaim: getEnv("OIDC_USER_ID_CLAIM", "preferred_username"),
+		GroupsClaim: getEnv("OIDC_GROUPS_CLAIM", "groups"),
+		GroupRoles:  map[string]string{},
+		DefaultRole: getEnv("OIDC_DEFAULT_ROLE", schemas.RoleMember),
+	}
+	if mapping.DefaultRole == "none" {
+		mapping.DefaultRole = ""
+	} else if !schemas.IsAccountRole(mapping.DefaultRole) {
+		log.Fatalf("Invalid OIDC_DEFAULT_ROLE %q", mapping.DefaultRole)
+	}
+	for _, entry := range strings.Split(getEnv("OIDC_GROUP_ROLES", ""), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		group, role, ok := strings.Cut(entry, "=")
+		if !ok || !schemas.IsAccountRole(role) {
+			log.Fatalf("Invalid OIDC_GROUP_ROLES entry %q, expected group=admin|member|read_only", entry)
+		}
+		mapping.GroupRoles[group] = role
+	}
+	return cfg, mapping, true
+}
+
+// loadStreamOrigins читает STREAM_ALLOWED_ORIGINS — через запятую страницы других адресов
+// (https://dash.example.com), которым разрешено открывать WebSocket живого потока; "*" — любые.
+// Без переменной принимаются только запросы без Origin и с того же адреса, что и API.
+func loadStreamOrigins() []string {
+	var origins []string
+	for _, origin := range strings.Split(getEnv("STREAM_ALLOWED_ORIGINS", ""), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, strings.TrimSuffix(origin, "/"))
+		}
+	}
+	return origins
+}
+
+// loadTrustedProxies читает TRUSTED_PROXIES — через запятую IP или CIDR балансировщиков, чьим
+// X-Forwarded-For и X-Real-IP можно верить. Без переменной адрес клиента — адрес соединения.
+func loadTrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// loadRateLimits читает лимиты групп маршрутов в формате "N/период" (N запросов за период,
+// столько же допускается подряд) или "off":
+//
+//	RATE_LIMIT_AUTH   вход и обновление токенов, по IP (по умолчанию 10/1m)
+//	RATE_LIMIT_ADMIN  /admin/* и управление учётками (по умолчанию 30/1m)
+//	RATE_LIMIT_WRITE  остальные изменяющие запросы (по умолчанию 60/1m)
+//	RATE_LIMIT_READ   GET-запросы (по умолчанию 300/1m)
+func loadRateLimits() map[string]schemas.RateLimit {
+	defaults := map[string]string{
+		schemas.RateLimitGroupAuth:  "10/1m",
+		schemas.RateLimitGroupAdmin: "30/1m",
+		schemas.RateLimitGroupWrite: "60/1m",
+		schemas.RateLimitGroupRead:  "300/1m",
+	}
+
+	limits := map[string]schemas.RateLimit{}
+	for group, fallback := range defaults {
+		key := "RATE_LIMIT_" + strings.ToUpper(group)
+		value := getEnv(key, fallback)
+		if value == "off" {
+			continue
+		}
+		count, period, ok := strings.Cut(value, "/")
+		n, err := strconv.Atoi(count)
+		if !ok || err != nil || n <= 0 {
+			log.Fatalf("Invalid %s %q, expected N/period or off", key, value)
+		}
+		d, err := time.ParseDuration(period)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid %s %q, expected N/period or off", key, value)
+		}
+		limits[group] = schemas.RateLimit{Rate: float64(n) / d.Seconds(), Burst: n}
+	}
+	return limits
+}
+
+// loadQueryTimeouts читает таймауты запросов к БД ("0" — без ограничения):
+//
+//	DB_READ_TIMEOUT   чтение по ID и пачкой (по умолчанию 3s)
+//	DB_WRITE_TIMEOUT  изменения и единицы работы целиком (по умолчанию 5s)
+//	DB_LIST_TIMEOUT   полные списки, экспорт и статистика (по умолчанию 15s)
+func loadQueryTimeouts() postgres.QueryTimeouts {
+	return postgres.QueryTimeouts{
+		Read:  getEnvDuration("DB_READ_TIMEOUT", 3*time.Second),
+		Write: getEnvDuration("DB_WRITE_TIMEOUT", 5*time.Second),
+		List:  getEnvDuration("DB_LIST_TIMEOUT", 15*time.Second),
+	}
+}
+
+// serverConfig — параметры HTTP-сервера и его плавной остановки
+type serverConfig struct {
+	Addr            string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	MaxHeaderBytes  int
+	MaxBodyBytes    int64
+	DrainDelay      time.Duration
+	ShutdownTimeout time.Duration
+	TrustedProxies  []string
+}
+
+// loadServerConfig читает настройки HTTP-сервера ("0" у таймаутов и HTTP_MAX_BODY_BYTES — без ограничения):
+//
+//	HTTP_ADDR              адрес (по умолчанию :8080)
+//	HTTP_READ_TIMEOUT      чтение запроса вместе с телом (по умолчанию 15s)
+//	HTTP_WRITE_TIMEOUT     обработка запроса и запись ответа (по умолчанию 60s; живой поток событий не ограничивается)
+//	HTTP_IDLE_TIMEOUT      простой keep-alive соединения (по умолчанию 120s)
+//	HTTP_MAX_HEADER_BYTES  предел заголовков запроса (по умолчанию 1048576)
+//	HTTP_MAX_BODY_BYTES    предел тела запроса, больше — 413 (по умолчанию 10485760)
+//	SHUTDOWN_DRAIN_DELAY   сколько /ready отвечает 503 до остановки приёма соединений (по умолчанию 5s)
+//	SHUTDOWN_TIMEOUT       сколько ждать начатые запросы, затем воркеры (по умолчанию 30s на каждый этап)
+//	TRUSTED_PROXIES        IP или CIDR прокси через запятую, которым верим X-Forwarded-For (по умолчанию никому)
+func loadServerConfig() serverConfig {
+	return serverConfig{
+		Addr:            getEnv("HTTP_ADDR", ":8080"),
+		ReadTimeout:     getEnvDuration("HTTP_READ_TIMEOUT", 15*time.Second),
+		WriteTimeout:    getEnvDuration("HTTP_WRITE_TIMEOUT", time.Minute),
+		IdleTimeout:     getEnvDuration("HTTP_IDLE_TIMEOUT", 2*time.Minute),
+		MaxHeaderBytes:  getEnvInt("HTTP_MAX_HEADER_BYTES", 1<<20),
+		MaxBodyBytes:    int64(getEnvInt("HTTP_MAX_BODY_BYTES", 10<<20)),
+		DrainDelay:      getEnvDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		TrustedProxies:  loadTrustedProxies(),
+	}
+}
+
+// loadReviewerClients создаёт клиенты API провайдеров для отправки ревьюверов:
+//
+//	SCM_TOKEN_<PROVIDER>    токен сервиса у провайдера; без него провайдер не синхронизируется
+//	SCM_API_URL_<PROVIDER>  адрес API (для GitHub и GitLab по умолчанию — публичный, для Gitea обязателен)
+func loadReviewerClients(providers []string) map[string]scm.ReviewerClient {
+	clients := map[string]scm.ReviewerClient{}
+	for _, provider := range providers {
+		name := strings.ToUpper(provider)
+		token := getEnv("SCM_TOKEN_"+name, "")
+		if token == "" {
+			continue
+		}
+		client, err := scm.NewReviewerClient(provider, getEnv("SCM_API_URL_"+name, ""), token)
+		if err != nil {
+			log.Fatalf("Invalid SCM_API_URL_%s: %v", name, err)
+		}
+		clients[provider] = client
+	}
+	return clients
+}
+
+// loadNotifyChannels создаёт каналы уведомлений; канал без настроек выключен:
+//
+//	SMTP_ADDR, SMTP_FROM, SMTP_USERNAME, SMTP_PASSWORD  почта (host:port; без SMTP_USERNAME — без аутентификации)
+//	SMTP_TIMEOUT                                        ограничение на отправку одного письма (по умолчанию 10s)
+//	SLACK_WEBHOOK_URL                                   входящий вебхук Slack или Mattermost
+//	TELEGRAM_BOT_TOKEN, TELEGRAM_API_URL                Telegram Bot API (адрес — для своего Bot API сервера)
+func loadNotifyChannels() map[string]notify.Channel {
+	channels := map[string]notify.Channel{}
+	if addr := getEnv("SMTP_ADDR", ""); addr != "" {
+		from := getEnv("SMTP_FROM", "")
+		if from == "" {
+			log.Fatal("SMTP_FROM is required with SMTP_ADDR")
+		}
+		channels[notify.ChannelEmail] = &notify.SMTP{
+			Addr:     addr,
+			From:     from,
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			Timeout:  getEnvDuration("SMTP_TIMEOUT", 10*time.Second),
+		}
+	}
+	if url := getEnv("SLACK_WEBHOOK_URL", ""); url != "" {
+		channels[notify.ChannelSlack] = &notify.Slack{WebhookURL: url}
+	}
+	if token := getEnv("TELEGRAM_BOT_TOKEN", ""); token != "" {
+		channels[notify.ChannelTelegram] = &notify.Telegram{APIURL: getEnv("TELEGRAM_API_URL", ""), Token: token}
+	}
+	return channels
+}
+
+func readFileEnv(key string) []byte {
+	path := getEnv(key, "")
+	if path == "" {
+		log.Fatalf("%s is required", key)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", key, err)
+	}
+	return data
+}
@@ -2,22 +2,249 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"ReviewAssigner/api"
+	"ReviewAssigner/internal/delivery/graphql"
+	grpcapi "ReviewAssigner/internal/delivery/grpc"
 	"ReviewAssigner/internal/delivery/http"
+	"ReviewAssigner/internal/delivery/middleware"
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/pkg/jwt"
+	"ReviewAssigner/internal/pkg/notify"
+	"ReviewAssigner/internal/pkg/oidc"
+	"ReviewAssigner/internal/pkg/scm"
+	"ReviewAssigner/internal/repository/inmemory"
 	"ReviewAssigner/internal/repository/postgres"
+	"ReviewAssigner/internal/usecase/apikey"
+	"ReviewAssigner/internal/usecase/authz"
+	"ReviewAssigner/internal/usecase/auth"
+	"ReviewAssigner/internal/usecase/digest"
+	"ReviewAssigner/internal/usecase/notifier"
+	"ReviewAssigner/internal/usecase/outbox"
 	"ReviewAssigner/internal/usecase/pr"
+	"ReviewAssigner/internal/usecase/reviewsync"
+	"ReviewAssigner/internal/usecase/roster"
+	"ReviewAssigner/internal/usecase/scmhook"
+	"ReviewAssigner/internal/usecase/session"
+	"ReviewAssigner/internal/usecase/sso"
+	"ReviewAssigner/internal/usecase/stream"
 	"ReviewAssigner/internal/usecase/team"
 	"ReviewAssigner/internal/usecase/user"
+	"ReviewAssigner/internal/usecase/webhook"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
 )
 
-func InitApp() *gin.Engine {
-	// === Подключение к БД ===
+// app — собранное приложение: HTTP-роутер и gRPC-сервер поверх одних и тех же usecase,
+// фоновые воркеры и пул соединений с БД, который закрывается последним
+type app struct {
+	router     *gin.Engine
+	grpcServer *grpc.Server
+	handlers   *http.Handlers
+	reviewSync *reviewsync.Usecase // nil, если синхронизация ревьюверов выключена
+	db         *sqlx.DB
+	workers    sync.WaitGroup
+}
+
+// goWorker запускает фоновый воркер; Wait ждёт его завершения после отмены контекста InitApp
+func (a *app) goWorker(run func()) {
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		run()
+	}()
+}
+
+// Wait ждёт остановки воркеров и фоновых синхронизаций ревьюверов
+func (a *app) Wait() {
+	a.workers.Wait()
+	if a.reviewSync != nil {
+		a.reviewSync.Wait()
+	}
+}
+
+// InitApp собирает зависимости; фоновые воркеры работают до отмены ctx
+func InitApp(ctx context.Context, cfg serverConfig) *app {
+	db := connectDB()
+	a := &app{db: db}
+
+	// === Репозитории и UseCase ===
+	timeouts := loadQueryTimeouts()
+	userRepo := postgres.NewUserRepository(db, timeouts)
+	teamRepo := postgres.NewTeamRepository(db, timeouts)
+	prRepo := postgres.NewPullRequestRepository(db, timeouts)
+	accountRepo := postgres.NewAccountRepository(db, timeouts)
+	tokenRepo := postgres.NewTokenRepository(db, timeouts)
+	apiKeyRepo := postgres.NewAPIKeyRepository(db, timeouts)
+	roleBindingRepo := postgres.NewRoleBindingRepository(db, timeouts)
+	scmIdentityRepo := postgres.NewSCMIdentityRepository(db, timeouts)
+
+	// Ревьюверы PR из вебхуков отправляются провайдеру, если задан его SCM_TOKEN_*
+	scmRegistry := scm.DefaultRegistry()
+	var reviewSyncUsecase *reviewsync.Usecase
+	var reviewerSyncer pr.ReviewerSyncer
+	if clients := loadReviewerClients(scmRegistry.Names()); len(clients) > 0 {
+		reviewSyncUsecase = reviewsync.NewUsecase(clients, prRepo, scmIdentityRepo, reviewsync.RetryPolicy{
+			Attempts:  getEnvInt("SCM_SYNC_ATTEMPTS", 5),
+			BaseDelay: getEnvDuration("SCM_SYNC_BACKOFF", time.Second),
+			MaxDelay:  getEnvDuration("SCM_SYNC_MAX_BACKOFF", time.Minute),
+		})
+		reviewerSyncer = reviewSyncUsecase
+		a.reviewSync = reviewSyncUsecase
+	}
+
+	// Исходящие вебхуки: события сохраняются как доставки, воркер отправляет их с повторами
+	webhookUsecase := webhook.NewUsecase(postgres.NewWebhookSubscriptionRepository(db, timeouts), postgres.NewWebhookDeliveryRepository(db, timeouts),
+		webhook.RetryPolicy{
+			MaxAttempts: getEnvInt("WEBHOOK_DELIVERY_ATTEMPTS", 8),
+			BaseDelay:   getEnvDuration("WEBHOOK_DELIVERY_BACKOFF", 10*time.Second),
+			MaxDelay:    getEnvDuration("WEBHOOK_DELIVERY_MAX_BACKOFF", time.Hour),
+		})
+	webhookPoll := getEnvDuration("WEBHOOK_DELIVERY_POLL", 5*time.Second)
+	a.goWorker(func() { webhookUsecase.Run(ctx, webhookPoll) })
+
+	// Уведомления ревьюверам по каналам из их настроек; шаблоны можно переопределить в NOTIFY_TEMPLATE_DIR
+	templates, err := notify.LoadTemplates(getEnv("NOTIFY_TEMPLATE_DIR", ""))
+	if err != nil {
+		log.Fatal("Failed to load notification templates:", err)
+	}
+	notificationPrefRepo := postgres.NewNotificationPreferenceRepository(db, timeouts)
+	notifierUsecase := notifier.NewUsecase(loadNotifyChannels(), templates, notificationPrefRepo, userRepo)
+
+	// Ежедневные сводки ожидающих ревью: расписание проверяется раз в DIGEST_POLL.
+	// DIGEST_DEFAULT_CHANNEL включает сводки активным пользователям без своей настройки.
+	digestDefault, err := digest.DefaultSetting(getEnv("DIGEST_DEFAULT_CHANNEL", ""),
+		getEnv("DIGEST_DEFAULT_SEND_AT", "09:00"), getEnv("DIGEST_DEFAULT_TIMEZONE", "UTC"))
+	if err != nil {
+		log.Fatal("Invalid DIGEST_DEFAULT_*:", err)
+	}
+	digestUsecase := digest.NewUsecase(postgres.NewDigestSettingRepository(db, timeouts), notificationPrefRepo, userRepo, prRepo, notifierUsecase, digestDefault)
+	digestPoll := getEnvDuration("DIGEST_POLL", time.Minute)
+	a.goWorker(func() { digestUsecase.Run(ctx, digestPoll) })
+
+	// Доменные события пишутся в outbox вместе с изменениями; relay доставляет их получателям
+	outboxRepo := postgres.NewOutboxRepository(db, timeouts)
+	outboxUsecase := outbox.NewUsecase(outboxRepo, map[string]interfaces.EventPublisher{
+		"webhooks": webhookUsecase,
+		"notifier": notifierUsecase,
+	}, getEnvDuration("OUTBOX_RETENTION", 7*24*time.Hour))
+	outboxPoll := getEnvDuration("OUTBOX_POLL", time.Second)
+	a.goWorker(func() { outboxUsecase.Run(ctx, outboxPoll) })
+
+	// Живой поток читает outbox напрямую, поэтому события любого инстанса видны всем
+	streamUsecase := stream.NewUsecase(outboxRepo, userRepo)
+	streamPoll := getEnvDuration("EVENT_STREAM_POLL", 500*time.Millisecond)
+	a.goWorker(func() { streamUsecase.Run(ctx, streamPoll) })
+
+	teamUsecase := team.NewUsecase(teamRepo)
+	userUsecase := user.NewUsecase(userRepo, prRepo)
+	prUsecase := pr.NewUsecase(userRepo, prRepo, teamRepo, postgres.NewTxManager(db, timeouts), reviewerSyncer)
+	rosterUsecase := roster.NewUsecase(teamRepo, userRepo)
+	authUsecase := auth.NewUsecase(accountRepo, userRepo, auth.LockoutPolicy{
+		MaxAttempts: getEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+		Duration:    getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+	})
+
+	// Bootstrap-админ создаётся только если задан ADMIN_PASSWORD и учётки ещё нет
+	if adminPassword := getEnv("ADMIN_PASSWORD", ""); adminPassword != "" {
+		if err := authUsecase.EnsureAdmin(context.Background(), getEnv("ADMIN_LOGIN", "admin"), adminPassword); err != nil {
+			log.Fatal("Failed to create admin account:", err)
+		}
+	}
+
+	tokens, err := jwt.NewManager(loadJWTConfig())
+	if err != nil {
+		log.Fatal("Failed to configure JWT:", err)
+	}
+
+	sessionUsecase := session.NewUsecase(accountRepo, tokenRepo, tokens, getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour))
+
+	apiKeyUsecase := apikey.NewUsecase(apiKeyRepo)
+	authzUsecase := authz.NewUsecase(roleBindingRepo, accountRepo, userRepo, prRepo, teamRepo)
+
+	// SSO включается, если задан OIDC_ISSUER
+	var ssoUsecase *sso.Usecase
+	if oidcConfig, mapping, ok := loadOIDCConfig(); ok {
+		ssoUsecase = sso.NewUsecase(oidc.NewProvider(oidcConfig), postgres.NewOIDCStateRepository(db, timeouts), accountRepo, userRepo,
+			mapping, getEnvDuration("OIDC_STATE_TTL", 10*time.Minute))
+	}
+
+	// Вебхуки провайдера принимаются, только если задан его секрет (WEBHOOK_SECRET_GITHUB и т.д.)
+	webhookSecrets := map[string]string{}
+	for _, provider := range scmRegistry.Names() {
+		webhookSecrets[provider] = getEnv("WEBHOOK_SECRET_"+strings.ToUpper(provider), "")
+	}
+	scmHookUsecase := scmhook.NewUsecase(scmRegistry, webhookSecrets, prUsecase, scmIdentityRepo,
+		postgres.NewSCMDeliveryRepository(db, timeouts), userRepo)
+
+	// GraphQL для дашбордов: только чтение, вложенные связи загружаются пачками
+	graphQLServer := graphql.NewServer(teamUsecase, userUsecase, prUsecase, authzUsecase)
+
+	handlers := http.NewHandlers(teamUsecase, userUsecase, prUsecase, rosterUsecase, authUsecase, sessionUsecase, apiKeyUsecase, authzUsecase, ssoUsecase, scmHookUsecase, reviewSyncUsecase, webhookUsecase, notifierUsecase, digestUsecase, outboxUsecase, streamUsecase, graphQLServer, tokens,
+		loadStreamOrigins())
+
+	// === Gin ===
+	r, err := http.NewEngine(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+	r.Use(middleware.MaxBodySize(cfg.MaxBodyBytes))
+
+	// Счётчики лимитов: в памяти для одного инстанса, в postgres — общие для всех
+	var rateLimitRepo interfaces.RateLimitRepository = inmemory.NewRateLimitRepository()
+	if getEnv("RATE_LIMIT_STORE", "memory") == "postgres" {
+		rateLimitRepo = postgres.NewRateLimitRepository(db, timeouts)
+	}
+	rateLimit := middleware.RateLimitMiddleware(rateLimitRepo, loadRateLimits())
+
+	// Ответы на POST с Idempotency-Key: в postgres (по умолчанию) повтор узнаётся любым инстансом
+	var idempotencyRepo interfaces.IdempotencyRepository = postgres.NewIdempotencyRepository(db, timeouts)
+	if getEnv("IDEMPOTENCY_STORE", "postgres") == "memory" {
+		idempotencyRepo = inmemory.NewIdempotencyRepository()
+	}
+	idempotency := middleware.IdempotencyMiddleware(idempotencyRepo, getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour))
+
+	// Проверка запросов (и ответов) по api/openapi.yaml: off | requests | all
+	if mode := getEnv("OPENAPI_VALIDATION", middleware.OpenAPIValidationOff); mode != middleware.OpenAPIValidationOff {
+		doc, err := api.Load()
+		if err != nil {
+			log.Fatal("Failed to load OpenAPI spec:", err)
+		}
+		validation, err := middleware.OpenAPIValidation(doc, mode)
+		if err != nil {
+			log.Fatal("Failed to configure OpenAPI validation:", err)
+		}
+		r.Use(validation)
+	}
+
+	handlers.RegisterPublicRoutes(r, rateLimit)
+	handlers.RegisterRoutes(r, rateLimit, idempotency)
+
+	// gRPC API: те же usecase, аутентификация и права — в интерсепторе
+	var external middleware.BearerVerifier
+	if ssoUsecase != nil {
+		external = ssoUsecase
+	}
+	grpcServer := grpcapi.NewServer(grpcapi.NewAuthenticator(tokens, sessionUsecase, apiKeyUsecase, external, authzUsecase),
+		teamUsecase, userUsecase, prUsecase, authzUsecase)
+
+	a.router, a.grpcServer, a.handlers = r, grpcServer, handlers
+	log.Println("Server initialized successfully")
+	return a
+}
+
+// === Подключение к БД ===
+func connectDB() *sqlx.DB {
 	dbHost := getEnv("DB_HOST", "db")
 	dbPort := getEnv("DB_PORT", "5432")
 	dbName := getEnv("DB_NAME", "review_assigner")
@@ -35,30 +262,7 @@ func InitApp() *gin.Engine {
 	if err != nil {
 		log.Fatal("Failed to connect to DB:", err)
 	}
-
-	// === Репозитории и UseCase ===
-	userRepo := postgres.NewUserRepository(db)
-	teamRepo := postgres.NewTeamRepository(db)
-	prRepo := postgres.NewPullRequestRepository(db)
-
-	teamUsecase := team.NewUsecase(teamRepo)
-	userUsecase := user.NewUsecase(userRepo, prRepo)
-	prUsecase := pr.NewUsecase(userRepo, prRepo)
-
-	handlers := http.NewHandlers(teamUsecase, userUsecase, prUsecase)
-
-	// === Gin ===
-	r := gin.Default()
-
-	// Публичные роуты
-	r.GET("/health", handlers.Health)
-	r.POST("/auth/login", handlers.Login)
-
-	// Все остальные роуты — защищённые
-	handlers.RegisterRoutes(r)
-
-	log.Println("Server initialized successfully")
-	return r
+	return db
 }
 
 func getEnv(key, fallback string) string {
@@ -67,3 +271,27 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return n
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return d
+}
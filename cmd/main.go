@@ -1,25 +1,113 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
-// @title           Review Assigner API
-// @version         1.0
-// @description     API for assigning code reviewers to pull requests
+// Контракт HTTP API — api/openapi.yaml (отдаётся на /openapi.json, UI — /docs/)
+func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			if errors.Is(err, errDriftDetected) {
+				log.Println(err)
+				os.Exit(2)
+			}
+			log.Fatal(err)
+		}
+		return
+	}
 
-// @host            localhost:8080
-// @BasePath        /
+	cfg := loadServerConfig()
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	a := InitApp(workersCtx, cfg)
 
-// @securityDefinitions.apikey BearerAuth
-// @in                         header
-// @name                       Authorization
-// @description                Type "Bearer" followed by a space and JWT token.
+	// gRPC слушает отдельный порт в том же процессе
+	grpcAddr := getEnv("GRPC_ADDR", ":9090")
+	lis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		log.Fatal("Failed to listen for gRPC:", err)
+	}
+	go func() {
+		log.Println("gRPC server starting on " + grpcAddr)
+		if err := a.grpcServer.Serve(lis); err != nil {
+			log.Fatal("Failed to start gRPC server:", err)
+		}
+	}()
 
-func main() {
-	r := InitApp()
-	log.Println("Server starting on :8080")
-	if err := r.Run(":8080"); err != nil {
+	server := &http.Server{
+		Addr:           cfg.Addr,
+		Handler:        a.router,
+		ReadTimeout:    cfg.ReadTimeout,
+		WriteTimeout:   cfg.WriteTimeout,
+		IdleTimeout:    cfg.IdleTimeout,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
+	}
+	// Shutdown не ждёт соединения, из которых никто не читает: живые потоки завершаются сами
+	server.RegisterOnShutdown(a.handlers.CloseStreams)
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Println("Server starting on " + cfg.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	signals, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	select {
+	case err := <-serveErr:
 		log.Fatal("Failed to start server:", err)
+	case <-signals.Done():
+	}
+	stop()
+	shutdown(a, server, stopWorkers, cfg)
+}
+
+// shutdown останавливает приложение по шагам: /ready начинает отвечать 503, чтобы балансировщик
+// перестал слать запросы; начатые HTTP- и gRPC-запросы дорабатывают; воркеры завершают текущий проход;
+// последним закрывается пул соединений с БД
+func shutdown(a *app, server *http.Server, stopWorkers context.CancelFunc, cfg serverConfig) {
+	log.Println("Shutting down: readiness is failing, draining in", cfg.DrainDelay)
+	a.handlers.Drain()
+	time.Sleep(cfg.DrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Println("HTTP server shutdown:", err)
+	}
+	grpcStopped := make(chan struct{})
+	go func() {
+		a.grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		a.grpcServer.Stop()
+	}
+
+	stopWorkers()
+	workersStopped := make(chan struct{})
+	go func() {
+		a.Wait()
+		close(workersStopped)
+	}()
+	select {
+	case <-workersStopped:
+	case <-time.After(cfg.ShutdownTimeout):
+		log.Println("Background workers did not stop in", cfg.ShutdownTimeout)
+	}
+
+	if err := a.db.Close(); err != nil {
+		log.Println("Failed to close DB:", err)
 	}
+	log.Println("Server stopped")
 }
@@ -0,0 +1,73 @@
+package graphql
+
+import (
+	"sort"
+	"sync"
+)
+
+// loader загружает значения пачками в пределах одного запроса. Ключи, которые точно понадобятся
+// (участники команды, ревьюверы загруженных PR), добавляются через Prime и загружаются одним
+// вызовом fetch при первом Load любого из них — поэтому вложенные поля не делают N+1 запросов.
+type loader[V any] struct {
+	fetch func(keys []string) (map[string]V, error)
+
+	mu      sync.Mutex
+	pending map[string]bool
+	loaded  map[string]V
+	done    map[string]bool // загруженные ключи, включая ненайденные
+}
+
+func newLoader[V any](fetch func(keys []string) (map[string]V, error)) *loader[V] {
+	return &loader[V]{fetch: fetch, pending: map[string]bool{}, loaded: map[string]V{}, done: map[string]bool{}}
+}
+
+// Prime добавляет ключи в следующую пачку
+func (l *loader[V]) Prime(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if !l.done[key] {
+			l.pending[key] = true
+		}
+	}
+}
+
+// Set кладёт уже известное значение, чтобы оно не загружалось повторно
+func (l *loader[V]) Set(key string, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.pending, key)
+	l.loaded[key], l.done[key] = value, true
+}
+
+// Load возвращает значение по ключу; false — такого нет
+func (l *loader[V]) Load(key string) (V, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.done[key] {
+		l.pending[key] = true
+		keys := make([]string, 0, len(l.pending))
+		for k := range l.pending {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		values, err := l.fetch(keys)
+		if err != nil {
+			var zero V
+			return zero, false, err
+		}
+		l.pending = map[string]bool{}
+		for _, k := range keys {
+			l.done[k] = true
+			if v, ok := values[k]; ok {
+				l.loaded[k] = v
+			}
+		}
+	}
+	value, ok := l.loaded[key]
+	return value, ok, nil
+}
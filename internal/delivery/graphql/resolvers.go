@@ -0,0 +1,209 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// Команды и связь User.team требуют team:read, пользователи, их ревью и PR — users:read
+
+type queryResolver struct{}
+
+func (q *queryResolver) Teams(ctx context.Context) ([]*teamResolver, error) {
+	r := requestFrom(ctx)
+	if err := r.check(schemas.PermTeamRead); err != nil {
+		return nil, err
+	}
+	teams, err := r.server.teamUsecase.ListTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*teamResolver, 0, len(teams))
+	for _, t := range teams {
+		r.teams.Set(t.Name, t)
+		r.addMembers(t)
+		result = append(result, &teamResolver{team: t})
+	}
+	return result, nil
+}
+
+func (q *queryResolver) Team(ctx context.Context, args struct{ Name string }) (*teamResolver, error) {
+	r := requestFrom(ctx)
+	if err := r.check(schemas.PermTeamRead); err != nil {
+		return nil, err
+	}
+	return loadTeam(r, args.Name)
+}
+
+func (q *queryResolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	r := requestFrom(ctx)
+	if err := r.check(schemas.PermUsersRead); err != nil {
+		return nil, err
+	}
+	return loadUser(r, string(args.ID))
+}
+
+func (q *queryResolver) PullRequest(ctx context.Context, args struct{ ID graphql.ID }) (*prResolver, error) {
+	r := requestFrom(ctx)
+	if err := r.check(schemas.PermUsersRead); err != nil {
+		return nil, err
+	}
+	pr, ok, err := r.prs.Load(string(args.ID))
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &prResolver{pr: pr}, nil
+}
+
+func loadTeam(r *request, name string) (*teamResolver, error) {
+	t, ok, err := r.teams.Load(name)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &teamResolver{team: t}, nil
+}
+
+func loadUser(r *request, id string) (*userResolver, error) {
+	u, ok, err := r.users.Load(id)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &userResolver{user: u}, nil
+}
+
+type teamResolver struct {
+	team schemas.Team
+}
+
+func (t *teamResolver) Name() string {
+	return t.team.Name
+}
+
+func (t *teamResolver) ReviewersCount() int32 {
+	return int32(t.team.ReviewersCount)
+}
+
+func (t *teamResolver) Members() []*userResolver {
+	result := make([]*userResolver, 0, len(t.team.Members))
+	for _, m := range t.team.Members {
+		result = append(result, &userResolver{user: m})
+	}
+	return result
+}
+
+type userResolver struct {
+	user schemas.User
+}
+
+func (u *userResolver) ID() graphql.ID {
+	return graphql.ID(u.user.ID)
+}
+
+func (u *userResolver) Username() string {
+	return u.user.Username
+}
+
+func (u *userResolver) TeamName() string {
+	return u.user.TeamName
+}
+
+func (u *userResolver) IsActive() bool {
+	return u.user.IsActive
+}
+
+func (u *userResolver) Team(ctx context.Context) (*teamResolver, error) {
+	r := requestFrom(ctx)
+	if err := r.check(schemas.PermTeamRead); err != nil {
+		return nil, err
+	}
+	if u.user.TeamName == "" {
+		return nil, nil
+	}
+	return loadTeam(r, u.user.TeamName)
+}
+
+func (u *userResolver) Reviews(ctx context.Context, args struct{ Status *string }) ([]*prResolver, error) {
+	r := requestFrom(ctx)
+	if err := r.check(schemas.PermUsersRead); err != nil {
+		return nil, err
+	}
+	shorts, _, err := r.reviews.Load(u.user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, short := range shorts {
+		if args.Status == nil || short.Status == *args.Status {
+			ids = append(ids, short.ID)
+		}
+	}
+	r.prs.Prime(ids...)
+	result := make([]*prResolver, 0, len(ids))
+	for _, id := range ids {
+		pr, ok, err := r.prs.Load(id)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, &prResolver{pr: pr})
+		}
+	}
+	return result, nil
+}
+
+type prResolver struct {
+	pr schemas.PullRequest
+}
+
+func (p *prResolver) ID() graphql.ID {
+	return graphql.ID(p.pr.ID)
+}
+
+func (p *prResolver) Name() string {
+	return p.pr.Name
+}
+
+func (p *prResolver) Status() string {
+	return p.pr.Status
+}
+
+func (p *prResolver) CreatedAt() *string {
+	return formatTime(p.pr.CreatedAt)
+}
+
+func (p *prResolver) MergedAt() *string {
+	return formatTime(p.pr.MergedAt)
+}
+
+func (p *prResolver) Author(ctx context.Context) (*userResolver, error) {
+	return loadUser(requestFrom(ctx), p.pr.AuthorID)
+}
+
+func (p *prResolver) Reviewers(ctx context.Context) ([]*userResolver, error) {
+	r := requestFrom(ctx)
+	r.users.Prime(p.pr.AssignedReviewers...)
+	result := make([]*userResolver, 0, len(p.pr.AssignedReviewers))
+	for _, id := range p.pr.AssignedReviewers {
+		u, err := loadUser(r, id)
+		if err != nil {
+			return nil, err
+		}
+		if u != nil {
+			result = append(result, u)
+		}
+	}
+	return result, nil
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339)
+	return &s
+}
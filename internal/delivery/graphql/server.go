@@ -0,0 +1,163 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"sync"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/usecase/pr"
+	"ReviewAssigner/internal/usecase/team"
+	"ReviewAssigner/internal/usecase/user"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// maxDepth ограничивает вложенность запроса: связи циклические (User.team.members.team...)
+const maxDepth = 10
+
+// Authorizer проверяет, есть ли у principal право на действие (authz.Usecase)
+type Authorizer interface {
+	Allowed(ctx context.Context, p *schemas.Principal, perm string) (bool, error)
+}
+
+// Server выполняет GraphQL-запросы только на чтение поверх тех же usecase, что и HTTP API
+type Server struct {
+	schema      *graphql.Schema
+	teamUsecase *team.Usecase
+	userUsecase *user.Usecase
+	prUsecase   *pr.Usecase
+	authz       Authorizer
+}
+
+func NewServer(teamUsecase *team.Usecase, userUsecase *user.Usecase, prUsecase *pr.Usecase, authz Authorizer) *Server {
+	s := &Server{teamUsecase: teamUsecase, userUsecase: userUsecase, prUsecase: prUsecase, authz: authz}
+	s.schema = graphql.MustParseSchema(schemaSDL, &queryResolver{}, graphql.MaxDepth(maxDepth))
+	return s
+}
+
+// Exec выполняет запрос от имени principal; права проверяются по мере обхода полей
+func (s *Server) Exec(ctx context.Context, principal *schemas.Principal, query, operationName string, variables map[string]interface{}) *graphql.Response {
+	return s.schema.Exec(context.WithValue(ctx, requestKey{}, s.newRequest(ctx, principal)), query, operationName, variables)
+}
+
+type requestKey struct{}
+
+// request — состояние одного запроса: principal, кэш проверок прав и загрузчики.
+// Загрузчики выполняются с контекстом запроса, поэтому отмена запроса прерывает и их выборки.
+type request struct {
+	ctx       context.Context
+	server    *Server
+	principal *schemas.Principal
+
+	mu      sync.Mutex
+	allowed map[string]bool
+
+	users   *loader[schemas.User]
+	teams   *loader[schemas.Team]
+	reviews *loader[[]schemas.PullRequestShort] // по ID ревьювера
+	prs     *loader[schemas.PullRequest]
+}
+
+func (s *Server) newRequest(ctx context.Context, principal *schemas.Principal) *request {
+	r := &request{ctx: ctx, server: s, principal: principal, allowed: map[string]bool{}}
+
+	r.users = newLoader(func(ids []string) (map[string]schemas.User, error) {
+		users, err := s.userUsecase.GetUsers(r.ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]schemas.User, len(users))
+		for _, u := range users {
+			result[u.ID] = u
+		}
+		return result, nil
+	})
+
+	r.teams = newLoader(func(names []string) (map[string]schemas.Team, error) {
+		teams, err := s.teamUsecase.GetTeams(r.ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]schemas.Team, len(teams))
+		for _, t := range teams {
+			result[t.Name] = t
+			r.addMembers(t)
+		}
+		return result, nil
+	})
+
+	r.reviews = newLoader(func(ids []string) (map[string][]schemas.PullRequestShort, error) {
+		reviews, err := s.userUsecase.GetReviews(r.ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, prs := range reviews {
+			for _, short := range prs {
+				r.prs.Prime(short.ID)
+			}
+		}
+		return reviews, nil
+	})
+
+	r.prs = newLoader(func(ids []string) (map[string]schemas.PullRequest, error) {
+		prs, err := s.prUsecase.GetPRs(r.ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]schemas.PullRequest, len(prs))
+		for _, p := range prs {
+			result[p.ID] = p
+			r.users.Prime(p.AuthorID)
+			r.users.Prime(p.AssignedReviewers...)
+		}
+		return result, nil
+	})
+	return r
+}
+
+// addMembers кэширует участников команды и готовит загрузку их ревью одной пачкой
+func (r *request) addMembers(t schemas.Team) {
+	for _, m := range t.Members {
+		r.users.Set(m.ID, m)
+		r.reviews.Prime(m.ID)
+	}
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// check возвращает ошибку с extensions.code = FORBIDDEN, если у principal нет права perm
+func (r *request) check(perm string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	allowed, ok := r.allowed[perm]
+	if !ok {
+		var err error
+		if allowed, err = r.server.authz.Allowed(r.ctx, r.principal, perm); err != nil {
+			return err
+		}
+		r.allowed[perm] = allowed
+	}
+	if !allowed {
+		return &accessError{perm: perm}
+	}
+	return nil
+}
+
+type accessError struct {
+	perm string
+}
+
+func (e *accessError) Error() string {
+	return "insufficient permissions: " + e.perm
+}
+
+func (e *accessError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": "FORBIDDEN"}
+}
@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"net/http"
+
+	"ReviewAssigner/internal/pkg/errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain — домен в ErrorInfo; Reason совпадает с кодом ошибки HTTP API
+const errorDomain = "review-assigner"
+
+// errorCodes — gRPC-коды для ошибок, которым не подходит код по HTTP-статусу
+var errorCodes = map[string]codes.Code{
+	errors.ErrTeamExists.Code:  codes.AlreadyExists,
+	errors.ErrPRExists.Code:    codes.AlreadyExists,
+	errors.ErrPRMerged.Code:    codes.FailedPrecondition,
+	errors.ErrPRClosed.Code:    codes.FailedPrecondition,
+	errors.ErrNotAssigned.Code: codes.FailedPrecondition,
+	errors.ErrNoCandidate.Code: codes.FailedPrecondition,
+}
+
+// httpCodes — gRPC-код по HTTP-статусу типизированной ошибки
+var httpCodes = map[int]codes.Code{
+	http.StatusBadRequest:         codes.InvalidArgument,
+	http.StatusUnauthorized:       codes.Unauthenticated,
+	http.StatusForbidden:          codes.PermissionDenied,
+	http.StatusNotFound:           codes.NotFound,
+	http.StatusConflict:           codes.Aborted,
+	http.StatusPreconditionFailed: codes.FailedPrecondition,
+	http.StatusLocked:             codes.FailedPrecondition,
+	http.StatusTooManyRequests:    codes.ResourceExhausted,
+	http.StatusGatewayTimeout:     codes.DeadlineExceeded,
+}
+
+// toStatus переводит доменную ошибку в gRPC-статус с ErrorInfo{Reason: код ошибки}
+func toStatus(err error) error {
+	apiErr := errors.From(err)
+	code, ok := errorCodes[apiErr.Code]
+	if !ok {
+		if code, ok = httpCodes[apiErr.Status]; !ok {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+	st := status.New(code, apiErr.Message)
+	if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: apiErr.Code, Domain: errorDomain}); detailErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func invalidArgument(message string) error {
+	return status.Error(codes.InvalidArgument, message)
+}
@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"ReviewAssigner/internal/delivery/grpc/pb"
+	"ReviewAssigner/internal/delivery/middleware"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/jwt"
+	"ReviewAssigner/internal/usecase/apikey"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodPermissions — право, необходимое для метода; методы без записи закрыты для всех.
+// API-ключу метод доступен, если у ключа есть скоуп с тем же именем.
+var methodPermissions = map[string]string{
+	pb.TeamService_CreateTeam_FullMethodName:               schemas.PermTeamCreate,
+	pb.TeamService_GetTeam_FullMethodName:                  schemas.PermTeamRead,
+	pb.UserService_SetIsActive_FullMethodName:              schemas.PermUsersSetActive,
+	pb.UserService_GetReview_FullMethodName:                schemas.PermUsersRead,
+	pb.PullRequestService_CreatePullRequest_FullMethodName: schemas.PermPRCreate,
+	pb.PullRequestService_MergePullRequest_FullMethodName:  schemas.PermPRMerge,
+	pb.PullRequestService_ReassignReviewer_FullMethodName:  schemas.PermPRReassign,
+	pb.PullRequestService_GetStats_FullMethodName:          schemas.PermStatsRead,
+}
+
+type principalKey struct{}
+
+// principalFromContext возвращает principal, сохранённый AuthInterceptor
+func principalFromContext(ctx context.Context) *schemas.Principal {
+	p, _ := ctx.Value(principalKey{}).(*schemas.Principal)
+	if p == nil {
+		return &schemas.Principal{}
+	}
+	return p
+}
+
+// Authenticator проверяет учётные данные из metadata так же, как AuthMiddleware HTTP API
+type Authenticator struct {
+	tokens   *jwt.Manager
+	checker  middleware.AccessChecker
+	apiKeys  middleware.APIKeyAuthenticator
+	external middleware.BearerVerifier // nil, если SSO не настроен
+	authz    middleware.Authorizer
+}
+
+func NewAuthenticator(tokens *jwt.Manager, checker middleware.AccessChecker, apiKeys middleware.APIKeyAuthenticator,
+	external middleware.BearerVerifier, authz middleware.Authorizer) *Authenticator {
+	return &Authenticator{tokens: tokens, checker: checker, apiKeys: apiKeys, external: external, authz: authz}
+}
+
+// UnaryInterceptor аутентифицирует вызов (JWT или API-ключ) и проверяет право на метод по methodPermissions.
+// Права над конкретной командой или PR проверяются в самих методах.
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		p, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		perm, ok := methodPermissions[info.FullMethod]
+		allowed := false
+		if ok {
+			if allowed, err = a.authz.Allowed(ctx, p, perm); err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+		if !allowed {
+			return nil, status.Error(codes.PermissionDenied, "insufficient permissions for this operation")
+		}
+		return handler(context.WithValue(ctx, principalKey{}, p), req)
+	}
+}
+
+func (a *Authenticator) authenticate(ctx context.Context) (*schemas.Principal, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authHeader := first(md, "authorization")
+
+	rawKey := first(md, "x-api-key")
+	if rawKey == "" && apikey.IsAPIKey(strings.TrimPrefix(authHeader, "Bearer ")) {
+		rawKey = strings.TrimPrefix(authHeader, "Bearer ")
+	}
+	if rawKey != "" {
+		key, err := a.apiKeys.Authenticate(ctx, rawKey)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid, expired or revoked API key")
+		}
+		return &schemas.Principal{UserID: "api-key:" + key.ID, APIKey: key}, nil
+	}
+
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid authorization metadata")
+	}
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	claims, err := a.tokens.ValidateToken(tokenString)
+	if err != nil && a.external != nil {
+		claims, err = a.external.VerifyBearer(ctx, tokenString)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+	if err := a.checker.CheckAccess(ctx, claims); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "token has been revoked")
+	}
+	return &schemas.Principal{Login: claims.Login, UserID: claims.UserID, Role: claims.Role}, nil
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,234 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"ReviewAssigner/internal/delivery/grpc/pb"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/usecase/authz"
+	"ReviewAssigner/internal/usecase/pr"
+	"ReviewAssigner/internal/usecase/team"
+	"ReviewAssigner/internal/usecase/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NewServer создаёт gRPC-сервер с сервисами команд, пользователей и PR поверх тех же usecase, что и HTTP API
+func NewServer(auth *Authenticator, teamUsecase *team.Usecase, userUsecase *user.Usecase, prUsecase *pr.Usecase, authzUsecase *authz.Usecase) *grpc.Server {
+	s := grpc.NewServer(grpc.UnaryInterceptor(auth.UnaryInterceptor()))
+	pb.RegisterTeamServiceServer(s, &teamService{teamUsecase: teamUsecase, authzUsecase: authzUsecase})
+	pb.RegisterUserServiceServer(s, &userService{userUsecase: userUsecase, authzUsecase: authzUsecase})
+	pb.RegisterPullRequestServiceServer(s, &prService{prUsecase: prUsecase, authzUsecase: authzUsecase})
+	return s
+}
+
+// authorize проверяет право на конкретный объект, как Handlers.authorize в HTTP API
+func authorize(ctx context.Context, authzUsecase *authz.Usecase, perm string, res schemas.AuthzResource) error {
+	if err := authzUsecase.Authorize(ctx, principalFromContext(ctx), perm, res); err != nil {
+		return toStatus(err)
+	}
+	return nil
+}
+
+type teamService struct {
+	pb.UnimplementedTeamServiceServer
+	teamUsecase  *team.Usecase
+	authzUsecase *authz.Usecase
+}
+
+func (s *teamService) CreateTeam(ctx context.Context, req *pb.CreateTeamRequest) (*pb.CreateTeamResponse, error) {
+	if req.GetTeam().GetTeamName() == "" {
+		return nil, invalidArgument("team.team_name is required")
+	}
+	created, err := s.teamUsecase.CreateTeam(ctx, teamFromProto(req.GetTeam()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CreateTeamResponse{Team: teamToProto(created)}, nil
+}
+
+func (s *teamService) GetTeam(ctx context.Context, req *pb.GetTeamRequest) (*pb.GetTeamResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, invalidArgument("team_name is required")
+	}
+	found, err := s.teamUsecase.GetTeam(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.GetTeamResponse{Team: teamToProto(found)}, nil
+}
+
+type userService struct {
+	pb.UnimplementedUserServiceServer
+	userUsecase  *user.Usecase
+	authzUsecase *authz.Usecase
+}
+
+func (s *userService) SetIsActive(ctx context.Context, req *pb.SetIsActiveRequest) (*pb.SetIsActiveResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument("user_id is required")
+	}
+	if err := authorize(ctx, s.authzUsecase, schemas.PermUsersSetActive, schemas.AuthzResource{TargetUserID: req.GetUserId()}); err != nil {
+		return nil, err
+	}
+	updated, err := s.userUsecase.SetIsActive(ctx, req.GetUserId(), req.GetIsActive())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.SetIsActiveResponse{User: userToProto(*updated)}, nil
+}
+
+func (s *userService) GetReview(ctx context.Context, req *pb.GetReviewRequest) (*pb.GetReviewResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, invalidArgument("user_id is required")
+	}
+	found, prs, err := s.userUsecase.GetUserReviews(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.GetReviewResponse{UserId: found.ID}
+	for _, short := range prs {
+		resp.PullRequests = append(resp.PullRequests, &pb.PullRequestShort{
+			PullRequestId:   short.ID,
+			PullRequestName: short.Name,
+			AuthorId:        short.AuthorID,
+			Status:          short.Status,
+			CreatedAt:       timestamp(short.CreatedAt),
+		})
+	}
+	return resp, nil
+}
+
+type prService struct {
+	pb.UnimplementedPullRequestServiceServer
+	prUsecase    *pr.Usecase
+	authzUsecase *authz.Usecase
+}
+
+func (s *prService) CreatePullRequest(ctx context.Context, req *pb.CreatePullRequestRequest) (*pb.CreatePullRequestResponse, error) {
+	if req.GetPullRequestId() == "" || req.GetPullRequestName() == "" || req.GetAuthorId() == "" {
+		return nil, invalidArgument("pull_request_id, pull_request_name and author_id are required")
+	}
+	if err := authorize(ctx, s.authzUsecase, schemas.PermPRCreate, schemas.AuthzResource{PRID: req.GetPullRequestId(), AuthorID: req.GetAuthorId()}); err != nil {
+		return nil, err
+	}
+	created, err := s.prUsecase.CreatePR(ctx, req.GetPullRequestId(), req.GetPullRequestName(), req.GetAuthorId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	setETag(ctx, created)
+	return &pb.CreatePullRequestResponse{Pr: prToProto(created)}, nil
+}
+
+func (s *prService) MergePullRequest(ctx context.Context, req *pb.MergePullRequestRequest) (*pb.MergePullRequestResponse, error) {
+	if req.GetPullRequestId() == "" {
+		return nil, invalidArgument("pull_request_id is required")
+	}
+	if err := authorize(ctx, s.authzUsecase, schemas.PermPRMerge, schemas.AuthzResource{PRID: req.GetPullRequestId()}); err != nil {
+		return nil, err
+	}
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := s.prUsecase.MergePR(ctx, req.GetPullRequestId(), version)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	setETag(ctx, merged)
+	return &pb.MergePullRequestResponse{Pr: prToProto(merged)}, nil
+}
+
+func (s *prService) ReassignReviewer(ctx context.Context, req *pb.ReassignReviewerRequest) (*pb.ReassignReviewerResponse, error) {
+	if req.GetPullRequestId() == "" || req.GetOldUserId() == "" {
+		return nil, invalidArgument("pull_request_id and old_user_id are required")
+	}
+	if err := authorize(ctx, s.authzUsecase, schemas.PermPRReassign, schemas.AuthzResource{PRID: req.GetPullRequestId(), TargetUserID: req.GetOldUserId()}); err != nil {
+		return nil, err
+	}
+	version, err := ifMatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reassigned, newReviewer, err := s.prUsecase.ReassignPR(ctx, req.GetPullRequestId(), req.GetOldUserId(), version)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	setETag(ctx, reassigned)
+	return &pb.ReassignReviewerResponse{Pr: prToProto(reassigned), ReplacedBy: newReviewer}, nil
+}
+
+func (s *prService) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
+	userStats, prStats, err := s.prUsecase.GetStats(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.GetStatsResponse{UserAssignments: counts(userStats), PrAssignments: counts(prStats)}, nil
+}
+
+// ifMatch читает ожидаемую версию PR из метаданных if-match, как If-Match в HTTP API (0 — без проверки)
+func ifMatch(ctx context.Context) (int, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	version, ok := schemas.ParseIfMatch(first(md, "if-match"))
+	if !ok {
+		return 0, invalidArgument("if-match must be an ETag returned for the pull request")
+	}
+	return version, nil
+}
+
+// setETag отдаёт версию PR в метаданных ответа etag
+func setETag(ctx context.Context, pr *schemas.PullRequest) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs("etag", pr.ETag()))
+}
+
+// === Преобразование schemas <-> protobuf ===
+
+func teamFromProto(t *pb.Team) *schemas.Team {
+	result := &schemas.Team{Name: t.GetTeamName(), ReviewersCount: int(t.GetReviewersCount()), Members: []schemas.User{}}
+	for _, m := range t.GetMembers() {
+		result.Members = append(result.Members, schemas.User{ID: m.GetUserId(), Username: m.GetUsername(), TeamName: t.GetTeamName(), IsActive: m.GetIsActive()})
+	}
+	return result
+}
+
+func teamToProto(t *schemas.Team) *pb.Team {
+	result := &pb.Team{TeamName: t.Name, ReviewersCount: int32(t.ReviewersCount)}
+	for _, m := range t.Members {
+		result.Members = append(result.Members, userToProto(m))
+	}
+	return result
+}
+
+func userToProto(u schemas.User) *pb.User {
+	return &pb.User{UserId: u.ID, Username: u.Username, TeamName: u.TeamName, IsActive: u.IsActive}
+}
+
+func prToProto(p *schemas.PullRequest) *pb.PullRequest {
+	return &pb.PullRequest{
+		PullRequestId:     p.ID,
+		PullRequestName:   p.Name,
+		AuthorId:          p.AuthorID,
+		Status:            p.Status,
+		AssignedReviewers: p.AssignedReviewers,
+		CreatedAt:         timestamp(p.CreatedAt),
+		MergedAt:          timestamp(p.MergedAt),
+	}
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func counts(m map[string]int) map[string]int32 {
+	result := make(map[string]int32, len(m))
+	for k, v := range m {
+		result[k] = int32(v)
+	}
+	return result
+}
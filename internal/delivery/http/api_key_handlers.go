@@ -0,0 +1,44 @@
+package http
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAPIKey выпускает API-ключ (только admin). Открытое значение ключа возвращается один раз.
+func (h *Handlers) CreateAPIKey(c *gin.Context) {
+	var req struct {
+		Name      string     `json:"name" binding:"required"`
+		Scopes    []string   `json:"scopes" binding:"required"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+
+	key, raw, err := h.apiKeyUsecase.Create(c.Request.Context(), req.Name, req.Scopes, req.ExpiresAt, c.GetString("login"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(201, gin.H{"api_key": key, "key": raw})
+}
+
+func (h *Handlers) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyUsecase.List(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"api_keys": keys})
+}
+
+func (h *Handlers) RevokeAPIKey(c *gin.Context) {
+	if err := h.apiKeyUsecase.Revoke(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAccount — создание учётной записи (только admin)
+func (h *Handlers) CreateAccount(c *gin.Context) {
+	var req struct {
+		Login    string `json:"login" binding:"required"`
+		Password string `json:"password" binding:"required"`
+		Role     string `json:"role"`
+		UserID   string `json:"user_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	if req.Role == "" {
+		req.Role = schemas.RoleMember
+	}
+
+	account, err := h.authUsecase.CreateAccount(c.Request.Context(), req.Login, req.Password, req.Role, req.UserID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(201, gin.H{"account": account})
+}
+
+// SetPassword — сброс пароля администратором
+func (h *Handlers) SetPassword(c *gin.Context) {
+	var req struct {
+		Login    string `json:"login" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	if err := h.authUsecase.SetPassword(c.Request.Context(), req.Login, req.Password); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+// ChangePassword — смена собственного пароля (логин берётся из токена)
+func (h *Handlers) ChangePassword(c *gin.Context) {
+	var req struct {
+		OldPassword string `json:"old_password" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	if err := h.authUsecase.ChangePassword(c.Request.Context(), c.GetString("login"), req.OldPassword, req.NewPassword); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+// Refresh — обмен refresh-токена на новую пару токенов (публичный)
+func (h *Handlers) Refresh(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	pair, err := h.sessionUsecase.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, pair)
+}
+
+// Logout отзывает текущий access-токен и переданный refresh-токен
+func (h *Handlers) Logout(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			handleError(c, bindError(err))
+			return
+		}
+	}
+	claims := c.MustGet("claims").(*jwt.Claims)
+	if err := h.sessionUsecase.Logout(c.Request.Context(), claims, req.RefreshToken); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+// RevokeTokens — отзыв всех токенов учётки (только admin)
+func (h *Handlers) RevokeTokens(c *gin.Context) {
+	var req struct {
+		Login string `json:"login" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	if err := h.sessionUsecase.RevokeAll(c.Request.Context(), req.Login); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
@@ -0,0 +1,14 @@
+package http
+
+import "github.com/gin-gonic/gin"
+
+// NewEngine — gin.Default, доверяющий X-Forwarded-For и X-Real-IP только от перечисленных прокси
+// (IP или CIDR). Без списка адрес клиента — адрес соединения: иначе клиент сам выбирает себе IP
+// и обходит лимиты по IP.
+func NewEngine(trustedProxies []string) (*gin.Engine, error) {
+	r := gin.Default()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
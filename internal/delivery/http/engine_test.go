@@ -0,0 +1,53 @@
+package http
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ReviewAssigner/internal/delivery/middleware"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/repository/inmemory"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLimitedEngine — движок с одним входом в минуту на IP и заглушкой /auth/login
+func newLimitedEngine(t *testing.T, trustedProxies []string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r, err := NewEngine(trustedProxies)
+	require.NoError(t, err)
+	limits := map[string]schemas.RateLimit{schemas.RateLimitGroupAuth: {Rate: 1.0 / 60, Burst: 1}}
+	r.POST("/auth/login", middleware.RateLimitMiddleware(inmemory.NewRateLimitRepository(), limits),
+		func(c *gin.Context) { c.Status(nethttp.StatusNoContent) })
+	return r
+}
+
+func loginFrom(r *gin.Engine, remoteAddr, forwardedFor string) int {
+	req := httptest.NewRequest(nethttp.MethodPost, "/auth/login", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("X-Forwarded-For", forwardedFor)
+	req.Header.Set("X-Real-IP", forwardedFor)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestNewEngine_SpoofedForwardedForDoesNotResetLimit(t *testing.T) {
+	r := newLimitedEngine(t, nil)
+
+	assert.Equal(t, nethttp.StatusNoContent, loginFrom(r, "203.0.113.7:40000", "198.51.100.1"))
+	// Новый X-Forwarded-For от того же соединения — тот же клиент и та же корзина
+	assert.Equal(t, nethttp.StatusTooManyRequests, loginFrom(r, "203.0.113.7:40001", "198.51.100.2"))
+}
+
+func TestNewEngine_TrustedProxyForwardsClientIP(t *testing.T) {
+	r := newLimitedEngine(t, []string{"10.0.0.0/8"})
+
+	assert.Equal(t, nethttp.StatusNoContent, loginFrom(r, "10.0.0.5:40000", "198.51.100.1"))
+	assert.Equal(t, nethttp.StatusNoContent, loginFrom(r, "10.0.0.5:40001", "198.51.100.2"))
+	assert.Equal(t, nethttp.StatusTooManyRequests, loginFrom(r, "10.0.0.6:40002", "198.51.100.1"))
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"ReviewAssigner/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Ошибки полей называют поля так же, как они выглядят в JSON
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// handleError пишет ошибку в едином формате {"error": {"code", "message", "details", "fields"}};
+// статус и код берутся из типизированной ошибки в цепочке, остальные ошибки — 500 INTERNAL_ERROR
+func handleError(c *gin.Context, err error) {
+	apiErr := errors.From(err)
+	if apiErr.Status >= 500 {
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+	c.JSON(apiErr.Status, gin.H{"error": apiErr})
+}
+
+// bindError переводит ошибку разбора тела запроса в BAD_REQUEST с перечнем неверных полей
+func bindError(err error) error {
+	var validationErrs validator.ValidationErrors
+	if stderrors.As(err, &validationErrs) {
+		fields := make([]errors.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, errors.FieldError{Field: fieldPath(fe.Namespace()), Message: fieldMessage(fe)})
+		}
+		return errors.Validation(fields...).Wrap(err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if stderrors.As(err, &typeErr) && typeErr.Field != "" {
+		return errors.Validation(errors.FieldError{Field: typeErr.Field, Message: "must be " + typeErr.Type.String()}).Wrap(err)
+	}
+	return badRequest(err)
+}
+
+// badRequest — BAD_REQUEST с текстом исходной ошибки (формат файла, параметры запроса);
+// тело больше допустимого — PAYLOAD_TOO_LARGE
+func badRequest(err error) error {
+	var tooLarge *http.MaxBytesError
+	if stderrors.As(err, &tooLarge) {
+		return errors.ErrPayloadTooLarge.Wrap(err)
+	}
+	return errors.ErrBadRequest.WithMessage(err.Error()).Wrap(err)
+}
+
+// fieldPath отрезает имя структуры запроса: "req.members[0].user_id" -> "members[0].user_id"
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	default:
+		return "failed on " + fe.Tag() + " rule"
+	}
+}
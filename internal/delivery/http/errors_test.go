@@ -0,0 +1,117 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/delivery/middleware"
+	"ReviewAssigner/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type errorBody struct {
+	Error struct {
+		Code    string              `json:"code"`
+		Message string              `json:"message"`
+		Fields  []errors.FieldError `json:"fields"`
+	} `json:"error"`
+}
+
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) errorBody {
+	var body errorBody
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	return body
+}
+
+func TestHandleError_WrappedErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("load pr: %w", errors.ErrNotFound), 404, "NOT_FOUND"},
+		{errors.ErrPRMerged.Wrap(stderrors.New("db")), 409, "PR_MERGED"},
+		{errors.ErrInvalidRoster.WithMessage("team_name is required"), 400, "INVALID_ROSTER"},
+		{stderrors.New("connection refused"), 500, "INTERNAL_ERROR"},
+		{fmt.Errorf("select users: %w", context.DeadlineExceeded), 504, "TIMEOUT"},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/", nil)
+
+		handleError(c, tc.err)
+		assert.Equal(t, tc.status, w.Code, tc.err.Error())
+		assert.Equal(t, tc.code, decodeError(t, w).Error.Code)
+	}
+}
+
+func TestRequestDeadline_ReachesRepository(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationOff, false)
+	token := loginAdmin(t, r)
+
+	// Истёкший дедлайн клиента доходит до единицы работы, PR не создаётся
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	req := httptest.NewRequest("POST", "/api/v1/pullRequest/create",
+		bytes.NewReader([]byte(`{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`))).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 504, w.Code, w.Body.String())
+	assert.Equal(t, "TIMEOUT", decodeError(t, w).Error.Code)
+
+	w = doRequest(r, "POST", "/api/v1/pullRequest/create", token, map[string]string{
+		"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1",
+	})
+	assert.Equal(t, 201, w.Code, w.Body.String())
+}
+
+func TestBindError_FieldDetails(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationOff, false)
+	w := doRequest(r, "POST", "/api/v1/auth/login", "", map[string]string{"user_id": "admin"})
+	assert.Equal(t, 400, w.Code)
+
+	body := decodeError(t, w)
+	assert.Equal(t, "BAD_REQUEST", body.Error.Code)
+	assert.Equal(t, []errors.FieldError{{Field: "password", Message: "is required"}}, body.Error.Fields)
+
+	w = doRequest(r, "POST", "/api/v1/auth/login", "", map[string]interface{}{"user_id": 1, "password": "x"})
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, "user_id", decodeError(t, w).Error.Fields[0].Field)
+}
+
+func TestDeprecatedAliases(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationOff, false)
+	login := map[string]string{"user_id": "admin", "password": "admin-password"}
+
+	w := doRequest(r, "POST", "/api/v1/auth/login", "", login)
+	assert.Equal(t, 200, w.Code)
+	assert.Empty(t, w.Header().Get("Deprecation"))
+
+	// Старый путь работает, но помечен устаревшим и ссылается на /api/v1
+	w = doRequest(r, "POST", "/auth/login", "", login)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+	assert.Equal(t, `</api/v1/auth/login>; rel="successor-version"`, w.Header().Get("Link"))
+
+	var pair struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pair))
+	// Права по старому пути проверяются так же
+	w = doRequest(r, "GET", "/team/get?team_name=backend", pair.Token, nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"ReviewAssigner/internal/delivery/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func doIfMatch(r *gin.Engine, path, token, etag string, body interface{}) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPullRequest_ETagAndIfMatch(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationAll, false)
+	token := loginAdmin(t, r)
+	// u3 на время создания PR неактивен, затем становится кандидатом на замену
+	w := doRequest(r, "POST", "/api/v1/users/setIsActive", token, map[string]interface{}{"user_id": "u3", "is_active": false})
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "POST", "/api/v1/pullRequest/create", token, map[string]string{
+		"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1",
+	})
+	require.Equal(t, 201, w.Code, w.Body.String())
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+
+	var created struct {
+		PR struct {
+			Reviewers []string `json:"assigned_reviewers"`
+			Version   int      `json:"version"`
+		} `json:"pr"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.Equal(t, []string{"u2"}, created.PR.Reviewers)
+	assert.Equal(t, 1, created.PR.Version)
+
+	w = doRequest(r, "POST", "/api/v1/users/setIsActive", token, map[string]interface{}{"user_id": "u3", "is_active": true})
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	reassign := map[string]string{"pull_request_id": "pr-1", "old_user_id": "u2"}
+	w = doIfMatch(r, "/api/v1/pullRequest/reassign", token, `"1"`, reassign)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+	// Merge по устаревшей версии отклоняется, по актуальной — проходит
+	merge := map[string]string{"pull_request_id": "pr-1"}
+	w = doIfMatch(r, "/api/v1/pullRequest/merge", token, `"1"`, merge)
+	assert.Equal(t, 412, w.Code)
+	assert.Equal(t, "PRECONDITION_FAILED", errorCode(t, w))
+
+	w = doIfMatch(r, "/api/v1/pullRequest/merge", token, `W/"2"`, merge)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+	// Без If-Match версия не сверяется; повторный merge идемпотентен
+	w = doIfMatch(r, "/api/v1/pullRequest/merge", token, "", merge)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doIfMatch(r, "/api/v1/pullRequest/merge", token, "latest", merge)
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, "If-Match", decodeError(t, w).Error.Fields[0].Field)
+}
+
+func TestPullRequest_GetReturnsETagForIfMatch(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationAll, false)
+	token := loginAdmin(t, r)
+	w := doRequest(r, "POST", "/api/v1/pullRequest/create", token, map[string]string{
+		"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1",
+	})
+	require.Equal(t, 201, w.Code, w.Body.String())
+
+	// Клиент, не видевший ответа на создание, узнаёт версию чтением, а не пробным изменением
+	w = doRequest(r, "GET", "/api/v1/pullRequest/get?pull_request_id=pr-1", token, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	var got struct {
+		PR struct {
+			ID      string `json:"pull_request_id"`
+			Version int    `json:"version"`
+		} `json:"pr"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "pr-1", got.PR.ID)
+	assert.Equal(t, 1, got.PR.Version)
+
+	w = doIfMatch(r, "/api/v1/pullRequest/merge", token, w.Header().Get("ETag"), map[string]string{"pull_request_id": "pr-1"})
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "GET", "/pullRequest/get?pull_request_id=pr-1", token, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	assert.NotEmpty(t, w.Header().Get("Deprecation"))
+
+	w = doRequest(r, "GET", "/api/v1/pullRequest/get?pull_request_id=pr-404", token, nil)
+	assert.Equal(t, 404, w.Code)
+	w = doRequest(r, "GET", "/api/v1/pullRequest/get", token, nil)
+	assert.Equal(t, 400, w.Code)
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"ReviewAssigner/internal/delivery/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQL — запросы на чтение команд, пользователей, их ревью и PR за один вызов.
+// Права проверяются по полям: ответ может содержать data вместе с errors (extensions.code = FORBIDDEN).
+func (h *Handlers) GraphQL(c *gin.Context) {
+	var req struct {
+		Query         string                 `json:"query" binding:"required"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	c.JSON(200, h.graphQL.Exec(c.Request.Context(), middleware.PrincipalFromContext(c), req.Query, req.OperationName, req.Variables))
+}
@@ -2,44 +2,253 @@
 package http
 
 import (
+	"sync"
+	"sync/atomic"
+
+	"ReviewAssigner/internal/delivery/graphql"
 	"ReviewAssigner/internal/domain/schemas"
 	"ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/usecase/apikey"
+	"ReviewAssigner/internal/usecase/auth"
+	"ReviewAssigner/internal/usecase/authz"
+	"ReviewAssigner/internal/usecase/digest"
+	"ReviewAssigner/internal/usecase/notifier"
+	"ReviewAssigner/internal/usecase/outbox"
 	"ReviewAssigner/internal/usecase/pr"
+	"ReviewAssigner/internal/usecase/reviewsync"
+	"ReviewAssigner/internal/usecase/roster"
+	"ReviewAssigner/internal/usecase/scmhook"
+	"ReviewAssigner/internal/usecase/session"
+	"ReviewAssigner/internal/usecase/sso"
+	"ReviewAssigner/internal/usecase/stream"
 	"ReviewAssigner/internal/usecase/team"
 	"ReviewAssigner/internal/usecase/user"
+	"ReviewAssigner/internal/usecase/webhook"
 	"ReviewAssigner/internal/delivery/middleware"
 	"ReviewAssigner/internal/pkg/jwt"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
 type Handlers struct {
-	teamUsecase *team.Usecase
-	userUsecase *user.Usecase
-	prUsecase   *pr.Usecase
+	teamUsecase     *team.Usecase
+	userUsecase     *user.Usecase
+	prUsecase       *pr.Usecase
+	rosterUsecase   *roster.Usecase
+	authUsecase     *auth.Usecase
+	sessionUsecase  *session.Usecase
+	apiKeyUsecase   *apikey.Usecase
+	authzUsecase    *authz.Usecase
+	ssoUsecase      *sso.Usecase // nil, если SSO не настроен
+	scmHookUsecase  *scmhook.Usecase
+	reviewSync      *reviewsync.Usecase // nil, если токены провайдеров не заданы
+	webhookUsecase  *webhook.Usecase
+	notifierUsecase *notifier.Usecase
+	digestUsecase   *digest.Usecase
+	outboxUsecase   *outbox.Usecase
+	streamUsecase   *stream.Usecase
+	graphQL         *graphql.Server
+	tokens          *jwt.Manager
+	upgrader        websocket.Upgrader // WebSocket живого потока; Origin сверяется со списком разрешённых
+
+	draining  atomic.Bool   // /ready отвечает 503: балансировщик перестаёт слать запросы
+	closing   chan struct{} // закрывается при остановке сервера: живые потоки событий завершаются
+	closeOnce sync.Once
 }
 
-func NewHandlers(teamUsecase *team.Usecase, userUsecase *user.Usecase, prUsecase *pr.Usecase) *Handlers {
+func NewHandlers(teamUsecase *team.Usecase, userUsecase *user.Usecase, prUsecase *pr.Usecase, rosterUsecase *roster.Usecase, authUsecase *auth.Usecase, sessionUsecase *session.Usecase, apiKeyUsecase *apikey.Usecase, authzUsecase *authz.Usecase, ssoUsecase *sso.Usecase, scmHookUsecase *scmhook.Usecase, reviewSync *reviewsync.Usecase, webhookUsecase *webhook.Usecase, notifierUsecase *notifier.Usecase, digestUsecase *digest.Usecase, outboxUsecase *outbox.Usecase, streamUsecase *stream.Usecase, graphQL *graphql.Server, tokens *jwt.Manager, streamOrigins []string) *Handlers {
 	return &Handlers{
-		teamUsecase: teamUsecase,
-		userUsecase: userUsecase,
-		prUsecase:   prUsecase,
+		teamUsecase:     teamUsecase,
+		userUsecase:     userUsecase,
+		prUsecase:       prUsecase,
+		rosterUsecase:   rosterUsecase,
+		authUsecase:     authUsecase,
+		sessionUsecase:  sessionUsecase,
+		apiKeyUsecase:   apiKeyUsecase,
+		authzUsecase:    authzUsecase,
+		ssoUsecase:      ssoUsecase,
+		scmHookUsecase:  scmHookUsecase,
+		reviewSync:      reviewSync,
+		webhookUsecase:  webhookUsecase,
+		notifierUsecase: notifierUsecase,
+		digestUsecase:   digestUsecase,
+		outboxUsecase:   outboxUsecase,
+		streamUsecase:   streamUsecase,
+		graphQL:         graphQL,
+		tokens:          tokens,
+		upgrader:        newUpgrader(streamOrigins),
+		closing:         make(chan struct{}),
 	}
 }
 
-func (h *Handlers) RegisterRoutes(r *gin.Engine) {
-	protected := r.Group("/")
-	protected.Use(middleware.AuthMiddleware())
-	{
+// Drain переводит /ready в 503; уже принятые и новые запросы обслуживаются как обычно
+func (h *Handlers) Drain() {
+	h.draining.Store(true)
+}
+
+// CloseStreams завершает живые потоки событий, которые иначе не дали бы серверу остановиться
+func (h *Handlers) CloseStreams() {
+	h.closeOnce.Do(func() { close(h.closing) })
+}
+
+// routeScopes — какой скоуп API-ключа открывает маршрут; остальные маршруты API-ключам закрыты
+var routeScopes = map[string]string{
+	"GET /team/get":              schemas.ScopeTeamRead,
+	"POST /users/setIsActive":    schemas.ScopeUsersWrite,
+	"GET /users/getReview":       schemas.ScopeUsersRead,
+	"GET /pullRequest/get":       schemas.ScopeUsersRead,
+	"POST /pullRequest/create":   schemas.ScopePRCreate,
+	"POST /pullRequest/merge":    schemas.ScopePRMerge,
+	"POST /pullRequest/reassign": schemas.ScopePRReassign,
+	"GET /stats":                 schemas.ScopeStatsRead,
+}
+
+// routePermissions — право, необходимое для маршрута (путь без /api/v1). Пустая строка — достаточно аутентификации.
+// Маршруты без записи закрыты для всех; права над конкретной командой или PR проверяются в хендлерах.
+var routePermissions = map[string]string{
+	"POST /team/add":                                schemas.PermTeamCreate,
+	"GET /team/get":                                 schemas.PermTeamRead,
+	"POST /team/members":                            schemas.PermTeamManage,
+	"POST /users/setIsActive":                       schemas.PermUsersSetActive,
+	"GET /users/getReview":                          schemas.PermUsersRead,
+	"GET /users/notifications":                      "",
+	"PUT /users/notifications":                      "",
+	"DELETE /users/notifications":                   "",
+	"GET /users/digest":                             "",
+	"PUT /users/digest":                             "",
+	"DELETE /users/digest":                          "",
+	"GET /users/digest/preview":                     "",
+	"GET /pullRequest/get":                          schemas.PermUsersRead,
+	"POST /pullRequest/create":                      schemas.PermPRCreate,
+	"POST /pullRequest/merge":                       schemas.PermPRMerge,
+	"POST /pullRequest/reassign":                    schemas.PermPRReassign,
+	"POST /pullRequest/decline":                     schemas.PermPRReassign,
+	"GET /stats":                                    schemas.PermStatsRead,
+	"GET /events/stream":                            schemas.PermStatsRead,
+	"POST /graphql":                                 "",
+	"POST /admin/import":                            schemas.PermAdminister,
+	"GET /admin/export":                             schemas.PermAdminister,
+	"POST /admin/sync":                              schemas.PermAdminister,
+	"POST /auth/accounts":                           schemas.PermAdminister,
+	"POST /auth/password/set":                       schemas.PermAdminister,
+	"POST /auth/password/change":                    "",
+	"POST /auth/logout":                             "",
+	"POST /auth/revoke":                             schemas.PermAdminister,
+	"POST /admin/api-keys":                          schemas.PermAdminister,
+	"GET /admin/api-keys":                           schemas.PermAdminister,
+	"DELETE /admin/api-keys/:id":                    schemas.PermAdminister,
+	"POST /admin/role-bindings":                     schemas.PermAdminister,
+	"GET /admin/role-bindings":                      schemas.PermAdminister,
+	"DELETE /admin/role-bindings":                   schemas.PermAdminister,
+	"POST /admin/scm-identities":                    schemas.PermAdminister,
+	"GET /admin/scm-identities":                     schemas.PermAdminister,
+	"DELETE /admin/scm-identities":                  schemas.PermAdminister,
+	"POST /admin/scm-sync":                          schemas.PermAdminister,
+	"POST /admin/webhooks":                          schemas.PermAdminister,
+	"GET /admin/webhooks":                           schemas.PermAdminister,
+	"DELETE /admin/webhooks/:id":                    schemas.PermAdminister,
+	"GET /admin/webhooks/deliveries":                schemas.PermAdminister,
+	"POST /admin/webhooks/deliveries/:id/redeliver": schemas.PermAdminister,
+	"GET /admin/outbox":                             schemas.PermAdminister,
+}
+
+// RegisterPublicRoutes регистрирует маршруты без аутентификации. Служебные адреса (health, ready, спецификация,
+// JWKS, OIDC-колбэк и вебхуки провайдеров, прописанные во внешних системах) остаются без версии.
+func (h *Handlers) RegisterPublicRoutes(r *gin.Engine, rateLimit gin.HandlerFunc) {
+	r.GET("/health", h.Health)
+	r.GET("/ready", h.Ready)
+	r.GET("/openapi.json", h.OpenAPISpec)
+	r.GET("/docs/*any", h.Docs)
+	r.GET("/.well-known/jwks.json", h.JWKS)
+	if h.ssoUsecase != nil {
+		r.GET("/auth/oidc/login", rateLimit, h.OIDCLogin)
+		r.GET("/auth/oidc/callback", rateLimit, h.OIDCCallback)
+	}
+	r.POST("/webhooks/:provider", rateLimit, h.SCMWebhook)
+
+	for _, g := range versionGroups(r) {
+		g.POST("/auth/login", rateLimit, h.Login)
+		g.POST("/auth/refresh", rateLimit, h.Refresh)
+	}
+}
+
+// versionGroups — /api/v1 и устаревшие синонимы без версии с заголовком Deprecation
+func versionGroups(r *gin.Engine, handlers ...gin.HandlerFunc) []*gin.RouterGroup {
+	return []*gin.RouterGroup{
+		r.Group(middleware.APIPrefix, handlers...),
+		r.Group("/", append([]gin.HandlerFunc{middleware.Deprecated()}, handlers...)...),
+	}
+}
+
+// RegisterRoutes регистрирует защищённые маршруты под /api/v1 и по старым путям; rateLimit и idempotency
+// выполняются после аутентификации, чтобы лимит и ключи идемпотентности относились к клиенту, а не к IP
+func (h *Handlers) RegisterRoutes(r *gin.Engine, rateLimit, idempotency gin.HandlerFunc) {
+	var external middleware.BearerVerifier
+	if h.ssoUsecase != nil {
+		external = h.ssoUsecase
+	}
+
+	for _, protected := range versionGroups(r,
+		middleware.AuthMiddleware(h.tokens, h.sessionUsecase, h.apiKeyUsecase, external, routeScopes),
+		rateLimit,
+		middleware.RBACMiddleware(h.authzUsecase, routePermissions),
+		idempotency,
+	) {
 		protected.POST("/team/add", h.CreateTeam)
 		protected.GET("/team/get", h.GetTeam)
+		protected.POST("/team/members", h.AddTeamMember)
 		protected.POST("/users/setIsActive", h.SetUserActive)
 		protected.GET("/users/getReview", h.GetUserReviews)
+		protected.GET("/users/notifications", h.ListNotificationPreferences)
+		protected.PUT("/users/notifications", h.SetNotificationPreference)
+		protected.DELETE("/users/notifications", h.DeleteNotificationPreference)
+		protected.GET("/users/digest", h.GetDigestSetting)
+		protected.PUT("/users/digest", h.SetDigestSetting)
+		protected.DELETE("/users/digest", h.DeleteDigestSetting)
+		protected.GET("/users/digest/preview", h.PreviewDigest)
+		protected.GET("/pullRequest/get", h.GetPR)
 		protected.POST("/pullRequest/create", h.CreatePR)
 		protected.POST("/pullRequest/merge", h.MergePR)
 		protected.POST("/pullRequest/reassign", h.ReassignPR)
+		protected.POST("/pullRequest/decline", h.DeclinePR)
 		protected.GET("/stats", h.GetStats)
+		protected.GET("/events/stream", h.StreamEvents)
+		protected.POST("/graphql", h.GraphQL)
+		protected.POST("/admin/import", h.ImportRoster)
+		protected.GET("/admin/export", h.ExportRoster)
+		protected.POST("/admin/sync", h.SyncConfig)
+		protected.POST("/auth/accounts", h.CreateAccount)
+		protected.POST("/auth/password/set", h.SetPassword)
+		protected.POST("/auth/password/change", h.ChangePassword)
+		protected.POST("/auth/logout", h.Logout)
+		protected.POST("/auth/revoke", h.RevokeTokens)
+		protected.POST("/admin/api-keys", h.CreateAPIKey)
+		protected.GET("/admin/api-keys", h.ListAPIKeys)
+		protected.DELETE("/admin/api-keys/:id", h.RevokeAPIKey)
+		protected.POST("/admin/role-bindings", h.CreateRoleBinding)
+		protected.GET("/admin/role-bindings", h.ListRoleBindings)
+		protected.DELETE("/admin/role-bindings", h.DeleteRoleBinding)
+		protected.POST("/admin/scm-identities", h.LinkSCMIdentity)
+		protected.GET("/admin/scm-identities", h.ListSCMIdentities)
+		protected.DELETE("/admin/scm-identities", h.UnlinkSCMIdentity)
+		protected.POST("/admin/scm-sync", h.SyncSCMReviewers)
+		protected.POST("/admin/webhooks", h.CreateWebhook)
+		protected.GET("/admin/webhooks", h.ListWebhooks)
+		protected.DELETE("/admin/webhooks/:id", h.DeleteWebhook)
+		protected.GET("/admin/webhooks/deliveries", h.ListWebhookDeliveries)
+		protected.POST("/admin/webhooks/deliveries/:id/redeliver", h.RedeliverWebhook)
+		protected.GET("/admin/outbox", h.GetOutboxProgress)
+	}
+}
+
+// authorize проверяет право на конкретный объект; при отказе пишет ответ и возвращает false
+func (h *Handlers) authorize(c *gin.Context, perm string, res schemas.AuthzResource) bool {
+	if err := h.authzUsecase.Authorize(c.Request.Context(), middleware.PrincipalFromContext(c), perm, res); err != nil {
+		handleError(c, err)
+		return false
 	}
+	return true
 }
 
 // Публичные
@@ -47,39 +256,46 @@ func (h *Handlers) Health(c *gin.Context) {
 	c.JSON(200, gin.H{"status": "ok"})
 }
 
+// Ready — готовность принимать трафик; при остановке отвечает 503 раньше, чем сервер перестаёт слушать
+func (h *Handlers) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		handleError(c, errors.ErrShuttingDown)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+// JWKS — публичные ключи для проверки наших токенов другими сервисами
+func (h *Handlers) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(200, h.tokens.JWKS())
+}
+
 func (h *Handlers) Login(c *gin.Context) {
 	var req struct {
-		UserID   string `json:"user_id" binding:"required"`
+		UserID   string `json:"user_id" binding:"required"` // логин учётной записи
 		Password string `json:"password" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": gin.H{"code": "BAD_REQUEST", "message": err.Error()}})
+		handleError(c, bindError(err))
 		return
 	}
 
-	var role string
-	if req.UserID == "admin" && req.Password == "admin" {
-		role = "admin"
-	} else if req.UserID == "user" && req.Password == "user" {
-		role = "user"
-	} else {
-		c.JSON(401, gin.H{"error": gin.H{"code": "INVALID_CREDENTIALS", "message": "Invalid user_id or password"}})
+	account, err := h.authUsecase.Login(c.Request.Context(), req.UserID, req.Password)
+	if err != nil {
+		handleError(c, err)
 		return
 	}
 
-	token, err := jwt.GenerateToken(req.UserID, role)
+	pair, err := h.sessionUsecase.Issue(c.Request.Context(), account)
 	if err != nil {
-		c.JSON(500, gin.H{"error": gin.H{"code": "INTERNAL_ERROR", "message": err.Error()}})
+		handleError(c, err)
 		return
 	}
-
-	c.JSON(200, gin.H{
-		"token": token,
-		"role":  role,
-	})
+	c.JSON(200, pair)
 }
 
-// Защищённые хендлеры (все остальные — оставляем как были, но без @Summary и т.д.)
+// Защищённые хендлеры
 
 func (h *Handlers) CreateTeam(c *gin.Context) {
 	var req struct {
@@ -87,12 +303,12 @@ func (h *Handlers) CreateTeam(c *gin.Context) {
 		Members []schemas.User `json:"members" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": gin.H{"code": "BAD_REQUEST", "message": err.Error()}})
+		handleError(c, bindError(err))
 		return
 	}
 
 	teamData := &schemas.Team{Name: req.Name, Members: req.Members}
-	team, err := h.teamUsecase.CreateTeam(teamData)
+	team, err := h.teamUsecase.CreateTeam(c.Request.Context(), teamData)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -103,10 +319,10 @@ func (h *Handlers) CreateTeam(c *gin.Context) {
 func (h *Handlers) GetTeam(c *gin.Context) {
 	name := c.Query("team_name")
 	if name == "" {
-		c.JSON(400, gin.H{"error": "team_name query param is required"})
+		handleError(c, errors.Validation(errors.FieldError{Field: "team_name", Message: "query param is required"}))
 		return
 	}
-	team, err := h.teamUsecase.GetTeam(name)
+	team, err := h.teamUsecase.GetTeam(c.Request.Context(), name)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -114,16 +330,43 @@ func (h *Handlers) GetTeam(c *gin.Context) {
 	c.JSON(200, team)
 }
 
+// AddTeamMember — добавление или обновление участника команды (admin или лид этой команды)
+func (h *Handlers) AddTeamMember(c *gin.Context) {
+	var req struct {
+		TeamName string       `json:"team_name" binding:"required"`
+		Member   schemas.User `json:"member" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	if !h.authorize(c, schemas.PermTeamManage, schemas.AuthzResource{TeamName: req.TeamName, TargetUserID: req.Member.ID}) {
+		return
+	}
+
+	member := req.Member
+	member.TeamName = req.TeamName
+	diff, err := h.rosterUsecase.Import(c.Request.Context(), &schemas.Roster{Teams: []schemas.Team{{Name: req.TeamName, Members: []schemas.User{member}}}}, false)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"diff": diff})
+}
+
 func (h *Handlers) SetUserActive(c *gin.Context) {
 	var req struct {
 		UserID   string `json:"user_id" binding:"required"`
 		IsActive bool   `json:"is_active"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": gin.H{"code": "BAD_REQUEST", "message": err.Error()}})
+		handleError(c, bindError(err))
 		return
 	}
-	user, err := h.userUsecase.SetIsActive(req.UserID, req.IsActive)
+	if !h.authorize(c, schemas.PermUsersSetActive, schemas.AuthzResource{TargetUserID: req.UserID}) {
+		return
+	}
+	user, err := h.userUsecase.SetIsActive(c.Request.Context(), req.UserID, req.IsActive)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -134,10 +377,10 @@ func (h *Handlers) SetUserActive(c *gin.Context) {
 func (h *Handlers) GetUserReviews(c *gin.Context) {
 	userID := c.Query("user_id")
 	if userID == "" {
-		c.JSON(400, gin.H{"error": "user_id query param is required"})
+		handleError(c, errors.Validation(errors.FieldError{Field: "user_id", Message: "query param is required"}))
 		return
 	}
-	user, prs, err := h.userUsecase.GetUserReviews(userID)
+	user, prs, err := h.userUsecase.GetUserReviews(c.Request.Context(), userID)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -145,6 +388,22 @@ func (h *Handlers) GetUserReviews(c *gin.Context) {
 	c.JSON(200, gin.H{"user_id": user.ID, "pull_requests": prs})
 }
 
+// GetPR — PR с текущей версией в ETag: по нему клиент готовит If-Match, ничего не меняя
+func (h *Handlers) GetPR(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		handleError(c, errors.Validation(errors.FieldError{Field: "pull_request_id", Message: "query param is required"}))
+		return
+	}
+	pr, err := h.prUsecase.GetPR(c.Request.Context(), prID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Header("ETag", pr.ETag())
+	c.JSON(200, gin.H{"pr": pr})
+}
+
 func (h *Handlers) CreatePR(c *gin.Context) {
 	var req struct {
 		PRID   string `json:"pull_request_id" binding:"required"`
@@ -152,14 +411,18 @@ func (h *Handlers) CreatePR(c *gin.Context) {
 		Author string `json:"author_id" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": gin.H{"code": "BAD_REQUEST", "message": err.Error()}})
+		handleError(c, bindError(err))
+		return
+	}
+	if !h.authorize(c, schemas.PermPRCreate, schemas.AuthzResource{PRID: req.PRID, AuthorID: req.Author}) {
 		return
 	}
-	pr, err := h.prUsecase.CreatePR(req.PRID, req.Name, req.Author)
+	pr, err := h.prUsecase.CreatePR(c.Request.Context(), req.PRID, req.Name, req.Author)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
+	c.Header("ETag", pr.ETag())
 	c.JSON(201, gin.H{"pr": pr})
 }
 
@@ -168,14 +431,22 @@ func (h *Handlers) MergePR(c *gin.Context) {
 		PRID string `json:"pull_request_id" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": gin.H{"code": "BAD_REQUEST", "message": err.Error()}})
+		handleError(c, bindError(err))
+		return
+	}
+	if !h.authorize(c, schemas.PermPRMerge, schemas.AuthzResource{PRID: req.PRID}) {
+		return
+	}
+	version, ok := ifMatch(c)
+	if !ok {
 		return
 	}
-	pr, err := h.prUsecase.MergePR(req.PRID)
+	pr, err := h.prUsecase.MergePR(c.Request.Context(), req.PRID, version)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
+	c.Header("ETag", pr.ETag())
 	c.JSON(200, gin.H{"pr": pr})
 }
 
@@ -185,19 +456,66 @@ func (h *Handlers) ReassignPR(c *gin.Context) {
 		OldUserID string `json:"old_user_id" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": gin.H{"code": "BAD_REQUEST", "message": err.Error()}})
+		handleError(c, bindError(err))
+		return
+	}
+	if !h.authorize(c, schemas.PermPRReassign, schemas.AuthzResource{PRID: req.PRID, TargetUserID: req.OldUserID}) {
 		return
 	}
-	pr, newReviewer, err := h.prUsecase.ReassignPR(req.PRID, req.OldUserID)
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+	pr, newReviewer, err := h.prUsecase.ReassignPR(c.Request.Context(), req.PRID, req.OldUserID, version)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
+	c.Header("ETag", pr.ETag())
 	c.JSON(200, gin.H{"pr": pr, "replaced_by": newReviewer})
 }
 
+// DeclinePR — отказ от собственного ревью: вызывающий заменяется другим ревьювером
+func (h *Handlers) DeclinePR(c *gin.Context) {
+	var req struct {
+		PRID string `json:"pull_request_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	userID := c.GetString("user_id")
+	if c.GetString("login") == "" || userID == "" {
+		handleError(c, errors.ErrForbidden.WithMessage("account is not linked to a user"))
+		return
+	}
+	if !h.authorize(c, schemas.PermPRReassign, schemas.AuthzResource{PRID: req.PRID, TargetUserID: userID}) {
+		return
+	}
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+	pr, newReviewer, err := h.prUsecase.ReassignPR(c.Request.Context(), req.PRID, userID, version)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Header("ETag", pr.ETag())
+	c.JSON(200, gin.H{"pr": pr, "replaced_by": newReviewer})
+}
+
+// ifMatch читает ожидаемую версию PR из If-Match (0 — без проверки); неверное значение — 400
+func ifMatch(c *gin.Context) (int, bool) {
+	version, ok := schemas.ParseIfMatch(c.GetHeader("If-Match"))
+	if !ok {
+		handleError(c, errors.Validation(errors.FieldError{Field: "If-Match", Message: "must be an ETag returned for the pull request"}))
+	}
+	return version, ok
+}
+
 func (h *Handlers) GetStats(c *gin.Context) {
-	userStats, prStats, err := h.prUsecase.GetStats()
+	userStats, prStats, err := h.prUsecase.GetStats(c.Request.Context())
 	if err != nil {
 		handleError(c, err)
 		return
@@ -207,22 +525,3 @@ func (h *Handlers) GetStats(c *gin.Context) {
 		"pr_assignments":   prStats,
 	})
 }
-
-func handleError(c *gin.Context, err error) {
-	switch err {
-	case errors.ErrTeamExists:
-		c.JSON(400, gin.H{"error": gin.H{"code": "TEAM_EXISTS", "message": "team_name already exists"}})
-	case errors.ErrPRExists:
-		c.JSON(409, gin.H{"error": gin.H{"code": "PR_EXISTS", "message": "PR id already exists"}})
-	case errors.ErrPRMerged:
-		c.JSON(409, gin.H{"error": gin.H{"code": "PR_MERGED", "message": "cannot reassign on merged PR"}})
-	case errors.ErrNotAssigned:
-		c.JSON(409, gin.H{"error": gin.H{"code": "NOT_ASSIGNED", "message": "reviewer is not assigned to this PR"}})
-	case errors.ErrNoCandidate:
-		c.JSON(409, gin.H{"error": gin.H{"code": "NO_CANDIDATE", "message": "no active replacement candidate in team"}})
-	case errors.ErrNotFound:
-		c.JSON(404, gin.H{"error": gin.H{"code": "NOT_FOUND", "message": "resource not found"}})
-	default:
-		c.JSON(500, gin.H{"error": gin.H{"code": "INTERNAL_ERROR", "message": err.Error()}})
-	}
-}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"ReviewAssigner/internal/delivery/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loginAdmin(t *testing.T, r *gin.Engine) string {
+	w := doRequest(r, "POST", "/api/v1/auth/login", "", map[string]string{"user_id": "admin", "password": "admin-password"})
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var pair struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pair))
+	return pair.Token
+}
+
+func doIdempotent(r *gin.Engine, path, token, key string, body interface{}) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Idempotency-Key", key)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysFirstResponse(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationOff, false)
+	token := loginAdmin(t, r)
+	create := map[string]string{"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1"}
+
+	first := doIdempotent(r, "/api/v1/pullRequest/create", token, "ci-run-1", create)
+	require.Equal(t, 201, first.Code, first.Body.String())
+	assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+
+	// Повтор после таймаута получает тот же ответ, а не PR_EXISTS; по старому пути — тоже
+	for _, path := range []string{"/api/v1/pullRequest/create", "/pullRequest/create"} {
+		retry := doIdempotent(r, path, token, "ci-run-1", create)
+		assert.Equal(t, 201, retry.Code, path)
+		assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"), path)
+		assert.Equal(t, first.Body.String(), retry.Body.String(), path)
+		assert.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"), path)
+	}
+
+	// Без ключа или с новым ключом запрос выполняется заново
+	w := doIdempotent(r, "/api/v1/pullRequest/create", token, "ci-run-2", create)
+	assert.Equal(t, 409, w.Code)
+	assert.Equal(t, "PR_EXISTS", errorCode(t, w))
+}
+
+func TestIdempotency_ReplaysResponseHeaders(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationOff, false)
+	token := loginAdmin(t, r)
+	create := map[string]string{"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1"}
+
+	first := doIdempotent(r, "/pullRequest/create", token, "ci-run-1", create)
+	require.Equal(t, 201, first.Code, first.Body.String())
+	require.NotEmpty(t, first.Header().Get("ETag"))
+
+	// Повтор получает версию для следующего If-Match и пометки устаревшего пути
+	retry := doIdempotent(r, "/pullRequest/create", token, "ci-run-1", create)
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	for _, name := range []string{"ETag", "Deprecation", "Link"} {
+		assert.Equal(t, first.Header().Get(name), retry.Header().Get(name), name)
+	}
+}
+
+func TestIdempotency_KeyReusedWithDifferentBody(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationOff, false)
+	token := loginAdmin(t, r)
+
+	w := doIdempotent(r, "/api/v1/users/setIsActive", token, "key-1", map[string]interface{}{"user_id": "u3", "is_active": false})
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doIdempotent(r, "/api/v1/users/setIsActive", token, "key-1", map[string]interface{}{"user_id": "u3", "is_active": true})
+	assert.Equal(t, 409, w.Code)
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", errorCode(t, w))
+
+	// Ключ с тем же телом на другом маршруте — тоже другой запрос
+	w = doIdempotent(r, "/api/v1/pullRequest/merge", token, "key-1", map[string]interface{}{"user_id": "u3", "is_active": false})
+	assert.Equal(t, 409, w.Code)
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", errorCode(t, w))
+}
+
+func TestIdempotency_KeysAreScopedToClient(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationOff, false)
+	token := loginAdmin(t, r)
+
+	w := doIdempotent(r, "/api/v1/auth/accounts", token, "shared", map[string]string{"login": "bob", "password": "bob-password-1", "role": "member"})
+	require.Equal(t, 201, w.Code, w.Body.String())
+
+	w = doRequest(r, "POST", "/api/v1/auth/login", "", map[string]string{"user_id": "bob", "password": "bob-password-1"})
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var pair struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pair))
+
+	// Тот же ключ другого клиента не видит чужой ответ
+	w = doIdempotent(r, "/api/v1/auth/accounts", pair.Token, "shared", map[string]string{"login": "bob", "password": "bob-password-1", "role": "member"})
+	assert.Equal(t, 403, w.Code, w.Body.String())
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+}
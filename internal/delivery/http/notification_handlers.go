@@ -0,0 +1,139 @@
+package http
+
+import (
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// notificationTarget — чьи настройки меняются: свои или (только admin) указанного пользователя
+func (h *Handlers) notificationTarget(c *gin.Context, userID string) (string, bool) {
+	own := c.GetString("user_id")
+	if userID == "" {
+		userID = own
+	}
+	if userID == "" {
+		handleError(c, errors.ErrBadRequest.WithMessage("user_id is required"))
+		return "", false
+	}
+	if userID != own && !h.authorize(c, schemas.PermAdminister, schemas.AuthzResource{}) {
+		return "", false
+	}
+	return userID, true
+}
+
+func (h *Handlers) ListNotificationPreferences(c *gin.Context) {
+	userID, ok := h.notificationTarget(c, c.Query("user_id"))
+	if !ok {
+		return
+	}
+	prefs, err := h.notifierUsecase.ListPreferences(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"user_id": userID, "preferences": prefs})
+}
+
+// SetNotificationPreference включает канал уведомлений (email, slack, telegram) с адресом получателя
+func (h *Handlers) SetNotificationPreference(c *gin.Context) {
+	var req struct {
+		UserID  string `json:"user_id"`
+		Channel string `json:"channel" binding:"required"`
+		Address string `json:"address" binding:"required"`
+		Enabled *bool  `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	userID, ok := h.notificationTarget(c, req.UserID)
+	if !ok {
+		return
+	}
+
+	pref := &schemas.NotificationPreference{UserID: userID, Channel: req.Channel, Address: req.Address, Enabled: req.Enabled == nil || *req.Enabled}
+	if err := h.notifierUsecase.SetPreference(c.Request.Context(), pref); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"preference": pref})
+}
+
+func (h *Handlers) DeleteNotificationPreference(c *gin.Context) {
+	userID, ok := h.notificationTarget(c, c.Query("user_id"))
+	if !ok {
+		return
+	}
+	if err := h.notifierUsecase.DeletePreference(c.Request.Context(), userID, c.Query("channel")); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+func (h *Handlers) GetDigestSetting(c *gin.Context) {
+	userID, ok := h.notificationTarget(c, c.Query("user_id"))
+	if !ok {
+		return
+	}
+	setting, err := h.digestUsecase.GetSetting(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"digest": setting})
+}
+
+// SetDigestSetting включает ежедневную сводку в send_at (HH:MM) по часовому поясу timezone
+func (h *Handlers) SetDigestSetting(c *gin.Context) {
+	var req struct {
+		UserID   string `json:"user_id"`
+		Channel  string `json:"channel" binding:"required"`
+		SendAt   string `json:"send_at" binding:"required"`
+		Timezone string `json:"timezone"`
+		Enabled  *bool  `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	userID, ok := h.notificationTarget(c, req.UserID)
+	if !ok {
+		return
+	}
+
+	setting := &schemas.DigestSetting{UserID: userID, Channel: req.Channel, SendAt: req.SendAt, Timezone: req.Timezone, Enabled: req.Enabled == nil || *req.Enabled}
+	if err := h.digestUsecase.SetSetting(c.Request.Context(), setting); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"digest": setting})
+}
+
+func (h *Handlers) DeleteDigestSetting(c *gin.Context) {
+	userID, ok := h.notificationTarget(c, c.Query("user_id"))
+	if !ok {
+		return
+	}
+	if err := h.digestUsecase.DeleteSetting(c.Request.Context(), userID); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+// PreviewDigest показывает сводку пользователя и текст сообщения, ничего не отправляя
+func (h *Handlers) PreviewDigest(c *gin.Context) {
+	userID, ok := h.notificationTarget(c, c.Query("user_id"))
+	if !ok {
+		return
+	}
+	digest, msg, err := h.digestUsecase.Preview(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"digest": digest, "subject": msg.Subject, "text": msg.Text})
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"sync"
+
+	"ReviewAssigner/api"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+)
+
+// openAPIJSON — спецификация в JSON, собирается один раз
+var openAPIJSON = sync.OnceValues(func() ([]byte, error) {
+	doc, err := api.Load()
+	if err != nil {
+		return nil, err
+	}
+	return doc.MarshalJSON()
+})
+
+// docsIndex — Swagger UI со ссылкой на /openapi.json; скрипты и стили отдаются из встроенных файлов
+const docsIndex = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="UTF-8">
+  <title>Review Assigner API</title>
+  <link rel="stylesheet" href="swagger-ui.css">
+  <link rel="icon" type="image/png" href="favicon-32x32.png" sizes="32x32">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="swagger-ui-bundle.js"></script>
+  <script src="swagger-ui-standalone-preset.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: "/openapi.json",
+      dom_id: "#swagger-ui",
+      deepLinking: true,
+      persistAuthorization: true,
+      presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+      layout: "StandaloneLayout"
+    });
+  </script>
+</body>
+</html>
+`
+
+// OpenAPISpec отдаёт спецификацию API
+func (h *Handlers) OpenAPISpec(c *gin.Context) {
+	spec, err := openAPIJSON()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Data(200, "application/json; charset=utf-8", spec)
+}
+
+// Docs — Swagger UI на /docs/
+func (h *Handlers) Docs(c *gin.Context) {
+	file := c.Param("any")
+	if file == "/" || file == "/index.html" {
+		c.Data(200, "text/html; charset=utf-8", []byte(docsIndex))
+		return
+	}
+	c.FileFromFS(file, swaggerFiles.HTTP)
+}
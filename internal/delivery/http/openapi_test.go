@@ -0,0 +1,293 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"ReviewAssigner/api"
+	"ReviewAssigner/internal/delivery/graphql"
+	"ReviewAssigner/internal/delivery/middleware"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/jwt"
+	"ReviewAssigner/internal/pkg/notify"
+	"ReviewAssigner/internal/pkg/scm"
+	"ReviewAssigner/internal/repository/inmemory"
+	"ReviewAssigner/internal/usecase/apikey"
+	"ReviewAssigner/internal/usecase/auth"
+	"ReviewAssigner/internal/usecase/authz"
+	"ReviewAssigner/internal/usecase/digest"
+	"ReviewAssigner/internal/usecase/notifier"
+	"ReviewAssigner/internal/usecase/outbox"
+	"ReviewAssigner/internal/usecase/pr"
+	"ReviewAssigner/internal/usecase/roster"
+	"ReviewAssigner/internal/usecase/scmhook"
+	"ReviewAssigner/internal/usecase/session"
+	"ReviewAssigner/internal/usecase/sso"
+	"ReviewAssigner/internal/usecase/stream"
+	"ReviewAssigner/internal/usecase/team"
+	"ReviewAssigner/internal/usecase/user"
+	"ReviewAssigner/internal/usecase/webhook"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRouter собирает приложение поверх inmemory-репозиториев с админом admin/admin-password.
+// withSSO регистрирует маршруты OIDC (провайдер не вызывается).
+func newTestRouter(t *testing.T, validation string, withSSO bool) *gin.Engine {
+	t.Helper()
+	r, _ := newTestApp(t, validation, withSSO)
+	return r
+}
+
+// newTestApp — то же, что newTestRouter, вместе с обработчиками (тело запроса ограничено 1 MiB)
+func newTestApp(t *testing.T, validation string, withSSO bool) (*gin.Engine, *Handlers) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	outboxRepo := inmemory.NewOutboxRepository()
+	userRepo := inmemory.NewUserRepository(outboxRepo)
+	teamRepo := inmemory.NewTeamRepository()
+	prRepo := inmemory.NewPullRequestRepository(outboxRepo)
+	accountRepo := inmemory.NewAccountRepository()
+	prefRepo := inmemory.NewNotificationPreferenceRepository()
+	scmIdentityRepo := inmemory.NewSCMIdentityRepository()
+
+	// Inmemory-репозитории не связаны между собой, поэтому команда заводится в обоих
+	members := []schemas.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+		{ID: "u3", Username: "Carol", TeamName: "backend", IsActive: true},
+	}
+	teamRepo.(interface{ AddTeam(*schemas.Team) }).AddTeam(&schemas.Team{Name: "backend", Members: members})
+	for i := range members {
+		userRepo.(interface{ AddUser(*schemas.User) }).AddUser(&members[i])
+	}
+
+	templates, err := notify.LoadTemplates("")
+	require.NoError(t, err)
+	tokens, err := jwt.NewManager(jwt.Config{Secret: []byte("openapi-test-secret-0123456789abcdef")})
+	require.NoError(t, err)
+
+	teamUsecase := team.NewUsecase(teamRepo)
+	userUsecase := user.NewUsecase(userRepo, prRepo)
+	prUsecase := pr.NewUsecase(userRepo, prRepo, teamRepo, inmemory.NewTxManager(userRepo, teamRepo, prRepo), nil)
+	authUsecase := auth.NewUsecase(accountRepo, userRepo, auth.LockoutPolicy{MaxAttempts: 5, Duration: time.Minute})
+	require.NoError(t, authUsecase.EnsureAdmin(context.Background(), "admin", "admin-password"))
+	authzUsecase := authz.NewUsecase(inmemory.NewRoleBindingRepository(), accountRepo, userRepo, prRepo, teamRepo)
+	webhookUsecase := webhook.NewUsecase(inmemory.NewWebhookSubscriptionRepository(), inmemory.NewWebhookDeliveryRepository(), webhook.RetryPolicy{})
+	notifierUsecase := notifier.NewUsecase(map[string]notify.Channel{}, templates, prefRepo, userRepo)
+	var ssoUsecase *sso.Usecase
+	if withSSO {
+		ssoUsecase = sso.NewUsecase(nil, inmemory.NewOIDCStateRepository(), accountRepo, userRepo, sso.Mapping{}, time.Minute)
+	}
+
+	handlers := NewHandlers(teamUsecase, userUsecase, prUsecase, roster.NewUsecase(teamRepo, userRepo), authUsecase,
+		session.NewUsecase(accountRepo, inmemory.NewTokenRepository(), tokens, time.Hour), apikey.NewUsecase(inmemory.NewAPIKeyRepository()),
+		authzUsecase, ssoUsecase,
+		scmhook.NewUsecase(scm.DefaultRegistry(), map[string]string{}, prUsecase, scmIdentityRepo, inmemory.NewSCMDeliveryRepository(), userRepo),
+		nil, webhookUsecase, notifierUsecase,
+		digest.NewUsecase(inmemory.NewDigestSettingRepository(), prefRepo, userRepo, prRepo, notifierUsecase, nil),
+		outbox.NewUsecase(outboxRepo, nil, time.Hour), stream.NewUsecase(outboxRepo, userRepo),
+		graphql.NewServer(teamUsecase, userUsecase, prUsecase, authzUsecase), tokens, nil)
+
+	r := gin.New()
+	r.Use(middleware.MaxBodySize(1 << 20))
+	if validation != middleware.OpenAPIValidationOff {
+		doc, err := api.Load()
+		require.NoError(t, err)
+		mw, err := middleware.OpenAPIValidation(doc, validation)
+		require.NoError(t, err)
+		r.Use(mw)
+	}
+	noLimit := func(c *gin.Context) { c.Next() }
+	handlers.RegisterPublicRoutes(r, noLimit)
+	handlers.RegisterRoutes(r, noLimit, middleware.IdempotencyMiddleware(inmemory.NewIdempotencyRepository(), time.Hour))
+	return r, handlers
+}
+
+func doRequest(r *gin.Engine, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	var resp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+	return resp.Error.Code
+}
+
+// Каждый зарегистрированный маршрут описан в спецификации, и наоборот
+func TestOpenAPISpec_CoversAllRoutes(t *testing.T) {
+	doc, err := api.Load()
+	require.NoError(t, err)
+
+	pathParam := regexp.MustCompile(`\{(\w+)\}`)
+	var specRoutes []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			specRoutes = append(specRoutes, method+" "+pathParam.ReplaceAllString(path, ":$1"))
+		}
+	}
+
+	registered := map[string]bool{}
+	routes := newTestRouter(t, middleware.OpenAPIValidationOff, true).Routes()
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+	}
+	var ginRoutes, aliases []string
+	for _, route := range routes {
+		switch {
+		case strings.HasPrefix(route.Path, "/docs/"):
+		case registered[route.Method+" "+middleware.APIPrefix+route.Path]:
+			// Устаревший синоним маршрута /api/v1 в спецификацию не входит
+			aliases = append(aliases, route.Method+" "+route.Path)
+		default:
+			ginRoutes = append(ginRoutes, route.Method+" "+route.Path)
+		}
+	}
+
+	assert.ElementsMatch(t, specRoutes, ginRoutes)
+	assert.Contains(t, aliases, "POST /pullRequest/create")
+	assert.Contains(t, aliases, "POST /auth/login")
+}
+
+// Сквозной сценарий с проверкой запросов и ответов: любой ответ не по спецификации превратился бы в 500
+func TestOpenAPIValidation_Flow(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationAll, false)
+
+	w := doRequest(r, "GET", "/health", "", nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "POST", "/api/v1/auth/login", "", map[string]string{"user_id": "admin", "password": "admin-password"})
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var pair struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pair))
+	token := pair.Token
+
+	w = doRequest(r, "POST", "/api/v1/team/add", token, map[string]interface{}{
+		"name":    "frontend",
+		"members": []map[string]interface{}{{"user_id": "u9", "username": "Eve", "is_active": true}},
+	})
+	assert.Equal(t, 201, w.Code, w.Body.String())
+
+	w = doRequest(r, "POST", "/api/v1/team/members", token, map[string]interface{}{
+		"team_name": "backend",
+		"member":    map[string]interface{}{"user_id": "u4", "username": "Dave", "is_active": true},
+	})
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "GET", "/api/v1/team/get?team_name=backend", token, nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "POST", "/api/v1/users/setIsActive", token, map[string]interface{}{"user_id": "u3", "is_active": true})
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "POST", "/api/v1/pullRequest/create", token, map[string]string{
+		"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1",
+	})
+	assert.Equal(t, 201, w.Code, w.Body.String())
+
+	w = doRequest(r, "GET", "/api/v1/users/getReview?user_id=u2", token, nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "POST", "/api/v1/pullRequest/merge", token, map[string]string{"pull_request_id": "pr-1"})
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "GET", "/api/v1/stats", token, nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "POST", "/api/v1/graphql", token, map[string]string{"query": `{ team(name: "backend") { name members { id reviews { id } } } }`})
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "POST", "/api/v1/admin/api-keys", token, map[string]interface{}{"name": "ci", "scopes": []string{"team:read"}})
+	assert.Equal(t, 201, w.Code, w.Body.String())
+
+	w = doRequest(r, "GET", "/api/v1/admin/api-keys", token, nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "GET", "/api/v1/admin/export", token, nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = doRequest(r, "GET", "/openapi.json", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `"openapi":"3.0.3"`)
+
+	w = doRequest(r, "GET", "/docs/", "", nil)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "/openapi.json")
+
+	// Ошибки тоже отвечают по спецификации
+	w = doRequest(r, "POST", "/api/v1/pullRequest/create", token, map[string]string{
+		"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "u1",
+	})
+	assert.Equal(t, 409, w.Code, w.Body.String())
+	assert.Equal(t, "PR_EXISTS", errorCode(t, w))
+
+	w = doRequest(r, "GET", "/api/v1/team/get?team_name=mobile", token, nil)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+
+	w = doRequest(r, "GET", "/api/v1/stats", "", nil)
+	assert.Equal(t, 401, w.Code, w.Body.String())
+}
+
+func TestOpenAPIValidation_RejectsInvalidRequest(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationRequests, false)
+
+	w := doRequest(r, "GET", "/api/v1/team/get", "", nil)
+	assert.Equal(t, 400, w.Code)
+	body := decodeError(t, w)
+	assert.Equal(t, "BAD_REQUEST", body.Error.Code)
+	require.Len(t, body.Error.Fields, 1)
+	assert.Equal(t, "team_name", body.Error.Fields[0].Field)
+
+	w = doRequest(r, "POST", "/api/v1/auth/login", "", map[string]interface{}{"user_id": 42, "password": "x"})
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, "BAD_REQUEST", errorCode(t, w))
+}
+
+func TestOpenAPIValidation_RejectsResponseDrift(t *testing.T) {
+	doc, err := api.Load()
+	require.NoError(t, err)
+	mw, err := middleware.OpenAPIValidation(doc, middleware.OpenAPIValidationAll)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(mw)
+	r.GET("/health", func(c *gin.Context) { c.JSON(200, gin.H{"status": 1}) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(nethttp.MethodGet, "/health", nil))
+	assert.Equal(t, 500, w.Code)
+	assert.Equal(t, "RESPONSE_VALIDATION", errorCode(t, w))
+}
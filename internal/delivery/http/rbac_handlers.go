@@ -0,0 +1,57 @@
+package http
+
+import (
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateRoleBinding назначает командную роль (только admin), например team_lead команды backend
+func (h *Handlers) CreateRoleBinding(c *gin.Context) {
+	var req schemas.RoleBinding
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	if req.Login == "" || req.TeamName == "" {
+		handleError(c, errors.ErrBadRequest.WithMessage("login and team_name are required"))
+		return
+	}
+	if req.Role == "" {
+		req.Role = schemas.RoleTeamLead
+	}
+
+	if err := h.authzUsecase.CreateBinding(c.Request.Context(), &req); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(201, gin.H{"role_binding": req})
+}
+
+// ListRoleBindings — все назначения либо назначения одной учётки (?login=)
+func (h *Handlers) ListRoleBindings(c *gin.Context) {
+	bindings, err := h.authzUsecase.ListBindings(c.Request.Context(), c.Query("login"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"role_bindings": bindings})
+}
+
+func (h *Handlers) DeleteRoleBinding(c *gin.Context) {
+	binding := &schemas.RoleBinding{
+		Login:    c.Query("login"),
+		Role:     c.DefaultQuery("role", schemas.RoleTeamLead),
+		TeamName: c.Query("team_name"),
+	}
+	if binding.Login == "" || binding.TeamName == "" {
+		handleError(c, errors.ErrBadRequest.WithMessage("login and team_name query params are required"))
+		return
+	}
+	if err := h.authzUsecase.DeleteBinding(c.Request.Context(), binding); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"bytes"
+	"strconv"
+
+	"ReviewAssigner/internal/pkg/errors"
+	rosterfmt "ReviewAssigner/internal/pkg/roster"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportRoster принимает CSV/YAML с командами. Формат берётся из ?format= или Content-Type,
+// ?dry_run=true только возвращает diff без изменений в БД.
+func (h *Handlers) ImportRoster(c *gin.Context) {
+	formatParam := c.Query("format")
+	if formatParam == "" {
+		formatParam = c.ContentType()
+	}
+	format, err := rosterfmt.NormalizeFormat(formatParam)
+	if err != nil {
+		handleError(c, badRequest(err))
+		return
+	}
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			handleError(c, errors.ErrBadRequest.WithMessage("dry_run must be a boolean"))
+			return
+		}
+	}
+
+	roster, err := rosterfmt.Decode(format, c.Request.Body)
+	if err != nil {
+		handleError(c, badRequest(err))
+		return
+	}
+	diff, err := h.rosterUsecase.Import(c.Request.Context(), roster, dryRun)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"dry_run": dryRun, "diff": diff})
+}
+
+func (h *Handlers) ExportRoster(c *gin.Context) {
+	format, err := rosterfmt.NormalizeFormat(c.DefaultQuery("format", rosterfmt.FormatYAML))
+	if err != nil {
+		handleError(c, badRequest(err))
+		return
+	}
+	roster, err := h.rosterUsecase.Export(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := rosterfmt.Encode(format, &buf, roster); err != nil {
+		handleError(c, err)
+		return
+	}
+	contentType := "application/yaml"
+	if format == rosterfmt.FormatCSV {
+		contentType = "text/csv"
+	}
+	c.Header("Content-Disposition", "attachment; filename=teams."+format)
+	c.Data(200, contentType+"; charset=utf-8", buf.Bytes())
+}
+
+// SyncConfig сверяет БД с YAML-конфигурацией команд (config-as-code).
+// ?mode=plan (по умолчанию) возвращает расхождения, ?mode=apply применяет их.
+func (h *Handlers) SyncConfig(c *gin.Context) {
+	mode := c.DefaultQuery("mode", "plan")
+	if mode != "plan" && mode != "apply" {
+		handleError(c, errors.ErrBadRequest.WithMessage("mode must be plan or apply"))
+		return
+	}
+
+	config, err := rosterfmt.Decode(rosterfmt.FormatYAML, c.Request.Body)
+	if err != nil {
+		handleError(c, badRequest(err))
+		return
+	}
+	diff, err := h.rosterUsecase.Sync(c.Request.Context(), config, mode == "apply")
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"mode": mode, "in_sync": !diff.HasChanges(), "diff": diff})
+}
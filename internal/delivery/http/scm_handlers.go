@@ -0,0 +1,95 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBody — предел размера payload вебхука
+const maxWebhookBody = 5 << 20
+
+// SCMWebhook принимает вебхук Git-хостинга (публичный, аутентификация — подпись провайдера)
+func (h *Handlers) SCMWebhook(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBody))
+	if err != nil {
+		handleError(c, badRequest(err))
+		return
+	}
+
+	result, err := h.scmHookUsecase.Handle(c.Request.Context(), c.Param("provider"), c.Request.Header, body)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, result)
+}
+
+// LinkSCMIdentity привязывает логин у провайдера к пользователю (только admin)
+func (h *Handlers) LinkSCMIdentity(c *gin.Context) {
+	var req struct {
+		Provider string `json:"provider" binding:"required"`
+		Username string `json:"username" binding:"required"`
+		UserID   string `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+
+	identity := &schemas.SCMIdentity{Provider: req.Provider, Username: req.Username, UserID: req.UserID}
+	if err := h.scmHookUsecase.LinkIdentity(c.Request.Context(), identity); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(201, gin.H{"identity": identity})
+}
+
+// ListSCMIdentities — привязки логинов (?provider= для одного провайдера)
+func (h *Handlers) ListSCMIdentities(c *gin.Context) {
+	identities, err := h.scmHookUsecase.ListIdentities(c.Request.Context(), c.Query("provider"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"identities": identities})
+}
+
+func (h *Handlers) UnlinkSCMIdentity(c *gin.Context) {
+	provider, username := c.Query("provider"), c.Query("username")
+	if provider == "" || username == "" {
+		handleError(c, errors.ErrBadRequest.WithMessage("provider and username query params are required"))
+		return
+	}
+	if err := h.scmHookUsecase.UnlinkIdentity(c.Request.Context(), provider, username); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+// SyncSCMReviewers повторно отправляет ревьюверов PR провайдеру и возвращает PR со статусом синхронизации
+func (h *Handlers) SyncSCMReviewers(c *gin.Context) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+	if h.reviewSync == nil {
+		handleError(c, errors.ErrNotFound)
+		return
+	}
+
+	pr, err := h.reviewSync.Sync(c.Request.Context(), req.PullRequestID, nil)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"pr": pr})
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/delivery/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReady_FailsWhileDraining(t *testing.T) {
+	r, h := newTestApp(t, middleware.OpenAPIValidationAll, false)
+	w := doRequest(r, "GET", "/ready", "", nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	h.Drain()
+	w = doRequest(r, "GET", "/ready", "", nil)
+	assert.Equal(t, 503, w.Code)
+	assert.Equal(t, "SHUTTING_DOWN", errorCode(t, w))
+
+	// Живость и API не меняются: начатые и новые запросы дорабатывают
+	w = doRequest(r, "GET", "/health", "", nil)
+	assert.Equal(t, 200, w.Code)
+	token := loginAdmin(t, r)
+	w = doRequest(r, "GET", "/api/v1/team/get?team_name=backend", token, nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+}
+
+func TestMaxBodySize(t *testing.T) {
+	r := newTestRouter(t, middleware.OpenAPIValidationOff, false)
+	token := loginAdmin(t, r)
+	body := `{"pull_request_id":"pr-1","pull_request_name":"` + strings.Repeat("a", 1<<20) + `","author_id":"u1"}`
+
+	// Заявленная длина больше предела — отказ до чтения тела
+	req := httptest.NewRequest("POST", "/api/v1/pullRequest/create", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 413, w.Code)
+	assert.Equal(t, "PAYLOAD_TOO_LARGE", errorCode(t, w))
+
+	// Тело без длины обрывается при чтении
+	req = httptest.NewRequest("POST", "/api/v1/pullRequest/create", strings.NewReader(body))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 413, w.Code)
+	assert.Equal(t, "PAYLOAD_TOO_LARGE", errorCode(t, w))
+}
+
+func TestCloseStreams_EndsEventStream(t *testing.T) {
+	r, h := newTestApp(t, middleware.OpenAPIValidationOff, false)
+	token := loginAdmin(t, r)
+
+	req := httptest.NewRequest("GET", "/api/v1/events/stream", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	h.CloseStreams()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "event stream did not stop")
+	}
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"ReviewAssigner/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCLogin перенаправляет браузер на страницу входа OIDC-провайдера (публичный)
+func (h *Handlers) OIDCLogin(c *gin.Context) {
+	url, err := h.ssoUsecase.Begin(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Redirect(302, url)
+}
+
+// OIDCCallback завершает вход через провайдера и выдаёт нашу пару токенов (публичный)
+func (h *Handlers) OIDCCallback(c *gin.Context) {
+	if errCode := c.Query("error"); errCode != "" {
+		handleError(c, errors.ErrSSOFailed.WithMessage(errCode+": "+c.Query("error_description")))
+		return
+	}
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		handleError(c, errors.ErrBadRequest.WithMessage("state and code query params are required"))
+		return
+	}
+
+	account, err := h.ssoUsecase.Complete(c.Request.Context(), state, code)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	pair, err := h.sessionUsecase.Issue(c.Request.Context(), account)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, pair)
+}
@@ -0,0 +1,206 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/usecase/stream"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// streamHeartbeat — как часто слать keep-alive, чтобы прокси не закрывали простаивающее соединение
+const streamHeartbeat = 15 * time.Second
+
+// newUpgrader принимает WebSocket без Origin (не браузер), с того же адреса, что и API, и с origins.
+// Origin проверяется, даже пока поток принимает только JWT в заголовке: с cookie или токеном
+// в адресе чужая страница могла бы открыть поток от имени пользователя.
+func newUpgrader(origins []string) websocket.Upgrader {
+	return websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+				return true
+			}
+			for _, allowed := range origins {
+				if allowed == "*" || strings.EqualFold(allowed, origin) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// StreamEvents — живой поток событий: Server-Sent Events или WebSocket (по заголовку Upgrade).
+// ?team= и ?user= ограничивают поток; переподключение продолжается с Last-Event-ID (или ?last_event_id=).
+// События незадолго до Last-Event-ID могут прийти повторно — клиент отбрасывает их по id события;
+// если пропущено слишком много, вместо них приходит reset: состояние нужно загрузить заново.
+func (h *Handlers) StreamEvents(c *gin.Context) {
+	filter := stream.Filter{Team: c.Query("team"), User: c.Query("user")}
+	if filter.Team != "" && !h.authorize(c, schemas.PermTeamRead, schemas.AuthzResource{TeamName: filter.Team}) {
+		return
+	}
+	if filter.User != "" && !h.authorize(c, schemas.PermUsersRead, schemas.AuthzResource{TargetUserID: filter.User}) {
+		return
+	}
+
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("last_event_id")
+	}
+	var lastEventID int64
+	if lastID != "" {
+		var err error
+		if lastEventID, err = strconv.ParseInt(lastID, 10, 64); err != nil || lastEventID < 0 {
+			handleError(c, errors.ErrBadRequest.WithMessage("Last-Event-ID must be an event seq"))
+			return
+		}
+	}
+
+	sub, err := h.streamUsecase.Subscribe(c.Request.Context(), filter, lastEventID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	defer h.streamUsecase.Unsubscribe(sub)
+
+	// Поток живёт дольше таймаутов чтения и записи сервера; там, где их не снять, поток оборвётся
+	// и клиент переподключится с Last-Event-ID
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		h.streamWebSocket(c, sub)
+		return
+	}
+	h.streamSSE(c, sub, lastEventID)
+}
+
+// streamSSE не ставит id повторно отданным событиям не новее lastEventID, чтобы позиция
+// возобновления клиента не сдвигалась назад
+func (h *Handlers) streamSSE(c *gin.Context, sub *stream.Subscription, lastEventID int64) {
+	w := c.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(200)
+
+	write := func(e stream.Event) error {
+		data, err := json.Marshal(e.Event)
+		if err != nil {
+			return err
+		}
+		if e.Seq > lastEventID {
+			if _, err := fmt.Fprintf(w, "id: %d\n", e.Seq); err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+		return err
+	}
+	if sub.Reset {
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: {\"seq\":%d}\n\n", sub.ResetSeq, stream.ResetEvent, sub.ResetSeq); err != nil {
+			return
+		}
+	}
+	for _, e := range sub.Backlog {
+		if write(e) != nil {
+			return
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-h.closing:
+			return
+		case <-sub.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case e := <-sub.Events:
+			if sub.Replayed(e.Seq) {
+				continue
+			}
+			if write(e) != nil {
+				return
+			}
+		}
+		w.Flush()
+	}
+}
+
+// streamWebSocket шлёт события JSON-сообщениями {"seq", "id", "type", "occurred_at", "data"};
+// reset приходит сообщением {"type": "reset", "seq"}
+func (h *Handlers) streamWebSocket(c *gin.Context, sub *stream.Subscription) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return // Upgrade уже ответил клиенту ошибкой
+	}
+	defer conn.Close()
+
+	// Входящие сообщения не ожидаются; чтение нужно, чтобы заметить закрытие соединения клиентом
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if sub.Reset && conn.WriteJSON(gin.H{"type": stream.ResetEvent, "seq": sub.ResetSeq}) != nil {
+		return
+	}
+	for _, e := range sub.Backlog {
+		if conn.WriteJSON(e.OutboxEvent) != nil {
+			return
+		}
+	}
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-h.closing:
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseServiceRestart, "server is shutting down"))
+			return
+		case <-sub.Done():
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber too slow"))
+			return
+		case <-heartbeat.C:
+			if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)) != nil {
+				return
+			}
+		case e := <-sub.Events:
+			if sub.Replayed(e.Seq) {
+				continue
+			}
+			if conn.WriteJSON(e.OutboxEvent) != nil {
+				return
+			}
+		}
+	}
+}
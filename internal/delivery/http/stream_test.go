@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamUpgrader_ChecksOrigin(t *testing.T) {
+	upgrader := newUpgrader([]string{"https://dash.example.com"})
+	cases := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"", true},                         // не браузер
+		{"http://api.example.com", true},   // тот же адрес, что и API
+		{"https://dash.example.com", true}, // из STREAM_ALLOWED_ORIGINS
+		{"https://evil.example.com", false},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "http://api.example.com/api/v1/events/stream", nil)
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		assert.Equal(t, tc.allowed, upgrader.CheckOrigin(req), tc.origin)
+	}
+
+	req := httptest.NewRequest("GET", "http://api.example.com/api/v1/events/stream", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	assert.True(t, newUpgrader([]string{"*"}).CheckOrigin(req))
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWebhook подписывает URL на события (только admin). Секрет подписи возвращается один раз.
+func (h *Handlers) CreateWebhook(c *gin.Context) {
+	var req struct {
+		URL        string   `json:"url" binding:"required"`
+		Secret     string   `json:"secret"`
+		EventTypes []string `json:"event_types" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		handleError(c, bindError(err))
+		return
+	}
+
+	sub, secret, err := h.webhookUsecase.CreateSubscription(c.Request.Context(), req.URL, req.Secret, req.EventTypes, c.GetString("login"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(201, gin.H{"webhook": sub, "secret": secret})
+}
+
+func (h *Handlers) ListWebhooks(c *gin.Context) {
+	subs, err := h.webhookUsecase.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"webhooks": subs})
+}
+
+func (h *Handlers) DeleteWebhook(c *gin.Context) {
+	if err := h.webhookUsecase.DeleteSubscription(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+// ListWebhookDeliveries — журнал доставок (?status=dead — dead letter, ?limit=)
+func (h *Handlers) ListWebhookDeliveries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	deliveries, err := h.webhookUsecase.ListDeliveries(c.Request.Context(), c.Query("status"), limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"deliveries": deliveries})
+}
+
+// RedeliverWebhook повторно ставит доставку в очередь
+func (h *Handlers) RedeliverWebhook(c *gin.Context) {
+	if err := h.webhookUsecase.Redeliver(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(202, gin.H{"status": "queued"})
+}
+
+// GetOutboxProgress показывает, сколько событий outbox доставлено каждому получателю и сколько ждёт
+func (h *Handlers) GetOutboxProgress(c *gin.Context) {
+	progress, err := h.outboxUsecase.Progress(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"sinks": progress})
+}
@@ -1,13 +1,36 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
 	"ReviewAssigner/internal/pkg/jwt"
+	"ReviewAssigner/internal/usecase/apikey"
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// APIKeyAuthenticator проверяет API-ключи CI-ботов и интеграций
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, raw string) (*schemas.APIKey, error)
+}
+
+// AccessChecker проверяет, не отозван ли токен с валидной подписью
+type AccessChecker interface {
+	CheckAccess(ctx context.Context, claims *jwt.Claims) error
+}
+
+// BearerVerifier проверяет bearer-токены внешнего провайдера (OIDC) и переводит их в наши claims
+type BearerVerifier interface {
+	VerifyBearer(ctx context.Context, raw string) (*jwt.Claims, error)
+}
+
+// AuthMiddleware принимает JWT или API-ключ (Authorization: Bearer ra_... либо X-API-Key).
+// routeScopes задаёт скоуп для "METHOD /path"; маршруты без скоупа API-ключам недоступны.
+// Права ролей проверяет RBACMiddleware, который подключается следом.
+// Если задан external, токены, не выпущенные нами, проверяются у OIDC-провайдера.
+func AuthMiddleware(tokens *jwt.Manager, checker AccessChecker, apiKeys APIKeyAuthenticator, external BearerVerifier, routeScopes map[string]string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Публичные эндпоинты — пропускаем без проверки токена
 		if c.Request.URL.Path == "/health" || c.Request.URL.Path == "/auth/login" {
@@ -16,47 +39,59 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		authHeader := c.GetHeader("Authorization")
+		if rawKey := c.GetHeader("X-API-Key"); rawKey != "" {
+			authenticateAPIKey(c, apiKeys, routeScopes, rawKey)
+			return
+		}
+		if rawKey := strings.TrimPrefix(authHeader, "Bearer "); apikey.IsAPIKey(rawKey) {
+			authenticateAPIKey(c, apiKeys, routeScopes, rawKey)
+			return
+		}
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(401, gin.H{
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "Missing or invalid Authorization header",
-				},
-			})
-			c.Abort()
+			abortWithError(c, errors.ErrUnauthorized.WithMessage("Missing or invalid Authorization header"))
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		claims, err := jwt.ValidateToken(tokenString)
+		claims, err := tokens.ValidateToken(tokenString)
+		if err != nil && external != nil {
+			claims, err = external.VerifyBearer(c.Request.Context(), tokenString)
+		}
 		if err != nil {
-			c.JSON(401, gin.H{
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "Invalid or expired token",
-				},
-			})
-			c.Abort()
+			abortWithError(c, errors.ErrUnauthorized.WithMessage("Invalid or expired token"))
+			return
+		}
+
+		if err := checker.CheckAccess(c.Request.Context(), claims); err != nil {
+			abortWithError(c, errors.ErrUnauthorized.WithMessage("Token has been revoked"))
 			return
 		}
 
 		// Сохраняем данные пользователя в контекст (на случай, если понадобится в хендлерах)
 		c.Set("user_id", claims.UserID)
+		c.Set("login", claims.Login)
 		c.Set("role", claims.Role)
-
-		// Защита мутирующих операций — только admin
-		if c.Request.Method != "GET" && claims.Role != "admin" {
-			c.JSON(403, gin.H{
-				"error": gin.H{
-					"code":    "FORBIDDEN",
-					"message": "Admin role required for this operation",
-				},
-			})
-			c.Abort()
-			return
-		}
+		c.Set("claims", claims)
 
 		c.Next()
 	}
 }
+
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyAuthenticator, routeScopes map[string]string, rawKey string) {
+	key, err := apiKeys.Authenticate(c.Request.Context(), rawKey)
+	if err != nil {
+		abortWithError(c, errors.ErrUnauthorized.WithMessage("Invalid, expired or revoked API key"))
+		return
+	}
+
+	scope, ok := routeScopes[RouteKey(c)]
+	if !ok || !key.HasScope(scope) {
+		abortWithError(c, errors.ErrForbidden.WithMessage("API key does not have the required scope"))
+		return
+	}
+
+	c.Set("user_id", "api-key:"+key.ID)
+	c.Set("api_key", key)
+	c.Next()
+}
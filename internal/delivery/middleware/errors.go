@@ -0,0 +1,12 @@
+package middleware
+
+import (
+	"ReviewAssigner/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// abortWithError прерывает цепочку ответом в едином формате ошибок
+func abortWithError(c *gin.Context, err *errors.Error) {
+	c.AbortWithStatusJSON(err.Status, gin.H{"error": err})
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyStore — хранилище ответов по ключу идемпотентности (в памяти или общее в postgres)
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, record *schemas.IdempotencyRecord) (*schemas.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *schemas.IdempotencyRecord) error
+	Release(ctx context.Context, key string) error
+}
+
+const (
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL — сколько ключ считается занятым выполняющимся запросом;
+	// ключ инстанса, упавшего посреди запроса, освободится сам
+	idempotencyLockTTL = time.Minute
+)
+
+// IdempotencyMiddleware запоминает ответ на POST-запрос с заголовком Idempotency-Key на ttl
+// и отдаёт его повторам с тем же ключом и телом как есть (вместе с заголовками из
+// schemas.IdempotencyReplayHeaders), с заголовком Idempotent-Replayed: true.
+// Ключ принадлежит клиенту (API-ключ, логин или IP), поэтому подключается после AuthMiddleware.
+// Тот же ключ с другим запросом — 409 IDEMPOTENCY_KEY_REUSED, повтор до ответа на исходный
+// запрос — 409 REQUEST_IN_PROGRESS. Ответы 5xx не запоминаются: повтор выполнит запрос заново.
+func IdempotencyMiddleware(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(schemas.IdempotencyHeader)
+		if key == "" || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abortWithError(c, errors.ErrBadRequest.WithMessage(schemas.IdempotencyHeader+" must be at most "+
+				strconv.Itoa(maxIdempotencyKeyLength)+" characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithError(c, readBodyError(err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &schemas.IdempotencyRecord{
+			Key:         clientKey(c) + ":" + key,
+			RequestHash: requestHash(c.Request, body),
+			ExpiresAt:   time.Now().Add(idempotencyLockTTL),
+		}
+		existing, err := store.Reserve(c.Request.Context(), record)
+		if err != nil {
+			// Недоступность хранилища не должна останавливать API
+			log.Printf("idempotency store error: %v", err)
+			c.Next()
+			return
+		}
+		if existing != nil {
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				abortWithError(c, errors.ErrIdempotencyKeyReused)
+			case !existing.Completed():
+				abortWithError(c, errors.ErrRequestInProgress)
+			default:
+				for name, value := range existing.Headers {
+					c.Header(name, value)
+				}
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.Status, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		writer := &teeWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		// Ответ уже сформирован: запись ключа не должна сорваться из-за отключившегося клиента
+		done := context.WithoutCancel(c.Request.Context())
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			if err := store.Release(done, record.Key); err != nil {
+				log.Printf("idempotency store error: %v", err)
+			}
+			return
+		}
+		record.Status = c.Writer.Status()
+		record.ContentType = c.Writer.Header().Get("Content-Type")
+		record.Headers = map[string]string{}
+		for _, name := range schemas.IdempotencyReplayHeaders {
+			if value := c.Writer.Header().Get(name); value != "" {
+				record.Headers[name] = value
+			}
+		}
+		record.Body = writer.body.Bytes()
+		record.ExpiresAt = time.Now().Add(ttl)
+		if err := store.Complete(done, record); err != nil {
+			log.Printf("idempotency store error: %v", err)
+		}
+	}
+}
+
+// requestHash — отпечаток метода, пути без версии, параметров и тела: алиас и /api/v1 считаются одним запросом
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+trimVersion(r.URL.Path)+"?"+r.URL.RawQuery+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// teeWriter пишет ответ клиенту и одновременно копирует тело для сохранения
+type teeWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *teeWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *teeWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"ReviewAssigner/internal/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBodySize ограничивает тело запроса limit байтами: больший Content-Length отклоняется сразу,
+// тело без длины обрывается при чтении. limit <= 0 — без ограничения.
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			abortWithError(c, errors.ErrPayloadTooLarge)
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
+
+// readBodyError — ошибка чтения тела: превышение MaxBodySize или BAD_REQUEST
+func readBodyError(err error) *errors.Error {
+	if tooLarge(err) {
+		return errors.ErrPayloadTooLarge.Wrap(err)
+	}
+	return errors.ErrBadRequest.WithMessage("cannot read request body").Wrap(err)
+}
+
+func tooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return stderrors.As(err, &maxBytesErr)
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"bytes"
+	stderrors "errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"ReviewAssigner/internal/pkg/errors"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+)
+
+// Режимы OpenAPIValidation
+const (
+	OpenAPIValidationOff      = "off"
+	OpenAPIValidationRequests = "requests"
+	OpenAPIValidationAll      = "all"
+)
+
+// OpenAPIValidation проверяет запросы (и в режиме "all" — ответы) по спецификации.
+// Неверный запрос отклоняется с 400 BAD_REQUEST до хендлера; ответ, расходящийся со спецификацией,
+// заменяется на 500 RESPONSE_VALIDATION — режим для тестов и стендов, а не для продакшена.
+// Аутентификацию проверяет AuthMiddleware, поэтому схемы безопасности здесь не проверяются.
+// Маршруты, которых нет в спецификации, пропускаются; подключается до регистрации маршрутов.
+func OpenAPIValidation(doc *openapi3.T, mode string) (gin.HandlerFunc, error) {
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	options := &openapi3filter.Options{
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		IncludeResponseStatus: true,
+	}
+	validateResponses := mode == OpenAPIValidationAll
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			abortWithError(c, requestError(err))
+			return
+		}
+
+		// Потоковые ответы не буферизуются
+		if !validateResponses || isStream(route) {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		body := writer.body.Bytes()
+		err = openapi3filter.ValidateResponse(c.Request.Context(), (&openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 writer.status,
+			Header:                 writer.Header(),
+			Options:                options,
+		}).SetBodyBytes(body))
+		if err != nil {
+			log.Printf("openapi: response of %s %s does not match spec: %v", c.Request.Method, c.Request.URL.Path, err)
+			apiErr := errors.ErrResponseValidation.WithMessage(err.Error()).Wrap(err)
+			c.Writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+			c.JSON(apiErr.Status, gin.H{"error": apiErr})
+			return
+		}
+		c.Writer.WriteHeader(writer.status)
+		c.Writer.Write(body)
+	}, nil
+}
+
+// requestError переводит ошибку проверки запроса в BAD_REQUEST с указанием неверного поля или параметра
+func requestError(err error) *errors.Error {
+	if tooLarge(err) {
+		return errors.ErrPayloadTooLarge.Wrap(err)
+	}
+	apiErr := errors.ErrBadRequest.WithMessage(err.Error()).Wrap(err)
+	var reqErr *openapi3filter.RequestError
+	if !stderrors.As(err, &reqErr) {
+		return apiErr
+	}
+
+	field, message := "", reqErr.Reason
+	if reqErr.Parameter != nil {
+		field = reqErr.Parameter.Name
+	}
+	var schemaErr *openapi3.SchemaError
+	if stderrors.As(err, &schemaErr) {
+		if path := strings.Join(schemaErr.JSONPointer(), "."); path != "" {
+			field = strings.TrimPrefix(field+"."+path, ".")
+		}
+		message = schemaErr.Reason
+	} else if reqErr.Err != nil {
+		message = reqErr.Err.Error()
+	}
+	if message == "" {
+		return apiErr
+	}
+	if field == "" {
+		field = "body"
+	}
+	return apiErr.WithFields(errors.FieldError{Field: field, Message: message})
+}
+
+// isStream — операция помечена в спецификации x-stream: true
+func isStream(route *routers.Route) bool {
+	stream, _ := route.Operation.Extensions["x-stream"].(bool)
+	return stream
+}
+
+// bufferedWriter придерживает статус и тело ответа до проверки; заголовки пишутся сразу в исходный writer
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
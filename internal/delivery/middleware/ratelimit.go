@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter — хранилище корзин token bucket (в памяти или общее в postgres)
+type RateLimiter interface {
+	Take(ctx context.Context, key string, limit schemas.RateLimit) (schemas.RateLimitResult, error)
+}
+
+// RateLimitMiddleware ограничивает частоту запросов по группам маршрутов (см. RateLimitGroup).
+// Клиент определяется по API-ключу, логину из токена или IP, поэтому на защищённых маршрутах
+// middleware подключается после AuthMiddleware. Группы без лимита не ограничиваются.
+func RateLimitMiddleware(limiter RateLimiter, limits map[string]schemas.RateLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		group := RateLimitGroup(c.Request.Method, c.Request.URL.Path)
+		limit, ok := limits[group]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.Take(c.Request.Context(), group+":"+clientKey(c), limit)
+		if err != nil {
+			// Недоступность хранилища не должна останавливать API
+			log.Printf("rate limit store error: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+			abortWithError(c, errors.ErrRateLimited)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RateLimitGroup относит запрос к группе лимитов; пути с /api/v1 и без него попадают в одну группу
+func RateLimitGroup(method, path string) string {
+	path = trimVersion(path)
+	switch {
+	case path == "/auth/login" || path == "/auth/refresh" || strings.HasPrefix(path, "/auth/oidc/"):
+		return schemas.RateLimitGroupAuth
+	case strings.HasPrefix(path, "/admin/") || path == "/auth/accounts" || path == "/auth/password/set" || path == "/auth/revoke":
+		return schemas.RateLimitGroupAdmin
+	case method == "GET":
+		return schemas.RateLimitGroupRead
+	}
+	return schemas.RateLimitGroupWrite
+}
+
+// clientKey — API-ключ, затем логин из токена, затем IP для анонимных запросов
+func clientKey(c *gin.Context) string {
+	if key, ok := c.Get("api_key"); ok {
+		return "key:" + key.(*schemas.APIKey).ID
+	}
+	if login := c.GetString("login"); login != "" {
+		return "user:" + login
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Authorizer проверяет, есть ли у principal право на действие хотя бы над каким-то объектом.
+// Проверка конкретного объекта (команды, PR) выполняется в хендлерах.
+type Authorizer interface {
+	Allowed(ctx context.Context, p *schemas.Principal, perm string) (bool, error)
+}
+
+// PrincipalFromContext собирает principal из данных, сохранённых AuthMiddleware
+func PrincipalFromContext(c *gin.Context) *schemas.Principal {
+	p := &schemas.Principal{
+		Login:  c.GetString("login"),
+		UserID: c.GetString("user_id"),
+		Role:   c.GetString("role"),
+	}
+	if key, ok := c.Get("api_key"); ok {
+		p.APIKey = key.(*schemas.APIKey)
+	}
+	return p
+}
+
+// RBACMiddleware проверяет право на маршрут по routePermissions ("METHOD /path" без /api/v1 -> право).
+// Пустое право — маршрут доступен любому аутентифицированному пользователю; маршруты без записи закрыты.
+func RBACMiddleware(authz Authorizer, routePermissions map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		perm, ok := routePermissions[RouteKey(c)]
+		if ok && perm == "" {
+			c.Next()
+			return
+		}
+
+		allowed := false
+		if ok {
+			var err error
+			allowed, err = authz.Allowed(c.Request.Context(), PrincipalFromContext(c), perm)
+			if err != nil {
+				abortWithError(c, errors.From(err))
+				return
+			}
+		}
+		if !allowed {
+			abortWithError(c, errors.ErrForbidden.WithMessage("Insufficient permissions for this operation"))
+			return
+		}
+
+		c.Next()
+	}
+}
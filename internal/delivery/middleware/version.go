@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIPrefix — префикс текущей версии API. Те же маршруты без префикса — устаревшие синонимы.
+const APIPrefix = "/api/v1"
+
+// RouteKey — "METHOD /path" шаблона маршрута без префикса версии; по нему ищутся права и скоупы
+func RouteKey(c *gin.Context) string {
+	return c.Request.Method + " " + trimVersion(c.FullPath())
+}
+
+// Deprecated помечает ответы старых путей без версии заголовками Deprecation и Link на путь в /api/v1
+func Deprecated() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Link", "<"+APIPrefix+c.Request.URL.Path+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
+
+func trimVersion(path string) string {
+	if rest := strings.TrimPrefix(path, APIPrefix); rest != path && strings.HasPrefix(rest, "/") {
+		return rest
+	}
+	return path
+}
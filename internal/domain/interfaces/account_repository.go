@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"time"
+)
+
+type AccountRepository interface {
+	Create(ctx context.Context, account *schemas.Account) error
+	GetByLogin(ctx context.Context, login string) (*schemas.Account, error)
+	GetByUserID(ctx context.Context, userID string) (*schemas.Account, error)
+	UpdatePassword(ctx context.Context, login string, passwordHash string) error
+	IncrementFailedAttempts(ctx context.Context, login string) (int, error) // Возвращает новое число неудачных попыток
+	Lock(ctx context.Context, login string, until time.Time) error
+	ResetFailedAttempts(ctx context.Context, login string) error
+	UpdateRole(ctx context.Context, login string, role string) error
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"time"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *schemas.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*schemas.APIKey, error)
+	List(ctx context.Context) ([]schemas.APIKey, error)
+	Revoke(ctx context.Context, id string) (bool, error) // false — ключ не найден или уже отозван
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+// IdempotencyRepository хранит ответы на запросы с Idempotency-Key.
+// Reserve атомарно занимает ключ: nil — ключ свободен (или истёк) и теперь занят этим запросом,
+// иначе возвращается существующая запись. Release освобождает ключ, ответ на который не сохраняется.
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *schemas.IdempotencyRecord) (*schemas.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *schemas.IdempotencyRecord) error
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type NotificationPreferenceRepository interface {
+	Upsert(ctx context.Context, pref *schemas.NotificationPreference) error
+	ListByUser(ctx context.Context, userID string) ([]schemas.NotificationPreference, error)
+	Delete(ctx context.Context, userID, channel string) (bool, error)
+}
+
+type DigestSettingRepository interface {
+	Upsert(ctx context.Context, setting *schemas.DigestSetting) error
+	GetByUser(ctx context.Context, userID string) (*schemas.DigestSetting, error)
+	Delete(ctx context.Context, userID string) (bool, error)
+	ListEnabled(ctx context.Context) ([]schemas.DigestSetting, error)
+	// MarkSent отмечает сводку за местную дату day отправленной; false — её уже отправил другой инстанс
+	MarkSent(ctx context.Context, userID, day string) (bool, error)
+	// ListConfiguredUserIDs — пользователи со своей настройкой, в том числе выключенной
+	ListConfiguredUserIDs(ctx context.Context) ([]string, error)
+	// ListDefaultSent — userID -> местная дата последней сводки по расписанию по умолчанию
+	ListDefaultSent(ctx context.Context) (map[string]string, error)
+	// MarkDefaultSent — MarkSent для пользователей без своей настройки
+	MarkDefaultSent(ctx context.Context, userID, day string) (bool, error)
+}
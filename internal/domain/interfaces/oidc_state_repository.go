@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type OIDCStateRepository interface {
+	Save(ctx context.Context, state *schemas.OIDCLoginState) error
+	Consume(ctx context.Context, state string) (*schemas.OIDCLoginState, error) // Удаляет и возвращает state; nil, если не найден
+}
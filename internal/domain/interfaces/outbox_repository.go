@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+// OutboxRepository — журнал доменных событий и прогресс их доставки получателям (sink).
+// Репозитории, меняющие состояние, пишут события в outbox в той же транзакции.
+type OutboxRepository interface {
+	Append(ctx context.Context, events ...*schemas.Event) error
+	// Pending — события, ещё не доставленные получателю, в порядке записи
+	Pending(ctx context.Context, sink string, limit int) ([]schemas.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, sink string, seq int64) error
+	RecordFailure(ctx context.Context, sink string, message string) error
+	// ClaimSink продлевает аренду получателя за owner; false — получателя обслуживает другой инстанс
+	ClaimSink(ctx context.Context, sink, owner string, lease time.Duration) (bool, error)
+	Progress(ctx context.Context, sinks []string) ([]schemas.OutboxSinkProgress, error)
+	// After — события с Seq больше seq по порядку; для живого потока и его возобновления
+	After(ctx context.Context, seq int64, limit int) ([]schemas.OutboxEvent, error)
+	LastSeq(ctx context.Context) (int64, error)
+	// Purge удаляет события старше before, доставленные всем sinks, вместе с отметками о доставке;
+	// недоставленные хотя бы одному получателю события остаются
+	Purge(ctx context.Context, before time.Time, sinks []string) (int, error)
+}
@@ -1,16 +1,24 @@
 package interfaces
 
 import (
+	"context"
 	"ReviewAssigner/internal/domain/schemas"
 	"time"
 )
 
+// Методы записи принимают доменные события: они попадают в outbox в одной транзакции с изменением.
+// UpdateStatus и UpdateReviewers меняют PR, только если его версия равна version, и увеличивают её;
+// иначе возвращают errors.ErrVersionConflict.
 type PullRequestRepository interface {
-    Create(pr *schemas.PullRequest) error
-    GetByID(id string) (*schemas.PullRequest, error)
-    UpdateStatus(id string, status string, mergedAt *time.Time) (*schemas.PullRequest, error)
-    UpdateReviewers(id string, reviewers []string) error
-    GetByReviewerID(userID string) ([]schemas.PullRequestShort, error)
-    Exists(id string) (bool, error)
-    GetStats() (map[string]int, map[string]int, error) // userStats, prStats
+    Create(ctx context.Context, pr *schemas.PullRequest, events ...*schemas.Event) error
+    GetByID(ctx context.Context, id string) (*schemas.PullRequest, error)
+    GetByIDForUpdate(ctx context.Context, id string) (*schemas.PullRequest, error) // Блокирует PR до конца единицы работы (TxManager)
+    GetByIDs(ctx context.Context, ids []string) ([]schemas.PullRequest, error) // Пакетная загрузка с ревьюверами; отсутствующие пропускаются
+    UpdateStatus(ctx context.Context, id string, version int, status string, mergedAt *time.Time, events ...*schemas.Event) (*schemas.PullRequest, error)
+    UpdateReviewers(ctx context.Context, id string, version int, reviewers []string, events ...*schemas.Event) error
+    UpdateSCMSync(ctx context.Context, id string, state schemas.SCMSyncState) error
+    GetByReviewerID(ctx context.Context, userID string) ([]schemas.PullRequestShort, error)
+    GetByReviewerIDs(ctx context.Context, userIDs []string) (map[string][]schemas.PullRequestShort, error) // userID -> PR на ревью
+    Exists(ctx context.Context, id string) (bool, error)
+    GetStats(ctx context.Context) (map[string]int, map[string]int, error) // userStats, prStats
 }
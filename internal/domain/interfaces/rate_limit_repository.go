@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+// RateLimitRepository хранит корзины token bucket; Take должен быть атомарным для одного ключа
+type RateLimitRepository interface {
+	Take(ctx context.Context, key string, limit schemas.RateLimit) (schemas.RateLimitResult, error)
+}
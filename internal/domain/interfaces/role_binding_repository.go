@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type RoleBindingRepository interface {
+	Create(ctx context.Context, binding *schemas.RoleBinding) error
+	Delete(ctx context.Context, binding *schemas.RoleBinding) (bool, error)
+	ListByLogin(ctx context.Context, login string) ([]schemas.RoleBinding, error)
+	List(ctx context.Context) ([]schemas.RoleBinding, error)
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type SCMIdentityRepository interface {
+	Upsert(ctx context.Context, identity *schemas.SCMIdentity) error
+	Delete(ctx context.Context, provider, username string) (bool, error)
+	GetByUsername(ctx context.Context, provider, username string) (*schemas.SCMIdentity, error)
+	GetByUserID(ctx context.Context, provider, userID string) (*schemas.SCMIdentity, error)
+	List(ctx context.Context, provider string) ([]schemas.SCMIdentity, error) // пустой provider — все
+}
+
+// SCMDeliveryRepository запоминает обработанные доставки вебхуков для дедупликации
+type SCMDeliveryRepository interface {
+	IsProcessed(ctx context.Context, provider, deliveryID string) (bool, error)
+	MarkProcessed(ctx context.Context, provider, deliveryID string) error
+}
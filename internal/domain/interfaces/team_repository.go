@@ -1,9 +1,16 @@
 package interfaces
 
-import "ReviewAssigner/internal/domain/schemas"
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
 
 type TeamRepository interface {
-    Create(team *schemas.Team) error
-    GetByName(name string) (*schemas.Team, error)
-    Exists(name string) (bool, error)
+    Create(ctx context.Context, team *schemas.Team) error
+    GetByName(ctx context.Context, name string) (*schemas.Team, error)
+    GetByNames(ctx context.Context, names []string) ([]schemas.Team, error) // Пакетная загрузка с участниками; отсутствующие пропускаются
+    Exists(ctx context.Context, name string) (bool, error)
+    List(ctx context.Context) ([]schemas.Team, error)              // Все команды с участниками
+    ApplyRoster(ctx context.Context, diff *schemas.RosterDiff) error // Применяет изменения импорта одной транзакцией
 }
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"time"
+)
+
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *schemas.RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*schemas.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, tokenHash string) (bool, error) // false — токен уже был отозван
+	RevokeRefreshFamily(ctx context.Context, familyID string) error
+	RevokeRefreshTokensByLogin(ctx context.Context, login string) error
+
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	SetCutoff(ctx context.Context, login string, issuedBefore time.Time) error
+	GetCutoff(ctx context.Context, login string) (*time.Time, error)
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import "context"
+
+// TxRepositories — репозитории, привязанные к одной единице работы
+type TxRepositories struct {
+	Users        UserRepository
+	Teams        TeamRepository
+	PullRequests PullRequestRepository
+}
+
+// TxManager выполняет несколько вызовов репозиториев атомарно: fn получает репозитории одной транзакции,
+// ошибка fn откатывает все изменения вместе с событиями outbox. Методы *ForUpdate/*ForShare блокируют
+// прочитанные строки до конца единицы работы. Единицы работы не вкладываются друг в друга.
+// Отмена ctx или истечение его дедлайна откатывает транзакцию.
+type TxManager interface {
+	Do(ctx context.Context, fn func(repos TxRepositories) error) error
+}
@@ -1,9 +1,16 @@
 package interfaces
 
-import "ReviewAssigner/internal/domain/schemas"
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
 
 type UserRepository interface {
-    GetByID(userID string) (*schemas.User, error)
-    UpdateIsActive(userID string, isActive bool) (*schemas.User, error)
-    GetActiveByTeam(teamName string, excludeUserID string) ([]schemas.User, error) // Для выбора ревьюверов
+    GetByID(ctx context.Context, userID string) (*schemas.User, error)
+    GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) // Пакетная загрузка; отсутствующие пропускаются
+    UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) // события пишутся в outbox в той же транзакции
+    GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) // Для выбора ревьюверов
+    GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) // То же с блокировкой кандидатов до конца единицы работы
+    List(ctx context.Context) ([]schemas.User, error)
 }
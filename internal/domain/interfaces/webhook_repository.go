@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type WebhookSubscriptionRepository interface {
+	Create(ctx context.Context, sub *schemas.WebhookSubscription) error
+	GetByID(ctx context.Context, id string) (*schemas.WebhookSubscription, error)
+	List(ctx context.Context) ([]schemas.WebhookSubscription, error)
+	Delete(ctx context.Context, id string) (bool, error)
+}
+
+type WebhookDeliveryRepository interface {
+	// Create сохраняет доставки; уже существующая пара подписка+событие пропускается,
+	// поэтому повторная публикация события не отправляет его подписчику дважды
+	Create(ctx context.Context, deliveries []schemas.WebhookDelivery) error
+	// ClaimDue забирает до limit ожидающих доставок, чей срок наступил, и откладывает их на lease,
+	// чтобы другой инстанс не отправил их одновременно
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]schemas.WebhookDelivery, error)
+	Update(ctx context.Context, delivery *schemas.WebhookDelivery) error
+	List(ctx context.Context, status string, limit int) ([]schemas.WebhookDelivery, error) // пустой status — все
+	Requeue(ctx context.Context, id string, at time.Time) (bool, error)
+}
+
+// EventPublisher принимает доменные события после изменения состояния
+type EventPublisher interface {
+	Publish(ctx context.Context, event *schemas.Event) error
+}
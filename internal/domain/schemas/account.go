@@ -0,0 +1,25 @@
+package schemas
+
+import "time"
+
+
+// Account — учётная запись для входа. UserID связывает её с доменным пользователем (может быть пустым
+// для служебных учёток, например bootstrap-админа).
+type Account struct {
+	Login             string     `json:"login" db:"login"`
+	UserID            string     `json:"user_id,omitempty" db:"user_id"`
+	PasswordHash      string     `json:"-" db:"password_hash"`
+	Role              string     `json:"role" db:"role"`
+	FailedAttempts    int        `json:"-" db:"failed_attempts"`
+	LockedUntil       *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty" db:"password_changed_at"`
+	CreatedAt         *time.Time `json:"created_at,omitempty" db:"created_at"`
+}
+
+// Subject — идентификатор, который попадает в токен: доменный пользователь, если привязан, иначе логин
+func (a *Account) Subject() string {
+	if a.UserID != "" {
+		return a.UserID
+	}
+	return a.Login
+}
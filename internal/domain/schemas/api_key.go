@@ -0,0 +1,41 @@
+package schemas
+
+import "time"
+
+// Скоупы API-ключей совпадают с правами RBAC
+const (
+	ScopeTeamRead   = PermTeamRead
+	ScopeUsersRead  = PermUsersRead
+	ScopeUsersWrite = PermUsersSetActive
+	ScopePRCreate   = PermPRCreate
+	ScopePRMerge    = PermPRMerge
+	ScopePRReassign = PermPRReassign
+	ScopeStatsRead  = PermStatsRead
+)
+
+var KnownScopes = []string{
+	ScopeTeamRead, ScopeUsersRead, ScopeUsersWrite,
+	ScopePRCreate, ScopePRMerge, ScopePRReassign, ScopeStatsRead,
+}
+
+// APIKey — ключ для CI и интеграций. Сам ключ показывается один раз при создании, хранится только хеш.
+type APIKey struct {
+	ID         string     `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Scopes     []string   `json:"scopes" db:"-"`
+	CreatedBy  string     `json:"created_by" db:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  *time.Time `json:"created_at,omitempty" db:"created_at"`
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package schemas
+
+import (
+	"fmt"
+	"time"
+)
+
+// DigestSetting — когда и по какому каналу пользователь получает ежедневную сводку ревью.
+// Адрес берётся из настройки уведомлений пользователя для того же канала.
+type DigestSetting struct {
+	UserID     string `json:"user_id" db:"user_id"`
+	Channel    string `json:"channel" db:"channel"`
+	SendAt     string `json:"send_at" db:"send_at"`   // "HH:MM" по местному времени пользователя
+	Timezone   string `json:"timezone" db:"timezone"` // IANA, например "Europe/Moscow"
+	Enabled    bool   `json:"enabled" db:"enabled"`
+	LastSentOn string `json:"last_sent_on,omitempty" db:"last_sent_on"` // местная дата последней сводки, "2006-01-02"
+}
+
+// PendingReview — открытый PR из сводки и сколько он ждёт ревью (с момента создания)
+type PendingReview struct {
+	PullRequestShort
+	WaitingSeconds int64  `json:"waiting_seconds"`
+	Waiting        string `json:"waiting"` // "2d 5h", "3h 10m"
+}
+
+// Digest — сводка ожидающих ревью пользователя
+type Digest struct {
+	User        *User           `json:"user"`
+	Reviews     []PendingReview `json:"reviews"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// FormatWaiting округляет ожидание до двух старших единиц: дни и часы или часы и минуты
+func FormatWaiting(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
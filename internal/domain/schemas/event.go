@@ -0,0 +1,57 @@
+package schemas
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Типы доменных событий
+const (
+	EventPRCreated           = "pr.created"
+	EventPRReassigned        = "pr.reassigned"
+	EventPRMerged            = "pr.merged"
+	EventUserActivityChanged = "user.activity_changed"
+)
+
+var KnownEventTypes = []string{EventPRCreated, EventPRReassigned, EventPRMerged, EventUserActivityChanged}
+
+func IsKnownEventType(eventType string) bool {
+	for _, t := range KnownEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event — доменное событие; Data — payload конкретного типа (PREventData, UserEventData)
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type PREventData struct {
+	PR               *PullRequest `json:"pr"`
+	ReplacedReviewer string       `json:"replaced_reviewer,omitempty"` // только для pr.reassigned
+	NewReviewer      string       `json:"new_reviewer,omitempty"`
+}
+
+type UserEventData struct {
+	User *User `json:"user"`
+}
+
+func NewEvent(eventType string, data interface{}) (*Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return &Event{ID: hex.EncodeToString(id), Type: eventType, OccurredAt: time.Now().UTC(), Data: raw}, nil
+}
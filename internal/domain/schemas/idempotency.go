@@ -0,0 +1,27 @@
+package schemas
+
+import "time"
+
+// IdempotencyHeader — заголовок, по которому повторы POST-запроса узнают исходный ответ
+const IdempotencyHeader = "Idempotency-Key"
+
+// IdempotencyReplayHeaders — заголовки ответа, которые повтор получает вместе с телом:
+// версия PR для следующего If-Match, адрес созданного ресурса и пометки устаревшего пути
+var IdempotencyReplayHeaders = []string{"ETag", "Location", "Deprecation", "Link"}
+
+// IdempotencyRecord — запрос с ключом идемпотентности и его ответ. Пока запрос выполняется,
+// Status == 0; после ответа запись хранится до ExpiresAt и отдаётся повторам как есть.
+type IdempotencyRecord struct {
+	Key         string            `db:"key"`          // клиент + значение заголовка
+	RequestHash string            `db:"request_hash"` // метод, путь и тело запроса
+	Status      int               `db:"status"`
+	ContentType string            `db:"content_type"`
+	Headers     map[string]string `db:"-"` // заголовки ответа из IdempotencyReplayHeaders
+	Body        []byte            `db:"body"`
+	ExpiresAt   time.Time         `db:"expires_at"`
+}
+
+// Completed — ответ уже сохранён
+func (r *IdempotencyRecord) Completed() bool {
+	return r.Status != 0
+}
@@ -0,0 +1,21 @@
+package schemas
+
+import "time"
+
+// NotificationPreference — канал, по которому пользователь получает уведомления, и его адрес в канале
+type NotificationPreference struct {
+	UserID    string     `json:"user_id" db:"user_id"`
+	Channel   string     `json:"channel" db:"channel"`
+	Address   string     `json:"address" db:"address"` // e-mail, "@username" или chat_id Telegram
+	Enabled   bool       `json:"enabled" db:"enabled"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// NotificationData — данные для шаблонов уведомлений
+type NotificationData struct {
+	User             *User
+	PR               *PullRequest
+	ReplacedReviewer string
+	NewReviewer      string
+	Reviews          []PendingReview // сводка ожидающих ревью
+}
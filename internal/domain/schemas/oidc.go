@@ -0,0 +1,16 @@
+package schemas
+
+import "time"
+
+// SSOLoginPrefix — префикс логина учёток, созданных при первом входе через OIDC
+const SSOLoginPrefix = "oidc:"
+
+// OIDCLoginState — незавершённый вход через OIDC: state из редиректа связывает callback
+// с nonce и PKCE-верификатором, которые не должны покидать сервер
+type OIDCLoginState struct {
+	State        string     `db:"state"`
+	Nonce        string     `db:"nonce"`
+	CodeVerifier string     `db:"code_verifier"`
+	ExpiresAt    time.Time  `db:"expires_at"`
+	CreatedAt    *time.Time `db:"created_at"`
+}
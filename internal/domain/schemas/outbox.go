@@ -0,0 +1,17 @@
+package schemas
+
+// OutboxEvent — доменное событие из outbox; Seq задаёт порядок записи
+type OutboxEvent struct {
+	Seq int64 `json:"seq"`
+	Event
+}
+
+// OutboxSinkProgress — как далеко получатель продвинулся по outbox
+type OutboxSinkProgress struct {
+	Sink             string `json:"sink"`
+	Delivered        int    `json:"delivered"` // среди ещё хранящихся событий
+	Pending          int    `json:"pending"`
+	LastDeliveredSeq int64  `json:"last_delivered_seq"`
+	Failures         int    `json:"failures"` // неудачи подряд; сбрасываются успешной доставкой
+	LastError        string `json:"last_error,omitempty"`
+}
@@ -1,22 +1,34 @@
 package schemas
 
-import "time"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
 
 type PullRequest struct {
     ID                string    `json:"pull_request_id" db:"pull_request_id"`
     Name              string    `json:"pull_request_name" db:"pull_request_name"`
     AuthorID          string    `json:"author_id" db:"author_id"`
-    Status            string    `json:"status" db:"status"` // OPEN or MERGED
+    Status            string    `json:"status" db:"status"` // OPEN, MERGED или CLOSED (закрыт без merge)
     AssignedReviewers []string  `json:"assigned_reviewers"` // Не в БД напрямую, вычисляется из pr_reviewers
     CreatedAt         *time.Time `json:"createdAt,omitempty" db:"created_at"`
     MergedAt          *time.Time `json:"mergedAt,omitempty" db:"merged_at"`
+    // Растёт при каждой смене статуса или ревьюверов; отдаётся клиенту как ETag
+    Version           int        `json:"version" db:"version"`
+    // Отправка ревьюверов Git-хостингу; пусто у PR, заведённых не из вебхука
+    SCMSyncStatus     string     `json:"scm_sync_status,omitempty" db:"scm_sync_status"`
+    SCMSyncError      string     `json:"scm_sync_error,omitempty" db:"scm_sync_error"`
+    SCMSyncAttempts   int        `json:"scm_sync_attempts,omitempty" db:"scm_sync_attempts"`
+    SCMSyncedAt       *time.Time `json:"scm_synced_at,omitempty" db:"scm_synced_at"`
   }
 
   type PullRequestShort struct {
-    ID       string `json:"pull_request_id"`
-    Name     string `json:"pull_request_name"`
-    AuthorID string `json:"author_id"`
-    Status   string `json:"status"`
+    ID        string     `json:"pull_request_id" db:"pull_request_id"`
+    Name      string     `json:"pull_request_name" db:"pull_request_name"`
+    AuthorID  string     `json:"author_id" db:"author_id"`
+    Status    string     `json:"status" db:"status"`
+    CreatedAt *time.Time `json:"createdAt,omitempty" db:"created_at"`
   }
 
   type PRStats struct {
@@ -25,3 +37,23 @@ type PullRequest struct {
 	MergedPRs   int `json:"merged_prs"`
 	ReviewCount int `json:"review_count"`
 }
+
+// ETag — версия PR в формате заголовка ETag
+func (pr *PullRequest) ETag() string {
+	return strconv.Quote(strconv.Itoa(pr.Version))
+}
+
+// ParseIfMatch читает ожидаемую версию PR из If-Match ("3" или W/"3");
+// пустое значение и * — без проверки (0), ok == false — значение не похоже на ETag PR
+func ParseIfMatch(value string) (version int, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "*" {
+		return 0, true
+	}
+	unquoted, err := strconv.Unquote(strings.TrimPrefix(value, "W/"))
+	if err != nil {
+		return 0, false
+	}
+	version, err = strconv.Atoi(unquoted)
+	return version, err == nil && version > 0
+}
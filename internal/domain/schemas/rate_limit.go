@@ -0,0 +1,52 @@
+package schemas
+
+import (
+	"math"
+	"time"
+)
+
+// Группы маршрутов, для которых настраиваются отдельные лимиты
+const (
+	RateLimitGroupAuth  = "auth"  // вход и обновление токенов, ключ — IP
+	RateLimitGroupAdmin = "admin" // /admin/* и управление учётками
+	RateLimitGroupWrite = "write" // остальные изменяющие запросы
+	RateLimitGroupRead  = "read"  // GET-запросы
+)
+
+// RateLimit — параметры token bucket: Burst запросов подряд, затем Rate запросов в секунду
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// TokenBucket — состояние корзины одного клиента. Корзина с нулевым UpdatedAt считается полной.
+type TokenBucket struct {
+	Tokens    float64   `db:"tokens"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // через сколько появится следующий токен (если !Allowed)
+}
+
+// Take пополняет корзину за прошедшее время и пытается забрать один токен
+func (b *TokenBucket) Take(limit RateLimit, now time.Time) RateLimitResult {
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = float64(limit.Burst)
+		b.UpdatedAt = now
+	}
+	// Часы разных инстансов могут расходиться — время корзины назад не сдвигаем
+	if elapsed := now.Sub(b.UpdatedAt).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(float64(limit.Burst), b.Tokens+elapsed*limit.Rate)
+		b.UpdatedAt = now
+	}
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return RateLimitResult{Allowed: true, Remaining: int(b.Tokens)}
+	}
+	wait := time.Duration((1 - b.Tokens) / limit.Rate * float64(time.Second))
+	return RateLimitResult{Allowed: false, RetryAfter: wait}
+}
@@ -0,0 +1,35 @@
+package schemas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_Take(t *testing.T) {
+	limit := RateLimit{Rate: 1, Burst: 2}
+	now := time.Now()
+	var bucket TokenBucket
+
+	assert.True(t, bucket.Take(limit, now).Allowed)
+	assert.True(t, bucket.Take(limit, now).Allowed)
+
+	denied := bucket.Take(limit, now)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, time.Second, denied.RetryAfter)
+
+	// Через полсекунды токена ещё нет, через секунду — есть
+	assert.False(t, bucket.Take(limit, now.Add(500*time.Millisecond)).Allowed)
+	assert.True(t, bucket.Take(limit, now.Add(time.Second)).Allowed)
+}
+
+func TestTokenBucket_RefillCappedAtBurst(t *testing.T) {
+	limit := RateLimit{Rate: 10, Burst: 3}
+	now := time.Now()
+	bucket := TokenBucket{Tokens: 0, UpdatedAt: now}
+
+	result := bucket.Take(limit, now.Add(time.Hour))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
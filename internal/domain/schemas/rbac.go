@@ -0,0 +1,54 @@
+package schemas
+
+import "time"
+
+// Глобальные роли хранятся в accounts.role, командные (team_lead) — в role_bindings
+const (
+	RoleAdmin    = "admin"
+	RoleTeamLead = "team_lead"
+	RoleMember   = "member"
+	RoleReadOnly = "read_only"
+)
+
+// Действия, которые проверяет авторизация. Для API-ключей те же строки служат скоупами.
+const (
+	PermTeamCreate     = "team:create"
+	PermTeamRead       = "team:read"
+	PermTeamManage     = "team:manage"
+	PermUsersRead      = "users:read"
+	PermUsersSetActive = "users:write"
+	PermPRCreate       = "pr:create"
+	PermPRMerge        = "pr:merge"
+	PermPRReassign     = "pr:reassign"
+	PermStatsRead      = "stats:read"
+	PermAdminister     = "admin"
+)
+
+// RoleBinding выдаёт роль в рамках команды (например, team_lead команды backend)
+type RoleBinding struct {
+	Login     string     `json:"login" db:"login"`
+	Role      string     `json:"role" db:"role"`
+	TeamName  string     `json:"team_name" db:"team_name"`
+	CreatedAt *time.Time `json:"created_at,omitempty" db:"created_at"`
+}
+
+// Principal — кто выполняет запрос
+type Principal struct {
+	Login  string
+	UserID string
+	Role   string
+	APIKey *APIKey
+}
+
+// AuthzResource — объект действия; заполняются только известные поля
+type AuthzResource struct {
+	TeamName     string
+	TargetUserID string
+	PRID         string
+	AuthorID     string
+}
+
+// IsAccountRole — можно ли назначить роль учётке глобально; team_lead выдаётся только через RoleBinding
+func IsAccountRole(role string) bool {
+	return role == RoleAdmin || role == RoleMember || role == RoleReadOnly
+}
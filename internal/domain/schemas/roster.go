@@ -0,0 +1,40 @@
+package schemas
+
+// Roster — описание команд, их настроек и участников для импорта/экспорта и синхронизации
+type Roster struct {
+	Teams []Team `json:"teams"`
+}
+
+// UserMove — перевод пользователя из одной команды в другую
+type UserMove struct {
+	User     User   `json:"user"`
+	FromTeam string `json:"from_team"`
+}
+
+// RosterDiff — изменения, которые импорт внесёт в БД.
+// Каждый пользователь попадает только в один список (created > moved > deactivated > updated).
+type RosterDiff struct {
+	TeamsCreated     []string       `json:"teams_created"`
+	SettingsChanged  []TeamSettings `json:"settings_changed"`
+	UsersCreated     []User         `json:"users_created"`
+	UsersMoved       []UserMove     `json:"users_moved"`
+	UsersDeactivated []User         `json:"users_deactivated"`
+	UsersUpdated     []User         `json:"users_updated"`
+}
+
+func (d *RosterDiff) HasChanges() bool {
+	return len(d.TeamsCreated) > 0 || len(d.SettingsChanged) > 0 || len(d.UsersCreated) > 0 || len(d.UsersMoved) > 0 ||
+		len(d.UsersDeactivated) > 0 || len(d.UsersUpdated) > 0
+}
+
+// ChangedUsers возвращает целевое состояние всех затронутых пользователей
+func (d *RosterDiff) ChangedUsers() []User {
+	users := make([]User, 0, len(d.UsersCreated)+len(d.UsersMoved)+len(d.UsersDeactivated)+len(d.UsersUpdated))
+	users = append(users, d.UsersCreated...)
+	for _, m := range d.UsersMoved {
+		users = append(users, m.User)
+	}
+	users = append(users, d.UsersDeactivated...)
+	users = append(users, d.UsersUpdated...)
+	return users
+}
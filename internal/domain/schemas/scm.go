@@ -0,0 +1,84 @@
+package schemas
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Действия с PR, которые приходят из вебхуков провайдеров
+const (
+	SCMActionOpened   = "opened"
+	SCMActionClosed   = "closed" // закрыт без merge
+	SCMActionMerged   = "merged"
+	SCMActionReopened = "reopened"
+)
+
+// Результаты обработки вебхука
+const (
+	SCMEventProcessed = "processed"
+	SCMEventDuplicate = "duplicate"
+	SCMEventIgnored   = "ignored"
+)
+
+// SCMEvent — событие PR, приведённое адаптером провайдера к общему виду
+type SCMEvent struct {
+	Provider       string
+	DeliveryID     string // идентификатор доставки; повторная доставка приходит с тем же ID
+	Action         string
+	Repository     string // owner/repo или group/project
+	Number         int
+	Title          string
+	AuthorUsername string
+}
+
+// PRID — идентификатор PR в ReviewAssigner, например "github:acme/api#42"
+func (e *SCMEvent) PRID() string {
+	return fmt.Sprintf("%s:%s#%d", e.Provider, e.Repository, e.Number)
+}
+
+// ParseSCMPRID разбирает ID вида "github:acme/api#42"; ok=false для PR, заведённых вручную
+func ParseSCMPRID(id string) (provider, repo string, number int, ok bool) {
+	provider, rest, found := strings.Cut(id, ":")
+	i := strings.LastIndex(rest, "#")
+	if !found || provider == "" || i <= 0 {
+		return "", "", 0, false
+	}
+	number, err := strconv.Atoi(rest[i+1:])
+	if err != nil || number <= 0 {
+		return "", "", 0, false
+	}
+	return provider, rest[:i], number, true
+}
+
+type SCMEventResult struct {
+	Status string `json:"status"`
+	Action string `json:"action,omitempty"`
+	PRID   string `json:"pull_request_id,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// SCMIdentity связывает пользователя домена с его логином у провайдера
+type SCMIdentity struct {
+	Provider  string     `json:"provider" db:"provider"`
+	Username  string     `json:"username" db:"username"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	CreatedAt *time.Time `json:"created_at,omitempty" db:"created_at"`
+}
+
+// Статусы отправки ревьюверов провайдеру
+const (
+	SCMSyncPending = "pending"
+	SCMSyncSynced  = "synced"
+	SCMSyncFailed  = "failed"
+	SCMSyncSkipped = "skipped" // у ревьюверов нет привязанных логинов
+)
+
+// SCMSyncState — итог отправки ревьюверов провайдеру, сохраняется в PR
+type SCMSyncState struct {
+	Status   string
+	Error    string
+	Attempts int
+	SyncedAt *time.Time
+}
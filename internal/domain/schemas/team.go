@@ -1,6 +1,16 @@
 package schemas
 
+// DefaultReviewersCount — сколько ревьюверов назначается на PR, если для команды не задано иное
+const DefaultReviewersCount = 2
+
 type Team struct {
-    Name    string `json:"team_name" db:"team_name"`
-    Members []User `json:"members"`
+    Name           string `json:"team_name" db:"team_name"`
+    ReviewersCount int    `json:"reviewers_count" db:"reviewers_count"` // 0 — не задано
+    Members        []User `json:"members"`
+}
+
+// TeamSettings — настройки назначения ревьюверов для команды
+type TeamSettings struct {
+    TeamName       string `json:"team_name" db:"team_name"`
+    ReviewersCount int    `json:"reviewers_count" db:"reviewers_count"`
 }
@@ -0,0 +1,24 @@
+package schemas
+
+import "time"
+
+// RefreshToken хранится только в виде SHA-256 хеша. FamilyID объединяет цепочку ротаций:
+// повторное использование уже отозванного токена отзывает всю цепочку.
+type RefreshToken struct {
+	TokenHash string     `db:"token_hash"`
+	Login     string     `db:"login"`
+	FamilyID  string     `db:"family_id"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt *time.Time `db:"created_at"`
+}
+
+type TokenPair struct {
+	AccessToken      string `json:"token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
+	Role             string `json:"role"`
+	UserID           string `json:"user_id"`
+}
@@ -0,0 +1,49 @@
+package schemas
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Статусы доставки исходящего вебхука
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead" // попытки исчерпаны, повтор только вручную
+)
+
+// WebhookSubscription — адрес, на который отправляются события выбранных типов.
+// Секрет подписи показывается один раз при создании.
+type WebhookSubscription struct {
+	ID         string     `json:"id" db:"id"`
+	URL        string     `json:"url" db:"url"`
+	Secret     string     `json:"-" db:"secret"`
+	EventTypes []string   `json:"event_types" db:"-"`
+	CreatedBy  string     `json:"created_by" db:"created_by"`
+	CreatedAt  *time.Time `json:"created_at,omitempty" db:"created_at"`
+}
+
+func (s *WebhookSubscription) Subscribes(eventType string) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery — отправка одного события одной подписке
+type WebhookDelivery struct {
+	ID             string          `json:"id" db:"id"`
+	SubscriptionID string          `json:"subscription_id" db:"subscription_id"`
+	EventID        string          `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty" db:"last_error"`
+	ResponseStatus int             `json:"response_status,omitempty" db:"response_status"`
+	CreatedAt      *time.Time      `json:"created_at,omitempty" db:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
@@ -1,10 +1,157 @@
 package errors
-import "errors"
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Error — ошибка API: код для клиента, HTTP-статус, сообщение, детали и исходная причина.
+// Ошибки сравниваются по коду, поэтому errors.Is(err, ErrNotFound) верно и для копий
+// с другим сообщением или причиной, и для ошибок, обёрнутых через fmt.Errorf("%w").
+type Error struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	Fields  []FieldError           `json:"fields,omitempty"` // ошибки в конкретных полях запроса
+	Status  int                    `json:"-"`
+	Cause   error                  `json:"-"`
+}
+
+// FieldError — ошибка в поле запроса; Field — путь в терминах JSON (members[0].user_id)
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// New создаёт ошибку с кодом, HTTP-статусом и сообщением по умолчанию
+func New(code string, status int, message string) *Error {
+	return &Error{Code: code, Status: status, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Code + ": " + e.Cause.Error()
+	}
+	if e.Message != "" {
+		return e.Code + ": " + e.Message
+	}
+	return e.Code
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is сравнивает ошибки по коду
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap возвращает копию ошибки с причиной; причина не попадает в ответ клиенту
+func (e *Error) Wrap(cause error) *Error {
+	c := e.clone()
+	c.Cause = cause
+	return c
+}
+
+// WithMessage возвращает копию ошибки с другим сообщением
+func (e *Error) WithMessage(message string) *Error {
+	c := e.clone()
+	c.Message = message
+	return c
+}
+
+// WithDetail возвращает копию ошибки с дополнительным полем в details
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	c := e.clone()
+	details := make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		details[k] = v
+	}
+	details[key] = value
+	c.Details = details
+	return c
+}
+
+// WithFields возвращает копию ошибки с ошибками полей запроса
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	c := e.clone()
+	c.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return c
+}
+
+func (e *Error) clone() *Error {
+	c := *e
+	return &c
+}
+
+// From приводит любую ошибку к *Error: типизированная ошибка ищется по цепочке Unwrap,
+// истёкший дедлайн операции становится TIMEOUT, остальные — INTERNAL_ERROR с исходной ошибкой в Cause
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout.Wrap(err)
+	}
+	return ErrInternal.Wrap(err).WithMessage(err.Error())
+}
+
+// Validation — ошибка запроса с перечнем неверных полей
+func Validation(fields ...FieldError) *Error {
+	return ErrBadRequest.WithMessage("request validation failed").WithFields(fields...)
+}
+
 var (
-	ErrTeamExists    = errors.New("TEAM_EXISTS")
-	ErrPRExists      = errors.New("PR_EXISTS")
-	ErrPRMerged      = errors.New("PR_MERGED")
-	ErrNotAssigned   = errors.New("NOT_ASSIGNED")
-	ErrNoCandidate   = errors.New("NO_CANDIDATE")
-	ErrNotFound      = errors.New("NOT_FOUND")
+	ErrBadRequest = New("BAD_REQUEST", http.StatusBadRequest, "invalid request")
+	ErrInternal   = New("INTERNAL_ERROR", http.StatusInternalServerError, "internal error")
+	ErrTimeout    = New("TIMEOUT", http.StatusGatewayTimeout, "operation timed out, retry later")
+
+	ErrShuttingDown    = New("SHUTTING_DOWN", http.StatusServiceUnavailable, "server is shutting down")
+	ErrPayloadTooLarge = New("PAYLOAD_TOO_LARGE", http.StatusRequestEntityTooLarge, "request body is too large")
+
+	// ErrResponseValidation — ответ не соответствует спецификации OpenAPI (только при OPENAPI_VALIDATION=all)
+	ErrResponseValidation = New("RESPONSE_VALIDATION", http.StatusInternalServerError, "response does not match API specification")
+
+	ErrTeamExists    = New("TEAM_EXISTS", http.StatusBadRequest, "team_name already exists")
+	ErrPRExists      = New("PR_EXISTS", http.StatusConflict, "PR id already exists")
+	ErrPRMerged      = New("PR_MERGED", http.StatusConflict, "cannot reassign on merged PR")
+	ErrPRClosed      = New("PR_CLOSED", http.StatusConflict, "cannot reassign on closed PR")
+	ErrNotAssigned   = New("NOT_ASSIGNED", http.StatusConflict, "reviewer is not assigned to this PR")
+	ErrNoCandidate   = New("NO_CANDIDATE", http.StatusConflict, "no active replacement candidate in team")
+	ErrNotFound      = New("NOT_FOUND", http.StatusNotFound, "resource not found")
+	ErrInvalidRoster = New("INVALID_ROSTER", http.StatusBadRequest, "roster is invalid")
+
+	// ErrVersionConflict — PR изменили между чтением и записью; ErrPreconditionFailed — If-Match устарел
+	ErrVersionConflict    = New("VERSION_CONFLICT", http.StatusConflict, "pull request was modified concurrently, reload it and retry")
+	ErrPreconditionFailed = New("PRECONDITION_FAILED", http.StatusPreconditionFailed, "If-Match does not match the current version")
+
+	ErrUnauthorized       = New("UNAUTHORIZED", http.StatusUnauthorized, "authentication required")
+	ErrInvalidCredentials = New("INVALID_CREDENTIALS", http.StatusUnauthorized, "Invalid user_id or password")
+	ErrAccountLocked      = New("ACCOUNT_LOCKED", http.StatusLocked, "too many failed login attempts, try again later")
+	ErrAccountExists      = New("ACCOUNT_EXISTS", http.StatusConflict, "login already exists")
+	ErrWeakPassword       = New("WEAK_PASSWORD", http.StatusBadRequest, "password is too short")
+	ErrInvalidRole        = New("INVALID_ROLE", http.StatusBadRequest, "role is not allowed here")
+	ErrInvalidToken       = New("INVALID_TOKEN", http.StatusUnauthorized, "refresh token is invalid or expired")
+	ErrTokenRevoked       = New("TOKEN_REVOKED", http.StatusUnauthorized, "token has been revoked")
+	ErrInvalidAPIKey      = New("INVALID_API_KEY", http.StatusUnauthorized, "API key is invalid, expired or revoked")
+	ErrInvalidScope       = New("INVALID_SCOPE", http.StatusBadRequest, "unknown or empty scopes")
+	ErrForbidden          = New("FORBIDDEN", http.StatusForbidden, "insufficient permissions for this resource")
+	ErrRateLimited        = New("RATE_LIMITED", http.StatusTooManyRequests, "Too many requests, retry later")
+	ErrSSOFailed          = New("SSO_FAILED", http.StatusUnauthorized, "single sign-on failed or login session expired")
+	ErrUnknownUser        = New("UNKNOWN_USER", http.StatusForbidden, "user is not registered in any team")
+	ErrInvalidSignature   = New("INVALID_SIGNATURE", http.StatusUnauthorized, "webhook signature or token is invalid")
+	ErrInvalidPayload     = New("INVALID_PAYLOAD", http.StatusBadRequest, "webhook payload cannot be parsed")
+	ErrInvalidWebhook     = New("INVALID_WEBHOOK", http.StatusBadRequest, "url must be http(s) and event_types must be known")
+	ErrInvalidChannel     = New("INVALID_CHANNEL", http.StatusBadRequest, "unknown notification channel or empty address")
+	ErrInvalidDigest      = New("INVALID_DIGEST", http.StatusBadRequest, "send_at must be HH:MM, timezone a known IANA zone and channel configured for the user")
+
+	ErrIdempotencyKeyReused = New("IDEMPOTENCY_KEY_REUSED", http.StatusConflict, "Idempotency-Key was already used with a different request")
+	ErrRequestInProgress    = New("REQUEST_IN_PROGRESS", http.StatusConflict, "request with this Idempotency-Key is still in progress")
 )
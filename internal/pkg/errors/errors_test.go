@@ -0,0 +1,55 @@
+package errors_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"ReviewAssigner/internal/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_IsMatchesByCode(t *testing.T) {
+	cause := stderrors.New("no rows")
+	err := fmt.Errorf("get pr: %w", errors.ErrNotFound.WithMessage("pull request not found").Wrap(cause))
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, errors.ErrPRExists)
+
+	var apiErr *errors.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 404, apiErr.Status)
+	assert.Equal(t, "pull request not found", apiErr.Message)
+}
+
+func TestError_CopiesDoNotChangeSentinel(t *testing.T) {
+	err := errors.ErrBadRequest.WithMessage("bad").WithDetail("limit", 100).WithFields(errors.FieldError{Field: "name", Message: "is required"})
+
+	assert.Equal(t, "invalid request", errors.ErrBadRequest.Message)
+	assert.Nil(t, errors.ErrBadRequest.Details)
+	assert.Nil(t, errors.ErrBadRequest.Fields)
+	assert.Equal(t, map[string]interface{}{"limit": 100}, err.Details)
+	assert.Len(t, err.Fields, 1)
+}
+
+func TestFrom(t *testing.T) {
+	assert.Nil(t, errors.From(nil))
+	assert.Same(t, errors.ErrForbidden, errors.From(errors.ErrForbidden))
+
+	internal := errors.From(stderrors.New("connection refused"))
+	assert.Equal(t, "INTERNAL_ERROR", internal.Code)
+	assert.Equal(t, 500, internal.Status)
+	assert.Equal(t, "connection refused", internal.Message)
+
+	timeout := errors.From(fmt.Errorf("select users: %w", context.DeadlineExceeded))
+	assert.Equal(t, "TIMEOUT", timeout.Code)
+	assert.Equal(t, 504, timeout.Status)
+	assert.ErrorIs(t, timeout, context.DeadlineExceeded)
+
+	validation := errors.Validation(errors.FieldError{Field: "members[0].user_id", Message: "is required"})
+	assert.Equal(t, "BAD_REQUEST", validation.Code)
+	assert.Equal(t, 400, validation.Status)
+}
@@ -1,37 +1,122 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
 	"time"
 
 	jwt "github.com/golang-jwt/jwt/v4"
 )
 
-var secretKey = []byte("your-secret-key")
-
 type Claims struct {
 	UserID string `json:"user_id"`
-	Role   string `json:"role"` // "admin" или "user"
+	Login  string `json:"login"`
+	Role   string `json:"role"` // глобальная роль: "admin", "member" или "read_only"
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID, role string) (string, error) {
+// Config — настройки подписи токенов.
+// Для HS256 задаётся Secret, для RS256/ES256 — PrivateKeyPEM (алгоритм определяется типом ключа).
+// VerifyKeysPEM — дополнительные публичные ключи (kid -> PEM), которыми подписаны ещё живые токены.
+type Config struct {
+	Algorithm     string
+	KeyID         string
+	Secret        []byte
+	PrivateKeyPEM []byte
+	VerifyKeysPEM map[string][]byte
+	Issuer        string
+	TTL           time.Duration
+}
+
+// Manager выпускает и проверяет токены. Подписывает всегда текущим ключом,
+// проверяет любым из активных ключей по заголовку kid.
+type Manager struct {
+	signing *key
+	verify  map[string]*key
+	issuer  string
+	ttl     time.Duration
+}
+
+func NewManager(cfg Config) (*Manager, error) {
+	var signing *key
+	var err error
+	switch cfg.Algorithm {
+	case AlgHS256, "":
+		signing, err = newHMACKey(cfg.KeyID, cfg.Secret)
+	case AlgRS256, AlgES256:
+		signing, err = parsePrivateKey(cfg.KeyID, cfg.PrivateKeyPEM)
+		if err == nil && signing.method.Alg() != cfg.Algorithm {
+			err = fmt.Errorf("jwt: private key is for %s, but algorithm %s is configured", signing.method.Alg(), cfg.Algorithm)
+		}
+	default:
+		err = fmt.Errorf("jwt: unsupported algorithm %q", cfg.Algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{
+		signing: signing,
+		verify:  map[string]*key{signing.kid: signing},
+		issuer:  cfg.Issuer,
+		ttl:     cfg.TTL,
+	}
+	if m.issuer == "" {
+		m.issuer = "review-assigner"
+	}
+	if m.ttl == 0 {
+		m.ttl = 24 * time.Hour
+	}
+
+	for kid, pemData := range cfg.VerifyKeysPEM {
+		k, err := parsePublicKey(kid, pemData)
+		if err != nil {
+			return nil, fmt.Errorf("verification key %q: %w", kid, err)
+		}
+		if _, exists := m.verify[k.kid]; exists {
+			return nil, fmt.Errorf("jwt: duplicate kid %q", k.kid)
+		}
+		m.verify[k.kid] = k
+	}
+	return m, nil
+}
+
+func (m *Manager) GenerateToken(userID, login, role string) (string, error) {
+	now := time.Now()
 	claims := Claims{
 		UserID: userID,
+		Login:  login,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "review-assigner",
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    m.issuer,
+			ID:        newTokenID(),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secretKey)
+	token := jwt.NewWithClaims(m.signing.method, claims)
+	token.Header["kid"] = m.signing.kid
+	return token.SignedString(m.signing.signKey)
 }
 
-func ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return secretKey, nil
+func (m *Manager) ValidateToken(tokenString string) (*Claims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{AlgHS256, AlgRS256, AlgES256}))
+	token, err := parser.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		k := m.signing
+		// Токены без kid выпущены до ротации — проверяем текущим ключом
+		if kid, ok := token.Header["kid"].(string); ok {
+			if k, ok = m.verify[kid]; !ok {
+				return nil, fmt.Errorf("jwt: unknown kid %q", kid)
+			}
+		}
+		// Защита от подмены алгоритма (например, RS256-ключ как HMAC-секрет)
+		if token.Method.Alg() != k.method.Alg() {
+			return nil, fmt.Errorf("jwt: unexpected signing method %s", token.Method.Alg())
+		}
+		return k.verifyKey, nil
 	})
 
 	if err != nil {
@@ -39,8 +124,41 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		if claims.Issuer != m.issuer {
+			return nil, fmt.Errorf("jwt: unexpected issuer %q", claims.Issuer)
+		}
 		return claims, nil
 	}
 
 	return nil, jwt.ErrInvalidKey
 }
+
+// TTL — время жизни access-токена
+func (m *Manager) TTL() time.Duration {
+	return m.ttl
+}
+
+func newTokenID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// JWKS возвращает публичные ключи проверки (для HS256 — пустой набор)
+func (m *Manager) JWKS() JWKSet {
+	kids := make([]string, 0, len(m.verify))
+	for kid := range m.verify {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, kid := range kids {
+		if jwk, ok := m.verify[kid].jwk(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ecKeyPEM(t *testing.T) (private, public []byte) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pubDer, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer})
+}
+
+func TestManager_HS256(t *testing.T) {
+	m, err := NewManager(Config{Secret: []byte("0123456789abcdef0123456789abcdef")})
+	require.NoError(t, err)
+
+	token, err := m.GenerateToken("u1", "alice", "user")
+	require.NoError(t, err)
+	claims, err := m.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", claims.UserID)
+	assert.Empty(t, m.JWKS().Keys, "HMAC secret must not be published")
+}
+
+func TestManager_ES256_Rotation(t *testing.T) {
+	oldPriv, oldPub := ecKeyPEM(t)
+	newPriv, _ := ecKeyPEM(t)
+
+	oldManager, err := NewManager(Config{Algorithm: AlgES256, KeyID: "k1", PrivateKeyPEM: oldPriv})
+	require.NoError(t, err)
+	oldToken, err := oldManager.GenerateToken("u1", "alice", "user")
+	require.NoError(t, err)
+
+	// Новый ключ подписи, старый — только для проверки
+	m, err := NewManager(Config{
+		Algorithm:     AlgES256,
+		KeyID:         "k2",
+		PrivateKeyPEM: newPriv,
+		VerifyKeysPEM: map[string][]byte{"k1": oldPub},
+	})
+	require.NoError(t, err)
+
+	_, err = m.ValidateToken(oldToken)
+	assert.NoError(t, err)
+
+	newToken, err := m.GenerateToken("u1", "alice", "user")
+	require.NoError(t, err)
+	_, err = oldManager.ValidateToken(newToken)
+	assert.Error(t, err, "old instance does not know kid k2")
+
+	jwks := m.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "k1", jwks.Keys[0].Kid)
+	assert.Equal(t, "P-256", jwks.Keys[1].Crv)
+}
+
+func TestManager_RejectsAlgorithmMismatch(t *testing.T) {
+	priv, _ := ecKeyPEM(t)
+	_, err := NewManager(Config{Algorithm: AlgRS256, PrivateKeyPEM: priv})
+	assert.Error(t, err)
+}
@@ -0,0 +1,214 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+)
+
+// key — ключ подписи/проверки. Для HS256 signKey и verifyKey совпадают (секрет),
+// для RS256/ES256 signKey — приватный ключ (nil, если ключ только для проверки).
+type key struct {
+	kid       string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// JWK — публичный ключ в формате RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func newHMACKey(kid string, secret []byte) (*key, error) {
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("jwt: HS256 secret must be at least 32 bytes")
+	}
+	if kid == "" {
+		sum := sha256.Sum256(append([]byte("kid:"), secret...))
+		kid = "hs-" + hex.EncodeToString(sum[:8])
+	}
+	return &key{kid: kid, method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}, nil
+}
+
+// parsePrivateKey разбирает PEM с приватным RSA (PKCS#1/PKCS#8) или EC P-256 (SEC1/PKCS#8) ключом
+func parsePrivateKey(kid string, pemData []byte) (*key, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("jwt: no PEM block found in private key")
+	}
+
+	var priv interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		priv, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt: parse private key: %w", err)
+	}
+
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("jwt: unsupported private key type %T", priv)
+	}
+	k, err := publicKey(kid, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	k.signKey = priv
+	return k, nil
+}
+
+// parsePublicKey разбирает PEM с публичным ключом (PKIX или PKCS#1 RSA) — старые ключи при ротации
+func parsePublicKey(kid string, pemData []byte) (*key, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("jwt: no PEM block found in public key")
+	}
+
+	var pub interface{}
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			pub = cert.PublicKey
+		}
+	default:
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt: parse public key: %w", err)
+	}
+	return publicKey(kid, pub)
+}
+
+func publicKey(kid string, pub interface{}) (*key, error) {
+	var method jwt.SigningMethod
+	switch p := pub.(type) {
+	case *rsa.PublicKey:
+		if p.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("jwt: RSA key must be at least 2048 bits")
+		}
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		if p.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("jwt: only P-256 curve is supported for ES256")
+		}
+		method = jwt.SigningMethodES256
+	default:
+		return nil, fmt.Errorf("jwt: unsupported public key type %T", pub)
+	}
+
+	if kid == "" {
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(der)
+		kid = hex.EncodeToString(sum[:8])
+	}
+	return &key{kid: kid, method: method, verifyKey: pub}, nil
+}
+
+// jwk возвращает публичную часть ключа; для HS256 — false (секрет не публикуется)
+func (k *key) jwk() (JWK, bool) {
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch pub := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA", Use: "sig", Alg: k.method.Alg(), Kid: k.kid,
+			N: b64(pub.N.Bytes()),
+			E: b64(big.NewInt(int64(pub.E)).Bytes()),
+		}, true
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			Kty: "EC", Use: "sig", Alg: k.method.Alg(), Kid: k.kid, Crv: pub.Curve.Params().Name,
+			X: b64(pub.X.FillBytes(make([]byte, size))),
+			Y: b64(pub.Y.FillBytes(make([]byte, size))),
+		}, true
+	}
+	return JWK{}, false
+}
+
+// PublicKey восстанавливает публичный ключ из JWK (RSA или EC P-256) — для проверки токенов внешних провайдеров
+func (j JWK) PublicKey() (interface{}, error) {
+	b64 := base64.RawURLEncoding.DecodeString
+	switch j.Kty {
+	case "RSA":
+		n, err := b64(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: invalid JWK modulus: %w", err)
+		}
+		e, err := b64(j.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: invalid JWK exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if j.Crv != elliptic.P256().Params().Name {
+			return nil, fmt.Errorf("jwt: unsupported JWK curve %q", j.Crv)
+		}
+		x, err := b64(j.X)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: invalid JWK x: %w", err)
+		}
+		y, err := b64(j.Y)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: invalid JWK y: %w", err)
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, fmt.Errorf("jwt: JWK point is not on curve")
+		}
+		return pub, nil
+	}
+	return nil, fmt.Errorf("jwt: unsupported JWK key type %q", j.Kty)
+}
+
+// NewJWK возвращает публичную часть RSA- или EC-ключа в формате JWK
+func NewJWK(kid string, pub interface{}) (JWK, error) {
+	k, err := publicKey(kid, pub)
+	if err != nil {
+		return JWK{}, err
+	}
+	jwk, _ := k.jwk()
+	return jwk, nil
+}
@@ -0,0 +1,104 @@
+// Package notify содержит каналы уведомлений: почту через SMTP, входящие вебхуки Slack/Mattermost
+// и Telegram Bot API. Адрес получателя зависит от канала: e-mail, "@username" или chat_id.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Каналы уведомлений
+const (
+	ChannelEmail    = "email"
+	ChannelSlack    = "slack" // Slack или Mattermost: формат входящего вебхука у них совпадает
+	ChannelTelegram = "telegram"
+)
+
+var KnownChannels = []string{ChannelEmail, ChannelSlack, ChannelTelegram}
+
+// Message — отрендеренное уведомление
+type Message struct {
+	Subject string
+	Text    string
+}
+
+// Channel доставляет сообщение одному получателю
+type Channel interface {
+	Send(address string, msg Message) error
+}
+
+// PermanentError — получатель отклонил сообщение (неверный адрес, chat_id и т.п.): повтор не поможет
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// IsPermanent — ошибку Send не исправит повторная отправка того же сообщения
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// postJSON отправляет JSON и считает ошибкой любой ответ кроме 2xx
+func postJSON(url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("notify: %s responded %d: %s", url, resp.StatusCode, strings.TrimSpace(string(body)))
+		// 4xx — запрос отклонён по существу; 408 и 429 просят повторить позже
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+			resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return &PermanentError{Err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+// Slack отправляет во входящий вебхук; address ("@user" или "#channel") переопределяет канал вебхука
+type Slack struct {
+	WebhookURL string
+}
+
+func (s *Slack) Send(address string, msg Message) error {
+	payload := map[string]string{"text": "*" + msg.Subject + "*\n" + msg.Text}
+	if address != "" {
+		payload["channel"] = address
+	}
+	return postJSON(s.WebhookURL, payload)
+}
+
+// Telegram отправляет через Bot API; address — chat_id
+type Telegram struct {
+	APIURL string // по умолчанию https://api.telegram.org
+	Token  string
+}
+
+func (t *Telegram) Send(address string, msg Message) error {
+	base := t.APIURL
+	if base == "" {
+		base = "https://api.telegram.org"
+	}
+	return postJSON(strings.TrimRight(base, "/")+"/bot"+t.Token+"/sendMessage", map[string]string{
+		"chat_id": address,
+		"text":    msg.Subject + "\n\n" + msg.Text,
+	})
+}
@@ -0,0 +1,149 @@
+package notify_test
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/notify"
+	"ReviewAssigner/internal/pkg/notify/notifytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var msg = notify.Message{Subject: "Review requested: Add search", Text: "Hi Alice,\nplease review."}
+
+func TestSMTP_Send(t *testing.T) {
+	srv, err := notifytest.NewSMTPServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+
+	channel := &notify.SMTP{Addr: srv.Addr, From: "review@example.com"}
+	require.NoError(t, channel.Send("alice@example.com", msg))
+
+	mails := srv.Mails()
+	require.Len(t, mails, 1)
+	assert.Equal(t, "review@example.com", mails[0].From)
+	assert.Equal(t, []string{"alice@example.com"}, mails[0].To)
+	assert.Contains(t, mails[0].Data, "Subject: Review requested: Add search\r\n")
+	assert.Contains(t, mails[0].Data, "Hi Alice,\r\nplease review.")
+}
+
+func TestSMTP_SubjectCannotInjectHeaders(t *testing.T) {
+	srv, err := notifytest.NewSMTPServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+
+	channel := &notify.SMTP{Addr: srv.Addr, From: "review@example.com"}
+	require.NoError(t, channel.Send("alice@example.com", notify.Message{Subject: "x\r\nBcc: evil@example.com", Text: "body"}))
+	assert.NotContains(t, srv.Mails()[0].Data, "\r\nBcc:")
+}
+
+func TestSMTP_TimesOutOnSilentServer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		// Принимает соединение и молчит: приветствие сервера не придёт
+		conn, err := l.Accept()
+		if err == nil {
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	channel := &notify.SMTP{Addr: l.Addr().String(), From: "review@example.com", Timeout: 100 * time.Millisecond}
+	start := time.Now()
+	err = channel.Send("alice@example.com", msg)
+	require.Error(t, err)
+	assert.False(t, notify.IsPermanent(err))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestSMTP_InvalidAddressIsPermanent(t *testing.T) {
+	channel := &notify.SMTP{Addr: "127.0.0.1:1", From: "review@example.com"}
+	assert.True(t, notify.IsPermanent(channel.Send("not an address", msg)))
+}
+
+func TestSlack_Send(t *testing.T) {
+	rec, srv := notifytest.NewRecorder()
+	t.Cleanup(srv.Close)
+
+	require.NoError(t, (&notify.Slack{WebhookURL: srv.URL + "/hooks/abc"}).Send("@alice", msg))
+
+	reqs := rec.Requests()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "/hooks/abc", reqs[0].Path)
+	assert.Equal(t, "@alice", reqs[0].Body["channel"])
+	assert.Equal(t, "*Review requested: Add search*\nHi Alice,\nplease review.", reqs[0].Body["text"])
+}
+
+func TestTelegram_Send(t *testing.T) {
+	rec, srv := notifytest.NewRecorder()
+	t.Cleanup(srv.Close)
+	channel := &notify.Telegram{APIURL: srv.URL, Token: "123:abc"}
+
+	require.NoError(t, channel.Send("42", msg))
+	reqs := rec.Requests()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "/bot123:abc/sendMessage", reqs[0].Path)
+	assert.Equal(t, "42", reqs[0].Body["chat_id"])
+
+	rec.FailWith(http.StatusBadRequest)
+	assert.True(t, notify.IsPermanent(channel.Send("42", msg)), "неверный chat_id не исправится повтором")
+	rec.FailWith(http.StatusTooManyRequests)
+	assert.False(t, notify.IsPermanent(channel.Send("42", msg)))
+	rec.FailWith(http.StatusBadGateway)
+	assert.False(t, notify.IsPermanent(channel.Send("42", msg)))
+}
+
+func TestTemplates_DefaultAndOverride(t *testing.T) {
+	data := schemas.NotificationData{
+		User:             &schemas.User{ID: "u2", Username: "Alice"},
+		PR:               &schemas.PullRequest{ID: "pr1", Name: "Add search", AuthorID: "u1"},
+		ReplacedReviewer: "u3",
+	}
+
+	templates, err := notify.LoadTemplates("")
+	require.NoError(t, err)
+	m, err := templates.Render(notify.TemplateAssigned, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Review requested: Add search", m.Subject)
+	assert.Equal(t, `Hi Alice, you have been assigned to review "Add search" (pr1) by u1. You replace u3.`, m.Text)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "assigned.tmpl"),
+		[]byte(`{{define "subject"}}Ревью: {{.PR.Name}}{{end}}{{define "body"}}{{.User.Username}}, посмотри {{.PR.ID}}{{end}}`), 0o600))
+	templates, err = notify.LoadTemplates(dir)
+	require.NoError(t, err)
+	m, err = templates.Render(notify.TemplateAssigned, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Ревью: Add search", m.Subject)
+	assert.Equal(t, "Alice, посмотри pr1", m.Text)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unassigned.tmpl"), []byte(`{{define "body"}}no subject{{end}}`), 0o600))
+	_, err = notify.LoadTemplates(dir)
+	assert.Error(t, err)
+}
+
+func TestTemplates_Digest(t *testing.T) {
+	templates, err := notify.LoadTemplates("")
+	require.NoError(t, err)
+
+	m, err := templates.Render(notify.TemplateDigest, schemas.NotificationData{
+		User: &schemas.User{ID: "u2", Username: "Alice"},
+		Reviews: []schemas.PendingReview{
+			{PullRequestShort: schemas.PullRequestShort{ID: "pr3", Name: "Fix login", AuthorID: "u1"}, Waiting: "2d 2h"},
+			{PullRequestShort: schemas.PullRequestShort{ID: "pr1", Name: "Add search", AuthorID: "u4"}, Waiting: "3h 0m"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2 pull request(s) waiting for your review", m.Subject)
+	assert.Equal(t, "Hi Alice, these pull requests are waiting for your review:\n"+
+		"- \"Fix login\" (pr3) by u1, waiting 2d 2h\n"+
+		"- \"Add search\" (pr1) by u4, waiting 3h 0m", m.Text)
+}
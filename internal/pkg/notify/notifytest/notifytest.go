@@ -0,0 +1,161 @@
+// Package notifytest — локальные заглушки SMTP-сервера и HTTP API (Slack, Mattermost, Telegram) для тестов
+package notifytest
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Mail — письмо, принятое SMTP-заглушкой
+type Mail struct {
+	From string
+	To   []string
+	Data string // заголовки и тело как пришли
+}
+
+// SMTPServer принимает письма без аутентификации и TLS
+type SMTPServer struct {
+	Addr string
+
+	listener net.Listener
+	mu       sync.Mutex
+	mails    []Mail
+}
+
+func NewSMTPServer() (*SMTPServer, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &SMTPServer{Addr: l.Addr().String(), listener: l}
+	go s.serve()
+	return s, nil
+}
+
+func (s *SMTPServer) Close() error {
+	return s.listener.Close()
+}
+
+func (s *SMTPServer) Mails() []Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mail(nil), s.mails...)
+}
+
+func (s *SMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *SMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 notifytest ESMTP")
+	var mail Mail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 notifytest")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			mail = Mail{From: address(line)}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			mail.To = append(mail.To, address(line))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			mail.Data = data.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "RSET", cmd == "NOOP":
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func address(line string) string {
+	_, v, _ := strings.Cut(line, ":")
+	return strings.Trim(strings.TrimSpace(v), "<>")
+}
+
+// Request — JSON-запрос, принятый HTTP-заглушкой
+type Request struct {
+	Path string
+	Body map[string]interface{}
+}
+
+// Recorder записывает JSON-запросы: подходит как входящий вебхук Slack/Mattermost и как Telegram Bot API
+type Recorder struct {
+	mu       sync.Mutex
+	requests []Request
+	status   int
+}
+
+// NewRecorder запускает заглушку; закрыть сервер должен вызывающий
+func NewRecorder() (*Recorder, *httptest.Server) {
+	rec := &Recorder{status: http.StatusOK}
+	return rec, httptest.NewServer(rec)
+}
+
+// FailWith задаёт код ответа на все следующие запросы
+func (r *Recorder) FailWith(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *Recorder) Requests() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Request(nil), r.requests...)
+}
+
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var body map[string]interface{}
+	json.NewDecoder(req.Body).Decode(&body)
+
+	r.mu.Lock()
+	r.requests = append(r.requests, Request{Path: req.URL.Path, Body: body})
+	status := r.status
+	r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	io.WriteString(w, `{"ok":true}`)
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// defaultSMTPTimeout — ограничение по умолчанию на соединение и отправку одного письма
+const defaultSMTPTimeout = 10 * time.Second
+
+// SMTP отправляет письмо; без Username отправка идёт без аутентификации
+type SMTP struct {
+	Addr     string // host:port
+	From     string
+	Username string
+	Password string
+	Timeout  time.Duration // на подключение и весь диалог с сервером; 0 — defaultSMTPTimeout
+}
+
+func (s *SMTP) Send(address string, msg Message) error {
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+	if _, err := mail.ParseAddress(address); err != nil || strings.ContainsAny(address, "\r\n") {
+		return &PermanentError{Err: fmt.Errorf("notify: invalid email address %q", address)}
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+
+	// Переводы строк в заголовках недопустимы: иначе через тему можно подставить свои заголовки
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(msg.Subject)
+	body := strings.ReplaceAll(strings.ReplaceAll(msg.Text, "\r\n", "\n"), "\n", "\r\n")
+	data := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.From, address, subject, time.Now().Format(time.RFC1123Z), body)
+	return classifySMTP(s.sendMail(host, auth, address, []byte(data)))
+}
+
+// sendMail — smtp.SendMail с ограничением по времени: зависший сервер не держит отправителя
+func (s *SMTP) sendMail(host string, auth smtp.Auth, to string, data []byte) error {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultSMTPTimeout
+	}
+	conn, err := net.DialTimeout("tcp", s.Addr, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// classifySMTP помечает ответы 5xx постоянными: сервер отказал окончательно, 4xx просит повторить позже
+func classifySMTP(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return &PermanentError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Шаблон уведомления определяет блоки "subject" и "body"
+const (
+	TemplateAssigned   = "assigned"
+	TemplateUnassigned = "unassigned"
+	TemplateDigest     = "digest"
+)
+
+var defaultTemplates = map[string]string{
+	TemplateAssigned: `{{define "subject"}}Review requested: {{.PR.Name}}{{end}}
+{{define "body"}}Hi {{.User.Username}}, you have been assigned to review "{{.PR.Name}}" ({{.PR.ID}}) by {{.PR.AuthorID}}.
+{{- if .ReplacedReviewer}} You replace {{.ReplacedReviewer}}.{{end}}{{end}}`,
+	TemplateUnassigned: `{{define "subject"}}Review reassigned: {{.PR.Name}}{{end}}
+{{define "body"}}Hi {{.User.Username}}, your review of "{{.PR.Name}}" ({{.PR.ID}}) has been handed over to {{.NewReviewer}}.{{end}}`,
+	TemplateDigest: `{{define "subject"}}{{len .Reviews}} pull request(s) waiting for your review{{end}}
+{{define "body"}}Hi {{.User.Username}}, these pull requests are waiting for your review:
+{{- range .Reviews}}
+- "{{.Name}}" ({{.ID}}) by {{.AuthorID}}, waiting {{.Waiting}}
+{{- end}}{{end}}`,
+}
+
+// Templates — шаблоны уведомлений по имени
+type Templates struct {
+	set map[string]*template.Template
+}
+
+// LoadTemplates берёт встроенные шаблоны и заменяет их файлами <имя>.tmpl из dir (если dir задан)
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{set: map[string]*template.Template{}}
+	for name, text := range defaultTemplates {
+		if dir != "" {
+			data, err := os.ReadFile(filepath.Join(dir, name+".tmpl"))
+			if err == nil {
+				text = string(data)
+			} else if !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("notify: template %s: %w", name, err)
+		}
+		if tmpl.Lookup("subject") == nil || tmpl.Lookup("body") == nil {
+			return nil, fmt.Errorf("notify: template %s must define subject and body", name)
+		}
+		t.set[name] = tmpl
+	}
+	return t, nil
+}
+
+func (t *Templates) Render(name string, data interface{}) (Message, error) {
+	tmpl, ok := t.set[name]
+	if !ok {
+		return Message{}, fmt.Errorf("notify: unknown template %s", name)
+	}
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return Message{}, err
+	}
+	return Message{Subject: strings.TrimSpace(subject.String()), Text: strings.TrimSpace(body.String())}, nil
+}
@@ -0,0 +1,51 @@
+package oidc
+
+import "strings"
+
+// Claims — claims токена провайдера. Путь к claim может быть вложенным через точку,
+// например "realm_access.roles".
+type Claims map[string]interface{}
+
+func (c Claims) lookup(path string) (interface{}, bool) {
+	var cur interface{} = map[string]interface{}(c)
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// String возвращает строковый claim или "", если его нет
+func (c Claims) String(path string) string {
+	v, _ := c.lookup(path)
+	s, _ := v.(string)
+	return s
+}
+
+// Strings возвращает claim-список; одиночная строка трактуется как список из одного элемента
+func (c Claims) Strings(path string) []string {
+	v, ok := c.lookup(path)
+	if !ok {
+		return nil
+	}
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case []string:
+		return t
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/pkg/jwt"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+)
+
+// keysRefreshInterval — как часто можно перечитывать JWKS при встрече незнакомого kid
+const keysRefreshInterval = time.Minute
+
+// Config — настройки OIDC-провайдера (authorization code + PKCE)
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string // может быть пустым для public-клиента
+	RedirectURL  string
+	Scopes       []string
+	Audience     string // ожидаемый aud access-токенов; по умолчанию ClientID
+	HTTPClient   *http.Client
+}
+
+// TokenResponse — ответ token endpoint
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider работает с провайдером по discovery-документу. Метаданные и ключи загружаются лениво,
+// чтобы сервис стартовал даже при недоступном провайдере.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu            sync.Mutex
+	meta          *metadata
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+}
+
+func NewProvider(cfg Config) *Provider {
+	if cfg.Audience == "" {
+		cfg.Audience = cfg.ClientID
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile"}
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+// AuthCodeURL — адрес, на который отправляется браузер пользователя
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) (string, error) {
+	meta, err := p.metadata()
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {Challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange обменивает код авторизации на токены, подтверждая его PKCE-верификатором
+func (p *Provider) Exchange(code, verifier string) (*TokenResponse, error) {
+	meta, err := p.metadata()
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequest(http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: token request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: token endpoint returned %s", resp.Status)
+	}
+
+	var tokens TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("oidc: decode token response: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("oidc: token response has no id_token")
+	}
+	return &tokens, nil
+}
+
+// VerifyIDToken проверяет подпись, issuer, aud = ClientID, срок действия и nonce ID-токена
+func (p *Provider) VerifyIDToken(raw, nonce string) (Claims, error) {
+	claims, err := p.verify(raw, p.cfg.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if claims.String("nonce") != nonce {
+		return nil, fmt.Errorf("oidc: nonce mismatch")
+	}
+	return claims, nil
+}
+
+// VerifyAccessToken проверяет bearer-токен, выпущенный провайдером для нашего API
+func (p *Provider) VerifyAccessToken(raw string) (Claims, error) {
+	return p.verify(raw, p.cfg.Audience)
+}
+
+func (p *Provider) verify(raw, audience string) (Claims, error) {
+	meta, err := p.metadata()
+	if err != nil {
+		return nil, err
+	}
+
+	parser := gojwt.NewParser(gojwt.WithValidMethods([]string{jwt.AlgRS256, jwt.AlgES256}))
+	claims := gojwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(raw, claims, func(token *gojwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(kid)
+	}); err != nil {
+		return nil, fmt.Errorf("oidc: %w", err)
+	}
+
+	if !claims.VerifyIssuer(meta.Issuer, true) {
+		return nil, fmt.Errorf("oidc: unexpected issuer")
+	}
+	if !claims.VerifyAudience(audience, true) {
+		return nil, fmt.Errorf("oidc: unexpected audience")
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, fmt.Errorf("oidc: token has no expiry or is expired")
+	}
+	return Claims(claims), nil
+}
+
+// key ищет ключ по kid; незнакомый kid означает ротацию у провайдера — перечитываем JWKS
+func (p *Provider) key(kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if k, ok := p.lookupKey(kid); ok {
+		return k, nil
+	}
+	if time.Since(p.keysFetchedAt) < keysRefreshInterval {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	if err := p.fetchKeys(); err != nil {
+		return nil, err
+	}
+	if k, ok := p.lookupKey(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown kid %q", kid)
+}
+
+// lookupKey — токен без kid допустим, только если у провайдера один ключ
+func (p *Provider) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+	k, ok := p.keys[kid]
+	return k, ok
+}
+
+func (p *Provider) fetchKeys() error {
+	if p.meta == nil {
+		return fmt.Errorf("oidc: provider metadata is not loaded")
+	}
+	var set jwt.JWKSet
+	if err := p.getJSON(p.meta.JWKSURI, &set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			continue // ключи неподдерживаемых типов пропускаем
+		}
+		keys[jwk.Kid] = pub
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	return nil
+}
+
+func (p *Provider) metadata() (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil {
+		return p.meta, nil
+	}
+	var meta metadata
+	if err := p.getJSON(strings.TrimSuffix(p.cfg.IssuerURL, "/")+"/.well-known/openid-configuration", &meta); err != nil {
+		return nil, err
+	}
+	if meta.Issuer != strings.TrimSuffix(p.cfg.IssuerURL, "/") && meta.Issuer != p.cfg.IssuerURL {
+		return nil, fmt.Errorf("oidc: discovery issuer %q does not match %q", meta.Issuer, p.cfg.IssuerURL)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc: discovery document is incomplete")
+	}
+	p.meta = &meta
+	return p.meta, nil
+}
+
+func (p *Provider) getJSON(url string, out interface{}) error {
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return fmt.Errorf("oidc: GET %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: GET %s returned %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("oidc: decode %s: %w", url, err)
+	}
+	return nil
+}
+
+// RandomString — случайная строка для state, nonce и PKCE-верификатора
+func RandomString() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Challenge — PKCE code_challenge для метода S256
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,97 @@
+package oidc_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"ReviewAssigner/internal/pkg/oidc"
+	"ReviewAssigner/internal/pkg/oidc/oidctest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "http://app.local/auth/oidc/callback"
+
+func newProvider(t *testing.T) (*oidc.Provider, *oidctest.IdP) {
+	idp, srv := oidctest.NewServer("review-assigner")
+	t.Cleanup(srv.Close)
+	return oidc.NewProvider(oidc.Config{IssuerURL: idp.Issuer, ClientID: "review-assigner", RedirectURL: redirectURL}), idp
+}
+
+// authorize проходит редирект на IdP и возвращает code и state из callback
+func authorize(t *testing.T, authURL string) (code, state string) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	return loc.Query().Get("code"), loc.Query().Get("state")
+}
+
+func TestProvider_AuthCodeFlowWithPKCE(t *testing.T) {
+	provider, idp := newProvider(t)
+	idp.SetClaims(map[string]interface{}{"sub": "s1", "preferred_username": "u1", "groups": []string{"admins"}})
+
+	verifier := oidc.RandomString()
+	authURL, err := provider.AuthCodeURL("st", "n1", verifier)
+	require.NoError(t, err)
+
+	code, state := authorize(t, authURL)
+	assert.Equal(t, "st", state)
+
+	tokens, err := provider.Exchange(code, verifier)
+	require.NoError(t, err)
+
+	claims, err := provider.VerifyIDToken(tokens.IDToken, "n1")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", claims.String("preferred_username"))
+	assert.Equal(t, []string{"admins"}, claims.Strings("groups"))
+
+	_, err = provider.VerifyAccessToken(tokens.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestProvider_Exchange_WrongVerifier(t *testing.T) {
+	provider, _ := newProvider(t)
+
+	authURL, err := provider.AuthCodeURL("st", "n1", oidc.RandomString())
+	require.NoError(t, err)
+	code, _ := authorize(t, authURL)
+
+	_, err = provider.Exchange(code, oidc.RandomString())
+	assert.Error(t, err)
+}
+
+func TestProvider_VerifyIDToken_NonceMismatch(t *testing.T) {
+	provider, idp := newProvider(t)
+
+	_, err := provider.VerifyIDToken(idp.Token(map[string]interface{}{"nonce": "other"}), "n1")
+	assert.Error(t, err)
+}
+
+func TestProvider_VerifyAccessToken_Rejects(t *testing.T) {
+	provider, idp := newProvider(t)
+	other, otherSrv := oidctest.NewServer("review-assigner")
+	defer otherSrv.Close()
+
+	cases := map[string]string{
+		"wrong audience": idp.Token(map[string]interface{}{"aud": "someone-else"}),
+		"expired":        idp.Token(map[string]interface{}{"exp": 1}),
+		"foreign key":    other.Token(map[string]interface{}{"iss": idp.Issuer}),
+	}
+	for name, token := range cases {
+		_, err := provider.VerifyAccessToken(token)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestClaims_NestedPath(t *testing.T) {
+	claims := oidc.Claims{"realm_access": map[string]interface{}{"roles": []interface{}{"lead", "dev"}}}
+	assert.Equal(t, []string{"lead", "dev"}, claims.Strings("realm_access.roles"))
+	assert.Equal(t, "", claims.String("realm_access.missing"))
+}
@@ -0,0 +1,182 @@
+// Package oidctest — минимальный OIDC-провайдер для тестов и локальной разработки.
+// Авторизация подтверждается автоматически: пользователь получает заранее заданные claims.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/pkg/jwt"
+	"ReviewAssigner/internal/pkg/oidc"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+)
+
+const keyID = "oidctest"
+
+type grant struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	claims        map[string]interface{}
+}
+
+// IdP — http.Handler с discovery, JWKS, authorize и token endpoint'ами
+type IdP struct {
+	Issuer   string
+	ClientID string
+	TTL      time.Duration
+
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	claims map[string]interface{}
+	codes  map[string]grant
+}
+
+func NewIdP(issuer, clientID string) (*IdP, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return &IdP{
+		Issuer:   issuer,
+		ClientID: clientID,
+		TTL:      time.Hour,
+		key:      key,
+		claims:   map[string]interface{}{"sub": "user-1"},
+		codes:    make(map[string]grant),
+	}, nil
+}
+
+// NewServer запускает IdP на случайном локальном порту; Close сервера останавливает его
+func NewServer(clientID string) (*IdP, *httptest.Server) {
+	srv := httptest.NewUnstartedServer(nil)
+	idp, err := NewIdP("http://"+srv.Listener.Addr().String(), clientID)
+	if err != nil {
+		panic(err)
+	}
+	srv.Config.Handler = idp
+	srv.Start()
+	return idp, srv
+}
+
+// SetClaims задаёт claims пользователя, который «войдёт» при следующей авторизации
+func (p *IdP) SetClaims(claims map[string]interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.claims = claims
+}
+
+// Token выпускает подписанный токен с переданными claims; iss, aud и exp дополняются, если не заданы
+func (p *IdP) Token(claims map[string]interface{}) string {
+	mc := gojwt.MapClaims{"iss": p.Issuer, "aud": p.ClientID, "iat": time.Now().Unix(), "exp": time.Now().Add(p.TTL).Unix()}
+	for k, v := range claims {
+		mc[k] = v
+	}
+	token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, mc)
+	token.Header["kid"] = keyID
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func (p *IdP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 p.Issuer,
+			"authorization_endpoint": p.Issuer + "/authorize",
+			"token_endpoint":         p.Issuer + "/token",
+			"jwks_uri":               p.Issuer + "/jwks",
+		})
+	case "/jwks":
+		jwk, err := jwt.NewJWK(keyID, &p.key.PublicKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, jwt.JWKSet{Keys: []jwt.JWK{jwk}})
+	case "/authorize":
+		p.authorize(w, r)
+	case "/token":
+		p.token(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (p *IdP) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || q.Get("redirect_uri") == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if q.Get("client_id") != p.ClientID || q.Get("response_type") != "code" ||
+		q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	code := oidc.RandomString()
+	p.mu.Lock()
+	p.codes[code] = grant{
+		clientID:      p.ClientID,
+		redirectURI:   q.Get("redirect_uri"),
+		nonce:         q.Get("nonce"),
+		codeChallenge: q.Get("code_challenge"),
+		claims:        p.claims,
+	}
+	p.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *IdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	g, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	if !ok || g.redirectURI != r.PostForm.Get("redirect_uri") || oidc.Challenge(r.PostForm.Get("code_verifier")) != g.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idClaims := map[string]interface{}{"nonce": g.nonce}
+	for k, v := range g.claims {
+		idClaims[k] = v
+	}
+	writeJSON(w, http.StatusOK, oidc.TokenResponse{
+		AccessToken: p.Token(g.claims),
+		IDToken:     p.Token(idClaims),
+		TokenType:   "Bearer",
+		ExpiresIn:   int(p.TTL.Seconds()),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
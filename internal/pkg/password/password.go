@@ -0,0 +1,30 @@
+package password
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+const MinLength = 8
+
+// MaxLength — предел bcrypt: более длинный пароль не хешируется
+const MaxLength = 72
+
+// dummyHash используется, чтобы проверка несуществующего логина занимала столько же времени
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+func Hash(plain string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func Verify(hash, plain string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain)) == nil
+}
+
+// VerifyDummy выполняет сравнение с фиктивным хешем (защита от timing-атак при неизвестном логине)
+func VerifyDummy(plain string) {
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(plain))
+}
@@ -0,0 +1,215 @@
+// Package roster читает и пишет описание команд в форматах CSV и YAML.
+// Настройки назначения (reviewers_count) переносятся только в YAML.
+package roster
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"ReviewAssigner/internal/domain/schemas"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatYAML = "yaml"
+)
+
+var csvHeader = []string{"team_name", "user_id", "username", "is_active"}
+
+type yamlRoster struct {
+	Teams []yamlTeam `yaml:"teams"`
+}
+
+type yamlTeam struct {
+	Name           string       `yaml:"team_name"`
+	ReviewersCount int          `yaml:"reviewers_count,omitempty"` // 0 — не задано
+	Members        []yamlMember `yaml:"members"`
+}
+
+type yamlMember struct {
+	ID       string `yaml:"user_id"`
+	Username string `yaml:"username"`
+	IsActive *bool  `yaml:"is_active,omitempty"` // по умолчанию true
+}
+
+// NormalizeFormat приводит название формата (или Content-Type) к FormatCSV/FormatYAML
+func NormalizeFormat(format string) (string, error) {
+	f := strings.ToLower(strings.TrimSpace(format))
+	if i := strings.Index(f, ";"); i >= 0 {
+		f = strings.TrimSpace(f[:i])
+	}
+	switch f {
+	case "csv", "text/csv":
+		return FormatCSV, nil
+	case "yaml", "yml", "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unsupported format %q (expected csv or yaml)", format)
+}
+
+func Decode(format string, r io.Reader) (*schemas.Roster, error) {
+	switch format {
+	case FormatCSV:
+		return decodeCSV(r)
+	case FormatYAML:
+		return decodeYAML(r)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+func Encode(format string, w io.Writer, roster *schemas.Roster) error {
+	switch format {
+	case FormatCSV:
+		return encodeCSV(w, roster)
+	case FormatYAML:
+		return encodeYAML(w, roster)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+// decodeCSV ожидает заголовок team_name,user_id,username,is_active.
+// Строка с пустым user_id объявляет команду без участников.
+func decodeCSV(r io.Reader) (*schemas.Roster, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &schemas.Roster{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvHeader[:3] {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv: missing column %q", name)
+		}
+	}
+	get := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	roster := &schemas.Roster{}
+	index := map[string]int{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		teamName := get(record, "team_name")
+		i, ok := index[teamName]
+		if !ok {
+			i = len(roster.Teams)
+			index[teamName] = i
+			roster.Teams = append(roster.Teams, schemas.Team{Name: teamName, Members: []schemas.User{}})
+		}
+
+		userID := get(record, "user_id")
+		if userID == "" {
+			continue
+		}
+		isActive := true
+		if v := get(record, "is_active"); v != "" {
+			isActive, err = strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("csv line %d: invalid is_active %q", line, v)
+			}
+		}
+		roster.Teams[i].Members = append(roster.Teams[i].Members, schemas.User{
+			ID:       userID,
+			Username: get(record, "username"),
+			TeamName: teamName,
+			IsActive: isActive,
+		})
+	}
+	return roster, nil
+}
+
+func encodeCSV(w io.Writer, roster *schemas.Roster) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, team := range roster.Teams {
+		if len(team.Members) == 0 {
+			if err := writer.Write([]string{team.Name, "", "", ""}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, m := range team.Members {
+			if err := writer.Write([]string{team.Name, m.ID, m.Username, strconv.FormatBool(m.IsActive)}); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func decodeYAML(r io.Reader) (*schemas.Roster, error) {
+	var doc yamlRoster
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("yaml: %w", err)
+	}
+
+	roster := &schemas.Roster{Teams: make([]schemas.Team, 0, len(doc.Teams))}
+	for _, t := range doc.Teams {
+		team := schemas.Team{
+			Name:           strings.TrimSpace(t.Name),
+			ReviewersCount: t.ReviewersCount,
+			Members:        make([]schemas.User, 0, len(t.Members)),
+		}
+		for _, m := range t.Members {
+			isActive := true
+			if m.IsActive != nil {
+				isActive = *m.IsActive
+			}
+			team.Members = append(team.Members, schemas.User{
+				ID:       strings.TrimSpace(m.ID),
+				Username: strings.TrimSpace(m.Username),
+				TeamName: team.Name,
+				IsActive: isActive,
+			})
+		}
+		roster.Teams = append(roster.Teams, team)
+	}
+	return roster, nil
+}
+
+func encodeYAML(w io.Writer, roster *schemas.Roster) error {
+	doc := yamlRoster{Teams: make([]yamlTeam, 0, len(roster.Teams))}
+	for _, t := range roster.Teams {
+		team := yamlTeam{Name: t.Name, ReviewersCount: t.ReviewersCount, Members: make([]yamlMember, 0, len(t.Members))}
+		for _, m := range t.Members {
+			isActive := m.IsActive
+			team.Members = append(team.Members, yamlMember{ID: m.ID, Username: m.Username, IsActive: &isActive})
+		}
+		doc.Teams = append(doc.Teams, team)
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,154 @@
+package scm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ReviewerClient запрашивает ревью у провайдера от имени токена сервиса
+type ReviewerClient interface {
+	// SetReviewers запрашивает ревью у reviewers и снимает запрос с removed (логины провайдера)
+	SetReviewers(repo string, number int, reviewers, removed []string) error
+}
+
+// ErrUnknownUser — логин не найден у провайдера
+var ErrUnknownUser = errors.New("scm: user not found")
+
+// APIError — неуспешный ответ API провайдера
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("scm: api returned %d: %s", e.StatusCode, e.Body)
+}
+
+// Temporary — ошибка, которую имеет смысл повторить (лимит запросов или сбой провайдера)
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Адреса API по умолчанию; у Gitea общего адреса нет
+var defaultAPIURLs = map[string]string{
+	"github": "https://api.github.com",
+	"gitlab": "https://gitlab.com/api/v4",
+}
+
+// NewReviewerClient создаёт клиент провайдера; пустой baseURL — публичный API провайдера
+func NewReviewerClient(provider, baseURL, token string) (ReviewerClient, error) {
+	if baseURL == "" {
+		baseURL = defaultAPIURLs[provider]
+	}
+	if baseURL == "" {
+		return nil, fmt.Errorf("scm: api url for %s is required", provider)
+	}
+	base := apiClient{baseURL: strings.TrimRight(baseURL, "/"), http: &http.Client{Timeout: 10 * time.Second}}
+
+	switch provider {
+	case "github":
+		base.header = http.Header{"Authorization": {"Bearer " + token}, "Accept": {"application/vnd.github+json"}}
+		return &githubClient{base}, nil
+	case "gitea":
+		// API Gitea для запроса ревью повторяет GitHub
+		base.header = http.Header{"Authorization": {"token " + token}}
+		return &githubClient{base}, nil
+	case "gitlab":
+		base.header = http.Header{"PRIVATE-TOKEN": {token}}
+		return &gitlabClient{apiClient: base}, nil
+	}
+	return nil, fmt.Errorf("scm: unknown provider %s", provider)
+}
+
+type apiClient struct {
+	baseURL string
+	header  http.Header
+	http    *http.Client
+}
+
+// do выполняет JSON-запрос; out может быть nil
+func (c *apiClient) do(method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// githubClient — requested_reviewers у GitHub и Gitea
+type githubClient struct {
+	apiClient
+}
+
+func (c *githubClient) SetReviewers(repo string, number int, reviewers, removed []string) error {
+	path := fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", repo, number)
+	if len(removed) > 0 {
+		if err := c.do(http.MethodDelete, path, map[string][]string{"reviewers": removed}, nil); err != nil {
+			return err
+		}
+	}
+	if len(reviewers) == 0 {
+		return nil
+	}
+	// Повторный запрос у уже назначенного ревьювера провайдер принимает без ошибки
+	return c.do(http.MethodPost, path, map[string][]string{"reviewers": reviewers}, nil)
+}
+
+// gitlabClient задаёт полный список reviewer_ids MR; логины переводятся в числовые ID
+type gitlabClient struct {
+	apiClient
+}
+
+func (c *gitlabClient) SetReviewers(repo string, number int, reviewers, removed []string) error {
+	ids := make([]int, 0, len(reviewers))
+	for _, username := range reviewers {
+		var users []struct {
+			ID int `json:"id"`
+		}
+		if err := c.do(http.MethodGet, "/users?username="+url.QueryEscape(username), nil, &users); err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return fmt.Errorf("%w: %s", ErrUnknownUser, username)
+		}
+		ids = append(ids, users[0].ID)
+	}
+
+	path := fmt.Sprintf("/projects/%s/merge_requests/%d", url.PathEscape(repo), number)
+	return c.do(http.MethodPut, path, map[string][]int{"reviewer_ids": ids}, nil)
+}
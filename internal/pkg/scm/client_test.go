@@ -0,0 +1,61 @@
+package scm_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"ReviewAssigner/internal/pkg/scm"
+	"ReviewAssigner/internal/pkg/scm/scmtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubClient_RequestAndRemoveReviewers(t *testing.T) {
+	api, srv := scmtest.NewServer("t0ken")
+	t.Cleanup(srv.Close)
+	client, err := scm.NewReviewerClient("github", srv.URL, "t0ken")
+	require.NoError(t, err)
+
+	require.NoError(t, client.SetReviewers("acme/api", 42, []string{"alice", "bob"}, nil))
+	assert.Equal(t, []string{"alice", "bob"}, api.Reviewers("acme/api", 42))
+
+	require.NoError(t, client.SetReviewers("acme/api", 42, []string{"alice", "carol"}, []string{"bob"}))
+	assert.Equal(t, []string{"alice", "carol"}, api.Reviewers("acme/api", 42))
+}
+
+func TestGitLabClient_ReplacesReviewersByID(t *testing.T) {
+	api, srv := scmtest.NewServer("t0ken")
+	t.Cleanup(srv.Close)
+	api.AddGitLabUser("alice", 1)
+	api.AddGitLabUser("bob", 2)
+	client, err := scm.NewReviewerClient("gitlab", srv.URL, "t0ken")
+	require.NoError(t, err)
+
+	require.NoError(t, client.SetReviewers("group/project", 7, []string{"alice", "bob"}, nil))
+	require.NoError(t, client.SetReviewers("group/project", 7, []string{"bob"}, []string{"alice"}))
+	assert.Equal(t, []string{"bob"}, api.Reviewers("group/project", 7))
+
+	err = client.SetReviewers("group/project", 7, []string{"ghost"}, nil)
+	assert.True(t, errors.Is(err, scm.ErrUnknownUser))
+}
+
+func TestClient_APIErrors(t *testing.T) {
+	api, srv := scmtest.NewServer("t0ken")
+	t.Cleanup(srv.Close)
+
+	bad, _ := scm.NewReviewerClient("gitea", srv.URL, "wrong")
+	var apiErr *scm.APIError
+	require.True(t, errors.As(bad.SetReviewers("acme/api", 1, []string{"alice"}, nil), &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.False(t, apiErr.Temporary())
+
+	client, _ := scm.NewReviewerClient("gitea", srv.URL, "t0ken")
+	api.FailNext(http.StatusBadGateway)
+	require.True(t, errors.As(client.SetReviewers("acme/api", 1, []string{"alice"}, nil), &apiErr))
+	assert.True(t, apiErr.Temporary())
+
+	_, err := scm.NewReviewerClient("gitea", "", "t0ken")
+	assert.Error(t, err)
+}
@@ -0,0 +1,23 @@
+package scm
+
+import (
+	"net/http"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+// Gitea: payload как у GitHub, подпись — hex HMAC-SHA256 без префикса в X-Gitea-Signature
+type Gitea struct{}
+
+func (Gitea) Name() string { return "gitea" }
+
+func (Gitea) Verify(header http.Header, body []byte, secret string) error {
+	return verifyHMAC(header.Get("X-Gitea-Signature"), body, secret)
+}
+
+func (g Gitea) Parse(header http.Header, body []byte) (*schemas.SCMEvent, error) {
+	if header.Get("X-Gitea-Event") != "pull_request" {
+		return nil, nil
+	}
+	return parseGitHubStyle(g.Name(), deliveryID(header, "X-Gitea-Delivery", body), body)
+}
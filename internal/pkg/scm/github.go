@@ -0,0 +1,80 @@
+package scm
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+// githubPullRequestEvent — общая часть payload pull_request у GitHub и Gitea
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// GitHub: подпись в X-Hub-Signature-256 ("sha256=<hex>"), событие в X-GitHub-Event
+type GitHub struct{}
+
+func (GitHub) Name() string { return "github" }
+
+func (GitHub) Verify(header http.Header, body []byte, secret string) error {
+	signature, ok := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return verifyHMAC(signature, body, secret)
+}
+
+func (g GitHub) Parse(header http.Header, body []byte) (*schemas.SCMEvent, error) {
+	if header.Get("X-GitHub-Event") != "pull_request" {
+		return nil, nil
+	}
+	return parseGitHubStyle(g.Name(), deliveryID(header, "X-GitHub-Delivery", body), body)
+}
+
+func parseGitHubStyle(provider, delivery string, body []byte) (*schemas.SCMEvent, error) {
+	var payload githubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, ErrInvalidPayload
+	}
+
+	var action string
+	switch payload.Action {
+	case "opened":
+		action = schemas.SCMActionOpened
+	case "reopened":
+		action = schemas.SCMActionReopened
+	case "closed":
+		action = schemas.SCMActionClosed
+		if payload.PullRequest.Merged {
+			action = schemas.SCMActionMerged
+		}
+	default:
+		return nil, nil
+	}
+	if payload.Repository.FullName == "" || payload.PullRequest.Number == 0 {
+		return nil, ErrInvalidPayload
+	}
+
+	return &schemas.SCMEvent{
+		Provider:       provider,
+		DeliveryID:     delivery,
+		Action:         action,
+		Repository:     payload.Repository.FullName,
+		Number:         payload.PullRequest.Number,
+		Title:          payload.PullRequest.Title,
+		AuthorUsername: payload.PullRequest.User.Login,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package scm
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type gitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    int    `json:"iid"`
+		Title  string `json:"title"`
+		Action string `json:"action"`
+	} `json:"object_attributes"`
+}
+
+// GitLab не подписывает тело: секрет приходит как есть в X-Gitlab-Token
+type GitLab struct{}
+
+func (GitLab) Name() string { return "gitlab" }
+
+func (GitLab) Verify(header http.Header, body []byte, secret string) error {
+	token := header.Get("X-Gitlab-Token")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (g GitLab) Parse(header http.Header, body []byte) (*schemas.SCMEvent, error) {
+	if header.Get("X-Gitlab-Event") != "Merge Request Hook" {
+		return nil, nil
+	}
+	var payload gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil || payload.ObjectKind != "merge_request" {
+		return nil, ErrInvalidPayload
+	}
+
+	var action string
+	switch payload.ObjectAttributes.Action {
+	case "open":
+		action = schemas.SCMActionOpened
+	case "reopen":
+		action = schemas.SCMActionReopened
+	case "close":
+		action = schemas.SCMActionClosed
+	case "merge":
+		action = schemas.SCMActionMerged
+	default:
+		return nil, nil
+	}
+	if payload.Project.PathWithNamespace == "" || payload.ObjectAttributes.IID == 0 {
+		return nil, ErrInvalidPayload
+	}
+
+	// В событии open инициатор — автор MR
+	return &schemas.SCMEvent{
+		Provider:       g.Name(),
+		DeliveryID:     deliveryID(header, "X-Gitlab-Event-UUID", body),
+		Action:         action,
+		Repository:     payload.Project.PathWithNamespace,
+		Number:         payload.ObjectAttributes.IID,
+		Title:          payload.ObjectAttributes.Title,
+		AuthorUsername: payload.User.Username,
+	}, nil
+}
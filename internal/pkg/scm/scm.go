@@ -0,0 +1,82 @@
+// Package scm содержит адаптеры вебхуков Git-хостингов. Адаптер проверяет подпись доставки
+// и приводит событие PR провайдера к schemas.SCMEvent.
+package scm
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
+
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+var (
+	ErrInvalidSignature = errors.New("scm: invalid webhook signature")
+	ErrInvalidPayload   = errors.New("scm: invalid webhook payload")
+)
+
+// Adapter разбирает вебхуки одного провайдера
+type Adapter interface {
+	Name() string
+	Verify(header http.Header, body []byte, secret string) error
+	// Parse возвращает nil без ошибки для событий, которые не нужно обрабатывать
+	Parse(header http.Header, body []byte) (*schemas.SCMEvent, error)
+}
+
+// Registry — набор адаптеров по имени провайдера
+type Registry struct {
+	adapters map[string]Adapter
+}
+
+func NewRegistry(adapters ...Adapter) *Registry {
+	r := &Registry{adapters: make(map[string]Adapter, len(adapters))}
+	for _, a := range adapters {
+		r.adapters[a.Name()] = a
+	}
+	return r
+}
+
+// DefaultRegistry — GitHub, GitLab и Gitea
+func DefaultRegistry() *Registry {
+	return NewRegistry(GitHub{}, GitLab{}, Gitea{})
+}
+
+func (r *Registry) Get(name string) (Adapter, bool) {
+	a, ok := r.adapters[name]
+	return a, ok
+}
+
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.adapters))
+	for name := range r.adapters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// verifyHMAC сравнивает hex-подпись HMAC-SHA256 тела за постоянное время
+func verifyHMAC(signature string, body []byte, secret string) error {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || secret == "" {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(expected, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// deliveryID — ID доставки из заголовка; если провайдер его не прислал, повтор узнаётся по телу
+func deliveryID(header http.Header, name string, body []byte) string {
+	if id := header.Get(name); id != "" {
+		return id
+	}
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
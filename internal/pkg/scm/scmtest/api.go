@@ -0,0 +1,188 @@
+// Package scmtest — заглушка API GitHub, Gitea и GitLab для запроса ревью в тестах и локальной разработке
+package scmtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+var (
+	githubPath = regexp.MustCompile(`^/repos/(.+)/pulls/(\d+)/requested_reviewers$`)
+	gitlabPath = regexp.MustCompile(`^/projects/([^/]+)/merge_requests/(\d+)$`)
+)
+
+// API хранит запрошенных ревьюверов по "repo#number" и умеет отвечать ошибками
+type API struct {
+	Token string
+
+	mu        sync.Mutex
+	reviewers map[string]map[string]bool
+	users     map[string]int // логин GitLab -> ID
+	failures  []int          // статусы, которыми ответить на ближайшие запросы
+	calls     int
+}
+
+func NewAPI(token string) *API {
+	return &API{Token: token, reviewers: map[string]map[string]bool{}, users: map[string]int{}}
+}
+
+// NewServer запускает API на httptest.Server; закрыть сервер должен вызывающий
+func NewServer(token string) (*API, *httptest.Server) {
+	api := NewAPI(token)
+	return api, httptest.NewServer(api)
+}
+
+// AddGitLabUser регистрирует логин GitLab, чтобы клиент смог найти его ID
+func (a *API) AddGitLabUser(username string, id int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.users[username] = id
+}
+
+// FailNext отвечает статусами statuses на ближайшие запросы по одному на запрос
+func (a *API) FailNext(statuses ...int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.failures = append(a.failures, statuses...)
+}
+
+// Reviewers — отсортированные запрошенные ревьюверы PR
+func (a *API) Reviewers(repo string, number int) []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	names := []string{}
+	for name := range a.reviewers[key(repo, number)] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Calls — число запросов, включая неудачные
+func (a *API) Calls() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.calls
+}
+
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls++
+
+	if !a.authorized(r) {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+		return
+	}
+	if len(a.failures) > 0 {
+		status := a.failures[0]
+		a.failures = a.failures[1:]
+		http.Error(w, `{"message":"injected failure"}`, status)
+		return
+	}
+
+	path := r.URL.EscapedPath()
+	switch {
+	case githubPath.MatchString(path):
+		m := githubPath.FindStringSubmatch(path)
+		a.githubReviewers(w, r, m[1], m[2])
+	case r.Method == http.MethodGet && path == "/users":
+		a.gitlabUsers(w, r.URL.Query().Get("username"))
+	case r.Method == http.MethodPut && gitlabPath.MatchString(path):
+		m := gitlabPath.FindStringSubmatch(path)
+		repo, _ := url.PathUnescape(m[1])
+		a.gitlabReviewers(w, r, repo, m[2])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (a *API) authorized(r *http.Request) bool {
+	want := []string{"Bearer " + a.Token, "token " + a.Token}
+	for _, v := range want {
+		if r.Header.Get("Authorization") == v {
+			return true
+		}
+	}
+	return r.Header.Get("PRIVATE-TOKEN") == a.Token
+}
+
+func (a *API) githubReviewers(w http.ResponseWriter, r *http.Request, repo, number string) {
+	var req struct {
+		Reviewers []string `json:"reviewers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"message":"Problems parsing JSON"}`, http.StatusBadRequest)
+		return
+	}
+	n, _ := strconv.Atoi(number)
+	set := a.set(repo, n)
+	switch r.Method {
+	case http.MethodPost:
+		for _, name := range req.Reviewers {
+			set[name] = true
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		for _, name := range req.Reviewers {
+			delete(set, name)
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	fmt.Fprint(w, "{}")
+}
+
+func (a *API) gitlabUsers(w http.ResponseWriter, username string) {
+	users := []map[string]interface{}{}
+	if id, ok := a.users[username]; ok {
+		users = append(users, map[string]interface{}{"id": id, "username": username})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+func (a *API) gitlabReviewers(w http.ResponseWriter, r *http.Request, repo, number string) {
+	var req struct {
+		ReviewerIDs []int `json:"reviewer_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"message":"400 Bad request"}`, http.StatusBadRequest)
+		return
+	}
+	n, _ := strconv.Atoi(number)
+	set := a.set(repo, n)
+	for name := range set {
+		delete(set, name)
+	}
+	for _, id := range req.ReviewerIDs {
+		for name, uid := range a.users {
+			if uid == id {
+				set[name] = true
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, "{}")
+}
+
+func (a *API) set(repo string, number int) map[string]bool {
+	k := key(repo, number)
+	if a.reviewers[k] == nil {
+		a.reviewers[k] = map[string]bool{}
+	}
+	return a.reviewers[k]
+}
+
+func key(repo string, number int) string {
+	return repo + "#" + strconv.Itoa(number)
+}
@@ -0,0 +1,126 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type accountRepository struct {
+	mu       sync.RWMutex
+	accounts map[string]*schemas.Account
+}
+
+func NewAccountRepository() interfaces.AccountRepository {
+	return &accountRepository{accounts: make(map[string]*schemas.Account)}
+}
+
+func (r *accountRepository) Create(ctx context.Context, account *schemas.Account) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.accounts[account.Login]; exists {
+		return errors.New("account already exists")
+	}
+	now := time.Now()
+	stored := *account
+	stored.PasswordChangedAt = &now
+	stored.CreatedAt = &now
+	r.accounts[account.Login] = &stored
+	return nil
+}
+
+func (r *accountRepository) GetByLogin(ctx context.Context, login string) (*schemas.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	account, exists := r.accounts[login]
+	if !exists {
+		return nil, nil
+	}
+	copied := *account
+	return &copied, nil
+}
+
+func (r *accountRepository) GetByUserID(ctx context.Context, userID string) (*schemas.Account, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, account := range r.accounts {
+		if userID != "" && account.UserID == userID {
+			copied := *account
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *accountRepository) UpdatePassword(ctx context.Context, login string, passwordHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, exists := r.accounts[login]
+	if !exists {
+		return errors.New("account not found")
+	}
+	now := time.Now()
+	account.PasswordHash = passwordHash
+	account.PasswordChangedAt = &now
+	account.FailedAttempts = 0
+	account.LockedUntil = nil
+	return nil
+}
+
+func (r *accountRepository) IncrementFailedAttempts(ctx context.Context, login string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, exists := r.accounts[login]
+	if !exists {
+		return 0, errors.New("account not found")
+	}
+	account.FailedAttempts++
+	return account.FailedAttempts, nil
+}
+
+func (r *accountRepository) Lock(ctx context.Context, login string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, exists := r.accounts[login]
+	if !exists {
+		return errors.New("account not found")
+	}
+	account.LockedUntil = &until
+	account.FailedAttempts = 0
+	return nil
+}
+
+func (r *accountRepository) ResetFailedAttempts(ctx context.Context, login string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, exists := r.accounts[login]
+	if !exists {
+		return errors.New("account not found")
+	}
+	account.FailedAttempts = 0
+	account.LockedUntil = nil
+	return nil
+}
+
+func (r *accountRepository) UpdateRole(ctx context.Context, login string, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, exists := r.accounts[login]
+	if !exists {
+		return errors.New("account not found")
+	}
+	account.Role = role
+	return nil
+}
@@ -0,0 +1,83 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type apiKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[string]*schemas.APIKey // id -> key
+}
+
+func NewAPIKeyRepository() interfaces.APIKeyRepository {
+	return &apiKeyRepository{keys: make(map[string]*schemas.APIKey)}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *schemas.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key.ID]; exists {
+		return errors.New("api key already exists")
+	}
+	stored := *key
+	now := time.Now()
+	stored.CreatedAt = &now
+	r.keys[key.ID] = &stored
+	return nil
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*schemas.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *apiKeyRepository) List(ctx context.Context) ([]schemas.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]schemas.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, *key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(*keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, exists := r.keys[id]
+	if !exists || key.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	return true, nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key, exists := r.keys[id]; exists {
+		key.LastUsedAt = &at
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type idempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]*schemas.IdempotencyRecord
+}
+
+func NewIdempotencyRepository() interfaces.IdempotencyRepository {
+	return &idempotencyRepository{records: make(map[string]*schemas.IdempotencyRecord)}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record *schemas.IdempotencyRecord) (*schemas.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, rec := range r.records {
+		if rec.ExpiresAt.Before(now) {
+			delete(r.records, key)
+		}
+	}
+	if existing, exists := r.records[record.Key]; exists {
+		stored := *existing
+		return &stored, nil
+	}
+	stored := *record
+	r.records[record.Key] = &stored
+	return nil, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, record *schemas.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *record
+	r.records[record.Key] = &stored
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rec, exists := r.records[key]; exists && !rec.Completed() {
+		delete(r.records, key)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type notificationPreferenceRepository struct {
+	mu    sync.RWMutex
+	prefs map[string]map[string]schemas.NotificationPreference // userID -> канал -> настройка
+}
+
+func NewNotificationPreferenceRepository() interfaces.NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{prefs: make(map[string]map[string]schemas.NotificationPreference)}
+}
+
+func (r *notificationPreferenceRepository) Upsert(ctx context.Context, pref *schemas.NotificationPreference) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.prefs[pref.UserID] == nil {
+		r.prefs[pref.UserID] = make(map[string]schemas.NotificationPreference)
+	}
+	stored := *pref
+	now := time.Now()
+	stored.UpdatedAt = &now
+	r.prefs[pref.UserID][pref.Channel] = stored
+	return nil
+}
+
+func (r *notificationPreferenceRepository) ListByUser(ctx context.Context, userID string) ([]schemas.NotificationPreference, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prefs := []schemas.NotificationPreference{}
+	for _, pref := range r.prefs[userID] {
+		prefs = append(prefs, pref)
+	}
+	sort.Slice(prefs, func(i, j int) bool { return prefs[i].Channel < prefs[j].Channel })
+	return prefs, nil
+}
+
+func (r *notificationPreferenceRepository) Delete(ctx context.Context, userID, channel string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.prefs[userID][channel]; !exists {
+		return false, nil
+	}
+	delete(r.prefs[userID], channel)
+	return true, nil
+}
+
+type digestSettingRepository struct {
+	mu          sync.Mutex
+	settings    map[string]schemas.DigestSetting // userID -> расписание сводки
+	defaultSent map[string]string                // userID -> дата сводки по расписанию по умолчанию
+}
+
+func NewDigestSettingRepository() interfaces.DigestSettingRepository {
+	return &digestSettingRepository{settings: make(map[string]schemas.DigestSetting), defaultSent: make(map[string]string)}
+}
+
+func (r *digestSettingRepository) Upsert(ctx context.Context, setting *schemas.DigestSetting) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *setting
+	stored.LastSentOn = r.settings[setting.UserID].LastSentOn
+	r.settings[setting.UserID] = stored
+	return nil
+}
+
+func (r *digestSettingRepository) GetByUser(ctx context.Context, userID string) (*schemas.DigestSetting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	setting, exists := r.settings[userID]
+	if !exists {
+		return nil, nil
+	}
+	return &setting, nil
+}
+
+func (r *digestSettingRepository) Delete(ctx context.Context, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.settings[userID]; !exists {
+		return false, nil
+	}
+	delete(r.settings, userID)
+	return true, nil
+}
+
+func (r *digestSettingRepository) ListEnabled(ctx context.Context) ([]schemas.DigestSetting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings := []schemas.DigestSetting{}
+	for _, setting := range r.settings {
+		if setting.Enabled {
+			settings = append(settings, setting)
+		}
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].UserID < settings[j].UserID })
+	return settings, nil
+}
+
+func (r *digestSettingRepository) MarkSent(ctx context.Context, userID, day string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	setting, exists := r.settings[userID]
+	if !exists || setting.LastSentOn >= day {
+		return false, nil
+	}
+	setting.LastSentOn = day
+	r.settings[userID] = setting
+	return true, nil
+}
+
+func (r *digestSettingRepository) ListConfiguredUserIDs(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := []string{}
+	for id := range r.settings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (r *digestSettingRepository) ListDefaultSent(ctx context.Context) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sent := make(map[string]string, len(r.defaultSent))
+	for id, day := range r.defaultSent {
+		sent[id] = day
+	}
+	return sent, nil
+}
+
+func (r *digestSettingRepository) MarkDefaultSent(ctx context.Context, userID, day string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.defaultSent[userID] >= day {
+		return false, nil
+	}
+	r.defaultSent[userID] = day
+	return true, nil
+}
@@ -0,0 +1,47 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type oidcStateRepository struct {
+	mu     sync.Mutex
+	states map[string]*schemas.OIDCLoginState
+}
+
+func NewOIDCStateRepository() interfaces.OIDCStateRepository {
+	return &oidcStateRepository{states: make(map[string]*schemas.OIDCLoginState)}
+}
+
+func (r *oidcStateRepository) Save(ctx context.Context, state *schemas.OIDCLoginState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, s := range r.states {
+		if s.ExpiresAt.Before(now) {
+			delete(r.states, key)
+		}
+	}
+	stored := *state
+	stored.CreatedAt = &now
+	r.states[state.State] = &stored
+	return nil
+}
+
+func (r *oidcStateRepository) Consume(ctx context.Context, state string) (*schemas.OIDCLoginState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, exists := r.states[state]
+	if !exists {
+		return nil, nil
+	}
+	delete(r.states, state)
+	return s, nil
+}
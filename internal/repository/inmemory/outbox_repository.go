@@ -0,0 +1,177 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type outboxSink struct {
+	delivered   map[int64]bool
+	lockedBy    string
+	lockedUntil time.Time
+	failures    int
+	lastError   string
+}
+
+type outboxRepository struct {
+	mu     sync.Mutex
+	events []schemas.OutboxEvent // по возрастанию Seq
+	seq    int64
+	sinks  map[string]*outboxSink
+}
+
+// NewOutboxRepository — outbox в памяти; передаётся в репозитории, чтобы их события попадали сюда
+func NewOutboxRepository() interfaces.OutboxRepository {
+	return &outboxRepository{sinks: make(map[string]*outboxSink)}
+}
+
+func (r *outboxRepository) Append(ctx context.Context, events ...*schemas.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range events {
+		r.seq++
+		r.events = append(r.events, schemas.OutboxEvent{Seq: r.seq, Event: *e})
+	}
+	return nil
+}
+
+func (r *outboxRepository) sink(name string) *outboxSink {
+	s, ok := r.sinks[name]
+	if !ok {
+		s = &outboxSink{delivered: make(map[int64]bool)}
+		r.sinks[name] = s
+	}
+	return s
+}
+
+func (r *outboxRepository) Pending(ctx context.Context, sink string, limit int) ([]schemas.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.sink(sink)
+	events := []schemas.OutboxEvent{}
+	for _, e := range r.events {
+		if len(events) >= limit {
+			break
+		}
+		if !s.delivered[e.Seq] {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) After(ctx context.Context, seq int64, limit int) ([]schemas.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []schemas.OutboxEvent{}
+	for _, e := range r.events {
+		if len(events) >= limit {
+			break
+		}
+		if e.Seq > seq {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) LastSeq(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.seq, nil
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, sink string, seq int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.sink(sink)
+	s.delivered[seq] = true
+	s.failures = 0
+	s.lastError = ""
+	return nil
+}
+
+func (r *outboxRepository) RecordFailure(ctx context.Context, sink string, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.sink(sink)
+	s.failures++
+	s.lastError = message
+	return nil
+}
+
+func (r *outboxRepository) ClaimSink(ctx context.Context, sink, owner string, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.sink(sink)
+	now := time.Now()
+	if s.lockedBy != "" && s.lockedBy != owner && s.lockedUntil.After(now) {
+		return false, nil
+	}
+	s.lockedBy = owner
+	s.lockedUntil = now.Add(lease)
+	return true, nil
+}
+
+func (r *outboxRepository) Progress(ctx context.Context, sinks []string) ([]schemas.OutboxSinkProgress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	progress := make([]schemas.OutboxSinkProgress, 0, len(sinks))
+	for _, name := range sinks {
+		s := r.sink(name)
+		p := schemas.OutboxSinkProgress{Sink: name, Failures: s.failures, LastError: s.lastError}
+		for _, e := range r.events {
+			if !s.delivered[e.Seq] {
+				p.Pending++
+				continue
+			}
+			p.Delivered++
+			if e.Seq > p.LastDeliveredSeq {
+				p.LastDeliveredSeq = e.Seq
+			}
+		}
+		progress = append(progress, p)
+	}
+	return progress, nil
+}
+
+func (r *outboxRepository) Purge(ctx context.Context, before time.Time, sinks []string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.events[:0]
+	purged := 0
+	for _, e := range r.events {
+		if e.OccurredAt.Before(before) && r.deliveredToAll(e.Seq, sinks) {
+			purged++
+			for _, s := range r.sinks {
+				delete(s.delivered, e.Seq)
+			}
+			continue
+		}
+		kept = append(kept, e)
+	}
+	r.events = kept
+	return purged, nil
+}
+
+func (r *outboxRepository) deliveredToAll(seq int64, sinks []string) bool {
+	for _, name := range sinks {
+		if s, ok := r.sinks[name]; !ok || !s.delivered[seq] {
+			return false
+		}
+	}
+	return true
+}
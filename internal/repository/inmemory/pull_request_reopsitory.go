@@ -1,39 +1,54 @@
 package inmemory
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
 	"ReviewAssigner/internal/domain/interfaces"
 	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
 )
 
 type pullRequestRepository struct {
 	mu        sync.RWMutex
 	prs       map[string]*schemas.PullRequest
 	reviewers map[string][]string // prID -> []userID
+	outbox    interfaces.OutboxRepository // nil — события не сохраняются
 }
 
-func NewPullRequestRepository() interfaces.PullRequestRepository {
+func NewPullRequestRepository(outbox interfaces.OutboxRepository) interfaces.PullRequestRepository {
 	return &pullRequestRepository{
 		prs:       make(map[string]*schemas.PullRequest),
 		reviewers: make(map[string][]string),
+		outbox:    outbox,
 	}
 }
 
-func (r *pullRequestRepository) Create(pr *schemas.PullRequest) error {
+func (r *pullRequestRepository) Create(ctx context.Context, pr *schemas.PullRequest, events ...*schemas.Event) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, exists := r.prs[pr.ID]; exists {
-		return errors.New("PR already exists")
+		return pkgerrors.ErrPRExists
+	}
+	if pr.Version == 0 {
+		pr.Version = 1
 	}
 	r.prs[pr.ID] = pr
 	r.reviewers[pr.ID] = pr.AssignedReviewers
-	return nil
+	return r.appendEvents(ctx, events)
+}
+
+// appendEvents пишет события в outbox под той же блокировкой, что и изменение
+func (r *pullRequestRepository) appendEvents(ctx context.Context, events []*schemas.Event) error {
+	if r.outbox == nil || len(events) == 0 {
+		return nil
+	}
+	return r.outbox.Append(ctx, events...)
 }
 
-func (r *pullRequestRepository) GetByID(id string) (*schemas.PullRequest, error) {
+func (r *pullRequestRepository) GetByID(ctx context.Context, id string) (*schemas.PullRequest, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -41,11 +56,33 @@ func (r *pullRequestRepository) GetByID(id string) (*schemas.PullRequest, error)
 	if !exists {
 		return nil, nil
 	}
-	pr.AssignedReviewers = r.reviewers[id]
-	return pr, nil
+	// Копия: версия и статус прочитанного PR не должны меняться вместе с хранилищем
+	found := *pr
+	found.AssignedReviewers = r.reviewers[id]
+	return &found, nil
 }
 
-func (r *pullRequestRepository) UpdateStatus(id string, status string, mergedAt *time.Time) (*schemas.PullRequest, error) {
+// GetByIDForUpdate — единицы работы и так выполняются по очереди (см. txManager)
+func (r *pullRequestRepository) GetByIDForUpdate(ctx context.Context, id string) (*schemas.PullRequest, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *pullRequestRepository) GetByIDs(ctx context.Context, ids []string) ([]schemas.PullRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var prs []schemas.PullRequest
+	for _, id := range ids {
+		if pr, exists := r.prs[id]; exists {
+			found := *pr
+			found.AssignedReviewers = r.reviewers[id]
+			prs = append(prs, found)
+		}
+	}
+	return prs, nil
+}
+
+func (r *pullRequestRepository) UpdateStatus(ctx context.Context, id string, version int, status string, mergedAt *time.Time, events ...*schemas.Event) (*schemas.PullRequest, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -53,23 +90,49 @@ func (r *pullRequestRepository) UpdateStatus(id string, status string, mergedAt
 	if !exists {
 		return nil, errors.New("PR not found")
 	}
+	if pr.Version != version {
+		return nil, pkgerrors.ErrVersionConflict
+	}
 	pr.Status = status
 	pr.MergedAt = mergedAt
-	return pr, nil
+	pr.Version++
+	updated := *pr
+	updated.AssignedReviewers = r.reviewers[id]
+	return &updated, r.appendEvents(ctx, events)
 }
 
-func (r *pullRequestRepository) UpdateReviewers(id string, reviewers []string) error {
+func (r *pullRequestRepository) UpdateReviewers(ctx context.Context, id string, version int, reviewers []string, events ...*schemas.Event) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.prs[id]; !exists {
+	pr, exists := r.prs[id]
+	if !exists {
 		return errors.New("PR not found")
 	}
+	if pr.Version != version {
+		return pkgerrors.ErrVersionConflict
+	}
+	pr.Version++
 	r.reviewers[id] = reviewers
+	return r.appendEvents(ctx, events)
+}
+
+func (r *pullRequestRepository) UpdateSCMSync(ctx context.Context, id string, state schemas.SCMSyncState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pr, exists := r.prs[id]
+	if !exists {
+		return errors.New("PR not found")
+	}
+	pr.SCMSyncStatus = state.Status
+	pr.SCMSyncError = state.Error
+	pr.SCMSyncAttempts = state.Attempts
+	pr.SCMSyncedAt = state.SyncedAt
 	return nil
 }
 
-func (r *pullRequestRepository) GetByReviewerID(userID string) ([]schemas.PullRequestShort, error) {
+func (r *pullRequestRepository) GetByReviewerID(ctx context.Context, userID string) ([]schemas.PullRequestShort, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -79,10 +142,11 @@ func (r *pullRequestRepository) GetByReviewerID(userID string) ([]schemas.PullRe
 			if rID == userID {
 				pr := r.prs[prID]
 				prs = append(prs, schemas.PullRequestShort{
-					ID:       pr.ID,
-					Name:     pr.Name,
-					AuthorID: pr.AuthorID,
-					Status:   pr.Status,
+					ID:        pr.ID,
+					Name:      pr.Name,
+					AuthorID:  pr.AuthorID,
+					Status:    pr.Status,
+					CreatedAt: pr.CreatedAt,
 				})
 				break
 			}
@@ -91,7 +155,21 @@ func (r *pullRequestRepository) GetByReviewerID(userID string) ([]schemas.PullRe
 	return prs, nil
 }
 
-func (r *pullRequestRepository) Exists(id string) (bool, error) {
+func (r *pullRequestRepository) GetByReviewerIDs(ctx context.Context, userIDs []string) (map[string][]schemas.PullRequestShort, error) {
+	result := make(map[string][]schemas.PullRequestShort, len(userIDs))
+	for _, userID := range userIDs {
+		prs, err := r.GetByReviewerID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(prs) > 0 {
+			result[userID] = prs
+		}
+	}
+	return result, nil
+}
+
+func (r *pullRequestRepository) Exists(ctx context.Context, id string) (bool, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -100,7 +178,7 @@ func (r *pullRequestRepository) Exists(id string) (bool, error) {
 }
 
 // GetStats возвращает статистику по pull requests
-func (r *pullRequestRepository) GetStats() (map[string]int, map[string]int, error) {
+func (r *pullRequestRepository) GetStats(ctx context.Context) (map[string]int, map[string]int, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -126,6 +204,9 @@ func (r *pullRequestRepository) AddPR(pr *schemas.PullRequest) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if pr.Version == 0 {
+		pr.Version = 1
+	}
 	r.prs[pr.ID] = pr
 	r.reviewers[pr.ID] = pr.AssignedReviewers
 }
@@ -0,0 +1,45 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+// idleBucketTTL — корзины, к которым давно не обращались, удаляются: они всё равно уже полные
+const idleBucketTTL = time.Hour
+
+type rateLimitRepository struct {
+	mu        sync.Mutex
+	buckets   map[string]*schemas.TokenBucket
+	lastSweep time.Time
+}
+
+func NewRateLimitRepository() interfaces.RateLimitRepository {
+	return &rateLimitRepository{buckets: make(map[string]*schemas.TokenBucket), lastSweep: time.Now()}
+}
+
+func (r *rateLimitRepository) Take(ctx context.Context, key string, limit schemas.RateLimit) (schemas.RateLimitResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.lastSweep) > idleBucketTTL {
+		for k, b := range r.buckets {
+			if now.Sub(b.UpdatedAt) > idleBucketTTL {
+				delete(r.buckets, k)
+			}
+		}
+		r.lastSweep = now
+	}
+
+	bucket, exists := r.buckets[key]
+	if !exists {
+		bucket = &schemas.TokenBucket{}
+		r.buckets[key] = bucket
+	}
+	return bucket.Take(limit, now), nil
+}
@@ -0,0 +1,75 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type roleBindingRepository struct {
+	mu       sync.RWMutex
+	bindings map[schemas.RoleBinding]time.Time // ключ — binding без CreatedAt
+}
+
+func NewRoleBindingRepository() interfaces.RoleBindingRepository {
+	return &roleBindingRepository{bindings: make(map[schemas.RoleBinding]time.Time)}
+}
+
+func bindingKey(b *schemas.RoleBinding) schemas.RoleBinding {
+	return schemas.RoleBinding{Login: b.Login, Role: b.Role, TeamName: b.TeamName}
+}
+
+func (r *roleBindingRepository) Create(ctx context.Context, binding *schemas.RoleBinding) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := bindingKey(binding)
+	if _, exists := r.bindings[key]; !exists {
+		r.bindings[key] = time.Now()
+	}
+	return nil
+}
+
+func (r *roleBindingRepository) Delete(ctx context.Context, binding *schemas.RoleBinding) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := bindingKey(binding)
+	_, exists := r.bindings[key]
+	delete(r.bindings, key)
+	return exists, nil
+}
+
+func (r *roleBindingRepository) ListByLogin(ctx context.Context, login string) ([]schemas.RoleBinding, error) {
+	all, _ := r.List(ctx)
+	bindings := []schemas.RoleBinding{}
+	for _, b := range all {
+		if b.Login == login {
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings, nil
+}
+
+func (r *roleBindingRepository) List(ctx context.Context) ([]schemas.RoleBinding, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bindings := make([]schemas.RoleBinding, 0, len(r.bindings))
+	for key, createdAt := range r.bindings {
+		createdAt := createdAt
+		key.CreatedAt = &createdAt
+		bindings = append(bindings, key)
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		if bindings[i].Login != bindings[j].Login {
+			return bindings[i].Login < bindings[j].Login
+		}
+		return bindings[i].TeamName < bindings[j].TeamName
+	})
+	return bindings, nil
+}
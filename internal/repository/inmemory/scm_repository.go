@@ -0,0 +1,123 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+// deliveryRetention — сколько помнить обработанные доставки; провайдеры не повторяют их дольше
+const deliveryRetention = 7 * 24 * time.Hour
+
+type scmIdentityRepository struct {
+	mu         sync.RWMutex
+	identities map[[2]string]schemas.SCMIdentity // ключ — provider, username
+}
+
+func NewSCMIdentityRepository() interfaces.SCMIdentityRepository {
+	return &scmIdentityRepository{identities: make(map[[2]string]schemas.SCMIdentity)}
+}
+
+func (r *scmIdentityRepository) Upsert(ctx context.Context, identity *schemas.SCMIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, existing := range r.identities {
+		if existing.Provider == identity.Provider && existing.UserID == identity.UserID {
+			delete(r.identities, key)
+		}
+	}
+	now := time.Now()
+	stored := *identity
+	stored.CreatedAt = &now
+	r.identities[[2]string{identity.Provider, identity.Username}] = stored
+	return nil
+}
+
+func (r *scmIdentityRepository) Delete(ctx context.Context, provider, username string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := [2]string{provider, username}
+	_, exists := r.identities[key]
+	delete(r.identities, key)
+	return exists, nil
+}
+
+func (r *scmIdentityRepository) GetByUsername(ctx context.Context, provider, username string) (*schemas.SCMIdentity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	identity, exists := r.identities[[2]string{provider, username}]
+	if !exists {
+		return nil, nil
+	}
+	return &identity, nil
+}
+
+func (r *scmIdentityRepository) GetByUserID(ctx context.Context, provider, userID string) (*schemas.SCMIdentity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.UserID == userID {
+			found := identity
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *scmIdentityRepository) List(ctx context.Context, provider string) ([]schemas.SCMIdentity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	identities := []schemas.SCMIdentity{}
+	for _, identity := range r.identities {
+		if provider == "" || identity.Provider == provider {
+			identities = append(identities, identity)
+		}
+	}
+	sort.Slice(identities, func(i, j int) bool {
+		if identities[i].Provider != identities[j].Provider {
+			return identities[i].Provider < identities[j].Provider
+		}
+		return identities[i].Username < identities[j].Username
+	})
+	return identities, nil
+}
+
+type scmDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries map[[2]string]time.Time
+}
+
+func NewSCMDeliveryRepository() interfaces.SCMDeliveryRepository {
+	return &scmDeliveryRepository{deliveries: make(map[[2]string]time.Time)}
+}
+
+func (r *scmDeliveryRepository) IsProcessed(ctx context.Context, provider, deliveryID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, exists := r.deliveries[[2]string{provider, deliveryID}]
+	return exists, nil
+}
+
+func (r *scmDeliveryRepository) MarkProcessed(ctx context.Context, provider, deliveryID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, processedAt := range r.deliveries {
+		if now.Sub(processedAt) > deliveryRetention {
+			delete(r.deliveries, key)
+		}
+	}
+	r.deliveries[[2]string{provider, deliveryID}] = now
+	return nil
+}
@@ -1,41 +1,140 @@
 package inmemory
 
 import (
+	"context"
 	"errors"
+	"sort"
+	"sync"
 	"ReviewAssigner/internal/domain/interfaces"
 	"ReviewAssigner/internal/domain/schemas"
 )
 
 type teamRepository struct {
+	mu    sync.RWMutex // на время единицы работы его держит txManager
 	teams map[string]*schemas.Team
 }
 
+// copyTeam — копия со своим списком участников: изменения хранилища не видны прочитанной команде
+func copyTeam(team *schemas.Team) *schemas.Team {
+	copied := *team
+	copied.Members = append([]schemas.User(nil), team.Members...)
+	return &copied
+}
+
 func NewTeamRepository() interfaces.TeamRepository {
 	return &teamRepository{teams: make(map[string]*schemas.Team)}
 }
 
-func (r *teamRepository) Create(team *schemas.Team) error {
+func (r *teamRepository) Create(ctx context.Context, team *schemas.Team) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if _, exists := r.teams[team.Name]; exists {
 		return errors.New("team already exists")
 	}
-	r.teams[team.Name] = team
+	r.teams[team.Name] = copyTeam(team)
 	return nil
 }
 
-func (r *teamRepository) GetByName(name string) (*schemas.Team, error) {
+func (r *teamRepository) GetByName(ctx context.Context, name string) (*schemas.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	team, exists := r.teams[name]
 	if !exists {
 		return nil, nil
 	}
-	return team, nil
+	return copyTeam(team), nil
+}
+
+func (r *teamRepository) GetByNames(ctx context.Context, names []string) ([]schemas.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var teams []schemas.Team
+	for _, name := range names {
+		if team, exists := r.teams[name]; exists {
+			teams = append(teams, *copyTeam(team))
+		}
+	}
+	return teams, nil
 }
 
-func (r *teamRepository) Exists(name string) (bool, error) {
+func (r *teamRepository) Exists(ctx context.Context, name string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	_, exists := r.teams[name]
 	return exists, nil
 }
 
 // Методы для тестов: AddTeam для инициализации
 func (r *teamRepository) AddTeam(team *schemas.Team) {
-	r.teams[team.Name] = team
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.teams[team.Name] = copyTeam(team)
+}
+
+func (r *teamRepository) List(ctx context.Context) ([]schemas.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.teams))
+	for name := range r.teams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	teams := make([]schemas.Team, 0, len(names))
+	for _, name := range names {
+		teams = append(teams, *copyTeam(r.teams[name]))
+	}
+	return teams, nil
+}
+
+func (r *teamRepository) ApplyRoster(ctx context.Context, diff *schemas.RosterDiff) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Проверяем всё заранее, чтобы не применить изменения частично
+	created := make(map[string]bool, len(diff.TeamsCreated))
+	for _, name := range diff.TeamsCreated {
+		created[name] = true
+	}
+	for _, settings := range diff.SettingsChanged {
+		if _, exists := r.teams[settings.TeamName]; !exists && !created[settings.TeamName] {
+			return errors.New("team not found")
+		}
+	}
+	for _, user := range diff.ChangedUsers() {
+		if _, exists := r.teams[user.TeamName]; !exists && !created[user.TeamName] && user.TeamName != "" {
+			return errors.New("team not found")
+		}
+	}
+
+	for _, name := range diff.TeamsCreated {
+		if _, exists := r.teams[name]; !exists {
+			r.teams[name] = &schemas.Team{Name: name, ReviewersCount: schemas.DefaultReviewersCount, Members: []schemas.User{}}
+		}
+	}
+
+	for _, settings := range diff.SettingsChanged {
+		r.teams[settings.TeamName].ReviewersCount = settings.ReviewersCount
+	}
+
+	for _, user := range diff.ChangedUsers() {
+		// Убираем пользователя из прежней команды
+		for _, team := range r.teams {
+			for i, m := range team.Members {
+				if m.ID == user.ID {
+					team.Members = append(team.Members[:i:i], team.Members[i+1:]...)
+					break
+				}
+			}
+		}
+		if team, exists := r.teams[user.TeamName]; exists {
+			team.Members = append(team.Members, user)
+		}
+	}
+	return nil
 }
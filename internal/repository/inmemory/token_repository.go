@@ -0,0 +1,118 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type tokenRepository struct {
+	mu      sync.RWMutex
+	refresh map[string]*schemas.RefreshToken
+	revoked map[string]time.Time // jti -> expiresAt
+	cutoffs map[string]time.Time
+}
+
+func NewTokenRepository() interfaces.TokenRepository {
+	return &tokenRepository{
+		refresh: make(map[string]*schemas.RefreshToken),
+		revoked: make(map[string]time.Time),
+		cutoffs: make(map[string]time.Time),
+	}
+}
+
+func (r *tokenRepository) CreateRefreshToken(ctx context.Context, token *schemas.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *token
+	now := time.Now()
+	stored.CreatedAt = &now
+	r.refresh[token.TokenHash] = &stored
+	return nil
+}
+
+func (r *tokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*schemas.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	token, exists := r.refresh[tokenHash]
+	if !exists {
+		return nil, nil
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (r *tokenRepository) RevokeRefreshToken(ctx context.Context, tokenHash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, exists := r.refresh[tokenHash]
+	if !exists || token.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.RevokedAt = &now
+	return true, nil
+}
+
+func (r *tokenRepository) RevokeRefreshFamily(ctx context.Context, familyID string) error {
+	r.revokeWhere(func(t *schemas.RefreshToken) bool { return t.FamilyID == familyID })
+	return nil
+}
+
+func (r *tokenRepository) RevokeRefreshTokensByLogin(ctx context.Context, login string) error {
+	r.revokeWhere(func(t *schemas.RefreshToken) bool { return t.Login == login })
+	return nil
+}
+
+func (r *tokenRepository) revokeWhere(match func(*schemas.RefreshToken) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range r.refresh {
+		if token.RevokedAt == nil && match(token) {
+			token.RevokedAt = &now
+		}
+	}
+}
+
+func (r *tokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revoked[jti] = expiresAt
+	return nil
+}
+
+func (r *tokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, revoked := r.revoked[jti]
+	return revoked, nil
+}
+
+func (r *tokenRepository) SetCutoff(ctx context.Context, login string, issuedBefore time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cutoffs[login] = issuedBefore
+	return nil
+}
+
+func (r *tokenRepository) GetCutoff(ctx context.Context, login string) (*time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cutoff, exists := r.cutoffs[login]
+	if !exists {
+		return nil, nil
+	}
+	return &cutoff, nil
+}
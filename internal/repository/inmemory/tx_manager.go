@@ -0,0 +1,124 @@
+package inmemory
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+// txManager выполняет единицы работы по очереди над копиями репозиториев: при успехе копии
+// подменяют состояние, а накопленные события уходят в outbox; при ошибке копии отбрасываются.
+// Всю единицу работы репозитории заблокированы, поэтому вызовы вне неё ждут фиксации и не теряются при подмене.
+type txManager struct {
+	users *userRepository
+	teams *teamRepository
+	prs   *pullRequestRepository
+}
+
+// NewTxManager принимает репозитории, созданные New*Repository этого пакета
+func NewTxManager(users interfaces.UserRepository, teams interfaces.TeamRepository, prs interfaces.PullRequestRepository) interfaces.TxManager {
+	return &txManager{
+		users: users.(*userRepository),
+		teams: teams.(*teamRepository),
+		prs:   prs.(*pullRequestRepository),
+	}
+}
+
+func (m *txManager) Do(ctx context.Context, fn func(repos interfaces.TxRepositories) error) error {
+	// Порядок блокировок один для всех единиц работы; сами репозитории друг друга не блокируют
+	m.users.mu.Lock()
+	defer m.users.mu.Unlock()
+	m.teams.mu.Lock()
+	defer m.teams.mu.Unlock()
+	m.prs.mu.Lock()
+	defer m.prs.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	userOutbox, prOutbox := newTxOutbox(m.users.outbox), newTxOutbox(m.prs.outbox)
+	users := m.users.clone(userOutbox)
+	teams := m.teams.clone()
+	prs := m.prs.clone(prOutbox)
+	if err := fn(interfaces.TxRepositories{Users: users, Teams: teams, PullRequests: prs}); err != nil {
+		return err
+	}
+	// Отменённая до фиксации единица работы отбрасывается, как откат транзакции
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.users.users = users.users
+	m.teams.teams = teams.teams
+	m.prs.prs, m.prs.reviewers = prs.prs, prs.reviewers
+	if err := userOutbox.flush(ctx); err != nil {
+		return err
+	}
+	return prOutbox.flush(ctx)
+}
+
+// txOutbox копит события единицы работы до её фиксации
+type txOutbox struct {
+	interfaces.OutboxRepository
+	events []*schemas.Event
+}
+
+// newTxOutbox возвращает nil для репозитория без outbox, чтобы события по-прежнему не сохранялись
+func newTxOutbox(outbox interfaces.OutboxRepository) *txOutbox {
+	if outbox == nil {
+		return nil
+	}
+	return &txOutbox{OutboxRepository: outbox}
+}
+
+func (o *txOutbox) Append(ctx context.Context, events ...*schemas.Event) error {
+	o.events = append(o.events, events...)
+	return nil
+}
+
+func (o *txOutbox) flush(ctx context.Context) error {
+	if o == nil || len(o.events) == 0 {
+		return nil
+	}
+	return o.OutboxRepository.Append(ctx, o.events...)
+}
+
+// outboxOf не даёт nil *txOutbox превратиться в ненулевой интерфейс
+func outboxOf(o *txOutbox) interfaces.OutboxRepository {
+	if o == nil {
+		return nil
+	}
+	return o
+}
+
+// clone-методы вызываются из txManager.Do, который уже держит блокировку репозитория
+func (r *userRepository) clone(outbox *txOutbox) *userRepository {
+	users := make(map[string]*schemas.User, len(r.users))
+	for id, u := range r.users {
+		copied := *u
+		users[id] = &copied
+	}
+	return &userRepository{users: users, outbox: outboxOf(outbox)}
+}
+
+func (r *teamRepository) clone() *teamRepository {
+	teams := make(map[string]*schemas.Team, len(r.teams))
+	for name, t := range r.teams {
+		teams[name] = copyTeam(t)
+	}
+	return &teamRepository{teams: teams}
+}
+
+func (r *pullRequestRepository) clone(outbox *txOutbox) *pullRequestRepository {
+	prs := make(map[string]*schemas.PullRequest, len(r.prs))
+	for id, pr := range r.prs {
+		copied := *pr
+		prs[id] = &copied
+	}
+	reviewers := make(map[string][]string, len(r.reviewers))
+	for id, ids := range r.reviewers {
+		reviewers[id] = append([]string(nil), ids...)
+	}
+	return &pullRequestRepository{prs: prs, reviewers: reviewers, outbox: outboxOf(outbox)}
+}
@@ -0,0 +1,119 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTx(t *testing.T) (interfaces.UserRepository, interfaces.TeamRepository, interfaces.PullRequestRepository, interfaces.TxManager) {
+	t.Helper()
+	users := NewUserRepository(NewOutboxRepository())
+	teams := NewTeamRepository()
+	prs := NewPullRequestRepository(NewOutboxRepository())
+	members := []schemas.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+	}
+	teams.(*teamRepository).AddTeam(&schemas.Team{Name: "backend", Members: members})
+	for i := range members {
+		users.(*userRepository).AddUser(&members[i])
+	}
+	return users, teams, prs, NewTxManager(users, teams, prs)
+}
+
+func TestTxManager_WriteOutsideUnitOfWorkIsNotReverted(t *testing.T) {
+	ctx := context.Background()
+	users, _, prs, tx := newTestTx(t)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- tx.Do(ctx, func(repos interfaces.TxRepositories) error {
+			close(started)
+			<-release
+			return repos.PullRequests.Create(ctx, &schemas.PullRequest{ID: "pr1", AuthorID: "u1"})
+		})
+	}()
+	<-started
+
+	updated := make(chan error, 1)
+	go func() {
+		_, err := users.UpdateIsActive(ctx, "u2", false)
+		updated <- err
+	}()
+	select {
+	case <-updated:
+		t.Fatal("write outside the unit of work did not wait for its commit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, <-updated)
+
+	// Фиксация единицы работы не откатила деактивацию, сделанную во время неё
+	user, err := users.GetByID(ctx, "u2")
+	require.NoError(t, err)
+	assert.False(t, user.IsActive)
+	exists, err := prs.Exists(ctx, "pr1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+// Запускать с -race: единицы работы идут параллельно с чтением и записью вне их
+func TestTxManager_ConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	users, teams, prs, tx := newTestTx(t)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			err := tx.Do(ctx, func(repos interfaces.TxRepositories) error {
+				candidates, err := repos.Users.GetActiveByTeamForShare(ctx, "backend", "u1")
+				if err != nil {
+					return err
+				}
+				reviewers := []string{}
+				for _, c := range candidates {
+					reviewers = append(reviewers, c.ID)
+				}
+				return repos.PullRequests.Create(ctx, &schemas.PullRequest{ID: fmt.Sprintf("pr%d", i), AuthorID: "u1", AssignedReviewers: reviewers})
+			})
+			assert.NoError(t, err)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			_, err := users.UpdateIsActive(ctx, "u2", i%2 == 0)
+			assert.NoError(t, err)
+			_, err = users.List(ctx)
+			assert.NoError(t, err)
+		}(i)
+		go func() {
+			defer wg.Done()
+			team, err := teams.GetByName(ctx, "backend")
+			assert.NoError(t, err)
+			assert.Len(t, team.Members, 2)
+			_, _, err = prs.GetStats(ctx)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < workers; i++ {
+		exists, err := prs.Exists(ctx, fmt.Sprintf("pr%d", i))
+		require.NoError(t, err)
+		assert.True(t, exists)
+	}
+}
@@ -1,37 +1,71 @@
 package inmemory
 
 import (
+	"context"
 	"errors"
+	"sort"
+	"sync"
 	"ReviewAssigner/internal/domain/interfaces"
 	"ReviewAssigner/internal/domain/schemas"
 )
 
 type userRepository struct {
-	users map[string]*schemas.User
+	mu     sync.RWMutex // на время единицы работы его держит txManager
+	users  map[string]*schemas.User
+	outbox interfaces.OutboxRepository // nil — события не сохраняются
 }
 
-func NewUserRepository() interfaces.UserRepository {
-	return &userRepository{users: make(map[string]*schemas.User)}
+func NewUserRepository(outbox interfaces.OutboxRepository) interfaces.UserRepository {
+	return &userRepository{users: make(map[string]*schemas.User), outbox: outbox}
 }
 
-func (r *userRepository) GetByID(userID string) (*schemas.User, error) {
+func (r *userRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	user, exists := r.users[userID]
 	if !exists {
 		return nil, nil
 	}
-	return user, nil
+	found := *user
+	return &found, nil
+}
+
+func (r *userRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []schemas.User
+	for _, id := range userIDs {
+		if user, exists := r.users[id]; exists {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
 }
 
-func (r *userRepository) UpdateIsActive(userID string, isActive bool) (*schemas.User, error) {
+func (r *userRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	user, exists := r.users[userID]
 	if !exists {
 		return nil, errors.New("user not found")
 	}
 	user.IsActive = isActive
-	return user, nil
+	if r.outbox != nil {
+		if err := r.outbox.Append(ctx, events...); err != nil {
+			return nil, err
+		}
+	}
+	updated := *user
+	return &updated, nil
 }
 
-func (r *userRepository) GetActiveByTeam(teamName string, excludeUserID string) ([]schemas.User, error) {
+func (r *userRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	var users []schemas.User
 	for _, user := range r.users {
 		if user.TeamName == teamName && user.IsActive && user.ID != excludeUserID {
@@ -41,7 +75,26 @@ func (r *userRepository) GetActiveByTeam(teamName string, excludeUserID string)
 	return users, nil
 }
 
+// GetActiveByTeamForShare — единицы работы и так выполняются по очереди (см. txManager)
+func (r *userRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	return r.GetActiveByTeam(ctx, teamName, excludeUserID)
+}
+
+func (r *userRepository) List(ctx context.Context) ([]schemas.User, error) {
+	r.mu.RLock()
+	users := make([]schemas.User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, *user)
+	}
+	r.mu.RUnlock()
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
 // Методы для тестов: AddUser для инициализации
 func (r *userRepository) AddUser(user *schemas.User) {
-	r.users[user.ID] = user
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *user
+	r.users[user.ID] = &copied
 }
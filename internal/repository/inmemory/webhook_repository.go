@@ -0,0 +1,160 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+type webhookSubscriptionRepository struct {
+	mu   sync.RWMutex
+	subs map[string]*schemas.WebhookSubscription // id -> подписка
+}
+
+func NewWebhookSubscriptionRepository() interfaces.WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{subs: make(map[string]*schemas.WebhookSubscription)}
+}
+
+func (r *webhookSubscriptionRepository) Create(ctx context.Context, sub *schemas.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.subs[sub.ID]; exists {
+		return errors.New("webhook subscription already exists")
+	}
+	stored := *sub
+	now := time.Now()
+	stored.CreatedAt = &now
+	r.subs[sub.ID] = &stored
+	return nil
+}
+
+func (r *webhookSubscriptionRepository) GetByID(ctx context.Context, id string) (*schemas.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sub, exists := r.subs[id]
+	if !exists {
+		return nil, nil
+	}
+	result := *sub
+	return &result, nil
+}
+
+func (r *webhookSubscriptionRepository) List(ctx context.Context) ([]schemas.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subs := make([]schemas.WebhookSubscription, 0, len(r.subs))
+	for _, sub := range r.subs {
+		subs = append(subs, *sub)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].CreatedAt.Before(*subs[j].CreatedAt) })
+	return subs, nil
+}
+
+func (r *webhookSubscriptionRepository) Delete(ctx context.Context, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.subs[id]; !exists {
+		return false, nil
+	}
+	delete(r.subs, id)
+	return true, nil
+}
+
+type webhookDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries map[string]*schemas.WebhookDelivery // id -> доставка
+	byEvent    map[string]string                   // подписка + событие -> id доставки
+}
+
+func NewWebhookDeliveryRepository() interfaces.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{deliveries: make(map[string]*schemas.WebhookDelivery), byEvent: make(map[string]string)}
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, deliveries []schemas.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, d := range deliveries {
+		key := d.SubscriptionID + "\x00" + d.EventID
+		if _, ok := r.byEvent[key]; ok {
+			continue
+		}
+		stored := d
+		stored.CreatedAt = &now
+		r.deliveries[d.ID] = &stored
+		r.byEvent[key] = d.ID
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]schemas.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []schemas.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Status == schemas.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, *d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for _, d := range due {
+		r.deliveries[d.ID].NextAttemptAt = now.Add(lease)
+	}
+	return due, nil
+}
+
+func (r *webhookDeliveryRepository) Update(ctx context.Context, d *schemas.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.deliveries[d.ID]; !exists {
+		return errors.New("webhook delivery not found")
+	}
+	stored := *d
+	r.deliveries[d.ID] = &stored
+	return nil
+}
+
+func (r *webhookDeliveryRepository) List(ctx context.Context, status string, limit int) ([]schemas.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries := []schemas.WebhookDelivery{}
+	for _, d := range r.deliveries {
+		if status == "" || d.Status == status {
+			deliveries = append(deliveries, *d)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(*deliveries[j].CreatedAt) })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepository) Requeue(ctx context.Context, id string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, exists := r.deliveries[id]
+	if !exists {
+		return false, nil
+	}
+	d.Status = schemas.WebhookDeliveryPending
+	d.NextAttemptAt = at
+	return true, nil
+}
@@ -0,0 +1,33 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveryRepository_CreateSkipsRepublishedEvent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWebhookDeliveryRepository()
+	delivery := func(id, subscriptionID string) schemas.WebhookDelivery {
+		return schemas.WebhookDelivery{ID: id, SubscriptionID: subscriptionID, EventID: "evt-1", EventType: schemas.EventPRCreated,
+			Status: schemas.WebhookDeliveryPending, NextAttemptAt: time.Now()}
+	}
+
+	require.NoError(t, repo.Create(ctx, []schemas.WebhookDelivery{delivery("d1", "s1"), delivery("d2", "s2")}))
+	// Outbox опубликовал то же событие повторно: доставки получают новые ID, но подписчикам уже назначены
+	require.NoError(t, repo.Create(ctx, []schemas.WebhookDelivery{delivery("d3", "s1"), delivery("d4", "s2")}))
+
+	deliveries, err := repo.List(ctx, "", 10)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, d := range deliveries {
+		ids = append(ids, d.ID)
+	}
+	assert.ElementsMatch(t, []string{"d1", "d2"}, ids)
+}
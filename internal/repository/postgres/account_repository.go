@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const accountColumns = "login, COALESCE(user_id, '') AS user_id, password_hash, role, failed_attempts, locked_until, password_changed_at, created_at"
+
+type accountRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewAccountRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.AccountRepository {
+	return &accountRepository{db: db, timeouts: timeouts}
+}
+
+func (r *accountRepository) Create(ctx context.Context, account *schemas.Account) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "INSERT INTO accounts (login, user_id, password_hash, role) VALUES ($1, NULLIF($2, ''), $3, $4)",
+		account.Login, account.UserID, account.PasswordHash, account.Role)
+	return err
+}
+
+func (r *accountRepository) GetByLogin(ctx context.Context, login string) (*schemas.Account, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var account schemas.Account
+	err := r.db.GetContext(ctx, &account, "SELECT "+accountColumns+" FROM accounts WHERE login = $1", login)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *accountRepository) GetByUserID(ctx context.Context, userID string) (*schemas.Account, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var account schemas.Account
+	err := r.db.GetContext(ctx, &account, "SELECT "+accountColumns+" FROM accounts WHERE user_id = $1", userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *accountRepository) UpdatePassword(ctx context.Context, login string, passwordHash string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "UPDATE accounts SET password_hash = $1, password_changed_at = NOW(), failed_attempts = 0, locked_until = NULL WHERE login = $2",
+		passwordHash, login)
+	return err
+}
+
+func (r *accountRepository) IncrementFailedAttempts(ctx context.Context, login string) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	var attempts int
+	err := r.db.GetContext(ctx, &attempts, "UPDATE accounts SET failed_attempts = failed_attempts + 1 WHERE login = $1 RETURNING failed_attempts", login)
+	return attempts, err
+}
+
+func (r *accountRepository) Lock(ctx context.Context, login string, until time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "UPDATE accounts SET locked_until = $1, failed_attempts = 0 WHERE login = $2", until, login)
+	return err
+}
+
+func (r *accountRepository) ResetFailedAttempts(ctx context.Context, login string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "UPDATE accounts SET failed_attempts = 0, locked_until = NULL WHERE login = $1", login)
+	return err
+}
+
+func (r *accountRepository) UpdateRole(ctx context.Context, login string, role string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "UPDATE accounts SET role = $1 WHERE login = $2", role, login)
+	return err
+}
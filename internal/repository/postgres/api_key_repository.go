@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const apiKeyColumns = "id, name, key_hash, scopes, created_by, expires_at, last_used_at, revoked_at, created_at"
+
+// apiKeyRow — скоупы хранятся строкой через запятую
+type apiKeyRow struct {
+	schemas.APIKey
+	ScopesRaw string `db:"scopes"`
+}
+
+func (r apiKeyRow) toSchema() schemas.APIKey {
+	key := r.APIKey
+	key.Scopes = []string{}
+	if r.ScopesRaw != "" {
+		key.Scopes = strings.Split(r.ScopesRaw, ",")
+	}
+	return key
+}
+
+type apiKeyRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewAPIKeyRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.APIKeyRepository {
+	return &apiKeyRepository{db: db, timeouts: timeouts}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *schemas.APIKey) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "INSERT INTO api_keys (id, name, key_hash, scopes, created_by, expires_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		key.ID, key.Name, key.KeyHash, strings.Join(key.Scopes, ","), key.CreatedBy, key.ExpiresAt)
+	return err
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*schemas.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var row apiKeyRow
+	err := r.db.GetContext(ctx, &row, "SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = $1", keyHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	key := row.toSchema()
+	return &key, nil
+}
+
+func (r *apiKeyRepository) List(ctx context.Context) ([]schemas.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	var rows []apiKeyRow
+	if err := r.db.SelectContext(ctx, &rows, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY created_at"); err != nil {
+		return nil, err
+	}
+	keys := make([]schemas.APIKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.toSchema())
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = $1 WHERE id = $2", at, id)
+	return err
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// idempotencySweepInterval — как часто удаляются истёкшие ключи
+const idempotencySweepInterval = time.Hour
+
+// idempotencyRow — заголовки ответа хранятся JSON-объектом
+type idempotencyRow struct {
+	schemas.IdempotencyRecord
+	HeadersRaw []byte `db:"headers"`
+}
+
+// idempotencyRepository — общие для всех инстансов ключи: повтор может прийти на другой инстанс
+type idempotencyRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewIdempotencyRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.IdempotencyRepository {
+	return &idempotencyRepository{db: db, timeouts: timeouts, lastSweep: time.Now()}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record *schemas.IdempotencyRecord) (*schemas.IdempotencyRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	r.sweep(ctx)
+
+	// Ключ мог освободиться между INSERT и SELECT — тогда пробуем занять его ещё раз
+	for attempt := 0; attempt < 3; attempt++ {
+		// Истёкшая запись перезаписывается, живая остаётся как есть
+		res, err := r.db.ExecContext(ctx, `INSERT INTO idempotency_keys (key, request_hash, status, content_type, body, expires_at)
+			VALUES ($1, $2, 0, '', NULL, $3)
+			ON CONFLICT (key) DO UPDATE SET request_hash = EXCLUDED.request_hash, status = 0, content_type = '', headers = '{}', body = NULL,
+				expires_at = EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at < $4`,
+			record.Key, record.RequestHash, record.ExpiresAt, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if n > 0 {
+			return nil, nil
+		}
+
+		var row idempotencyRow
+		err = r.db.GetContext(ctx, &row, "SELECT key, request_hash, status, content_type, headers, body, expires_at FROM idempotency_keys WHERE key = $1", record.Key)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		existing := row.IdempotencyRecord
+		if err := json.Unmarshal(row.HeadersRaw, &existing.Headers); err != nil {
+			return nil, err
+		}
+		return &existing, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, record *schemas.IdempotencyRecord) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	headers, err := json.Marshal(record.Headers)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, "UPDATE idempotency_keys SET status = $1, content_type = $2, headers = $3, body = $4, expires_at = $5 WHERE key = $6",
+		record.Status, record.ContentType, headers, record.Body, record.ExpiresAt, record.Key)
+	return err
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, key string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE key = $1 AND status = 0", key)
+	return err
+}
+
+// sweep раз в idempotencySweepInterval удаляет истёкшие ключи; ошибки не важны — попробуем в следующий раз
+func (r *idempotencyRepository) sweep(ctx context.Context) {
+	r.mu.Lock()
+	if time.Since(r.lastSweep) < idempotencySweepInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastSweep = time.Now()
+	r.mu.Unlock()
+
+	_, _ = r.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at < $1", time.Now())
+}
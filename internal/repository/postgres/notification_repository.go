@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type notificationPreferenceRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewNotificationPreferenceRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db, timeouts: timeouts}
+}
+
+func (r *notificationPreferenceRepository) Upsert(ctx context.Context, pref *schemas.NotificationPreference) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `INSERT INTO notification_preferences (user_id, channel, address, enabled) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, channel) DO UPDATE SET address = EXCLUDED.address, enabled = EXCLUDED.enabled, updated_at = NOW()`,
+		pref.UserID, pref.Channel, pref.Address, pref.Enabled)
+	return err
+}
+
+func (r *notificationPreferenceRepository) ListByUser(ctx context.Context, userID string) ([]schemas.NotificationPreference, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	prefs := []schemas.NotificationPreference{}
+	err := r.db.SelectContext(ctx, &prefs, "SELECT user_id, channel, address, enabled, updated_at FROM notification_preferences WHERE user_id = $1 ORDER BY channel", userID)
+	return prefs, err
+}
+
+func (r *notificationPreferenceRepository) Delete(ctx context.Context, userID, channel string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, "DELETE FROM notification_preferences WHERE user_id = $1 AND channel = $2", userID, channel)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+type digestSettingRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewDigestSettingRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.DigestSettingRepository {
+	return &digestSettingRepository{db: db, timeouts: timeouts}
+}
+
+const digestSettingColumns = "user_id, channel, send_at, timezone, enabled, COALESCE(TO_CHAR(last_sent_on, 'YYYY-MM-DD'), '') AS last_sent_on"
+
+func (r *digestSettingRepository) Upsert(ctx context.Context, setting *schemas.DigestSetting) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `INSERT INTO digest_settings (user_id, channel, send_at, timezone, enabled) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET channel = EXCLUDED.channel, send_at = EXCLUDED.send_at, timezone = EXCLUDED.timezone, enabled = EXCLUDED.enabled`,
+		setting.UserID, setting.Channel, setting.SendAt, setting.Timezone, setting.Enabled)
+	return err
+}
+
+func (r *digestSettingRepository) GetByUser(ctx context.Context, userID string) (*schemas.DigestSetting, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var setting schemas.DigestSetting
+	err := r.db.GetContext(ctx, &setting, "SELECT "+digestSettingColumns+" FROM digest_settings WHERE user_id = $1", userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+func (r *digestSettingRepository) Delete(ctx context.Context, userID string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, "DELETE FROM digest_settings WHERE user_id = $1", userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *digestSettingRepository) ListEnabled(ctx context.Context) ([]schemas.DigestSetting, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	settings := []schemas.DigestSetting{}
+	err := r.db.SelectContext(ctx, &settings, "SELECT "+digestSettingColumns+" FROM digest_settings WHERE enabled ORDER BY user_id")
+	return settings, err
+}
+
+func (r *digestSettingRepository) MarkSent(ctx context.Context, userID, day string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, "UPDATE digest_settings SET last_sent_on = $2::date WHERE user_id = $1 AND (last_sent_on IS NULL OR last_sent_on < $2::date)",
+		userID, day)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *digestSettingRepository) ListConfiguredUserIDs(ctx context.Context) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	ids := []string{}
+	err := r.db.SelectContext(ctx, &ids, "SELECT user_id FROM digest_settings ORDER BY user_id")
+	return ids, err
+}
+
+func (r *digestSettingRepository) ListDefaultSent(ctx context.Context) (map[string]string, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	var rows []struct {
+		UserID     string `db:"user_id"`
+		LastSentOn string `db:"last_sent_on"`
+	}
+	err := r.db.SelectContext(ctx, &rows, "SELECT user_id, TO_CHAR(last_sent_on, 'YYYY-MM-DD') AS last_sent_on FROM digest_default_sends")
+	if err != nil {
+		return nil, err
+	}
+	sent := make(map[string]string, len(rows))
+	for _, row := range rows {
+		sent[row.UserID] = row.LastSentOn
+	}
+	return sent, nil
+}
+
+func (r *digestSettingRepository) MarkDefaultSent(ctx context.Context, userID, day string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, `INSERT INTO digest_default_sends (user_id, last_sent_on) VALUES ($1, $2::date)
+		ON CONFLICT (user_id) DO UPDATE SET last_sent_on = EXCLUDED.last_sent_on WHERE digest_default_sends.last_sent_on < EXCLUDED.last_sent_on`,
+		userID, day)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type oidcStateRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewOIDCStateRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.OIDCStateRepository {
+	return &oidcStateRepository{db: db, timeouts: timeouts}
+}
+
+func (r *oidcStateRepository) Save(ctx context.Context, state *schemas.OIDCLoginState) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	// Заодно чистим брошенные входы, чтобы таблица не росла
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM oidc_login_states WHERE expires_at < NOW()"); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, "INSERT INTO oidc_login_states (state, nonce, code_verifier, expires_at) VALUES ($1, $2, $3, $4)",
+		state.State, state.Nonce, state.CodeVerifier, state.ExpiresAt)
+	return err
+}
+
+func (r *oidcStateRepository) Consume(ctx context.Context, state string) (*schemas.OIDCLoginState, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	var s schemas.OIDCLoginState
+	err := r.db.GetContext(ctx, &s, "DELETE FROM oidc_login_states WHERE state = $1 RETURNING state, nonce, code_verifier, expires_at, created_at", state)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type outboxRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewOutboxRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.OutboxRepository {
+	return &outboxRepository{db: db, timeouts: timeouts}
+}
+
+// insertEvents пишет события в outbox внутри транзакции, меняющей состояние
+func insertEvents(ctx context.Context, tx *sqlx.Tx, events []*schemas.Event) error {
+	for _, e := range events {
+		_, err := tx.ExecContext(ctx, "INSERT INTO outbox_events (event_id, event_type, payload, occurred_at) VALUES ($1, $2, $3, $4)",
+			e.ID, e.Type, string(e.Data), e.OccurredAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *outboxRepository) Append(ctx context.Context, events ...*schemas.Event) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertEvents(ctx, tx, events); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type outboxEventRow struct {
+	Seq        int64     `db:"seq"`
+	ID         string    `db:"event_id"`
+	Type       string    `db:"event_type"`
+	Payload    string    `db:"payload"`
+	OccurredAt time.Time `db:"occurred_at"`
+}
+
+func (row outboxEventRow) toSchema() schemas.OutboxEvent {
+	return schemas.OutboxEvent{Seq: row.Seq, Event: schemas.Event{
+		ID: row.ID, Type: row.Type, OccurredAt: row.OccurredAt.UTC(), Data: json.RawMessage(row.Payload),
+	}}
+}
+
+func (r *outboxRepository) Pending(ctx context.Context, sink string, limit int) ([]schemas.OutboxEvent, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var rows []outboxEventRow
+	err := r.db.SelectContext(ctx, &rows, `SELECT e.seq, e.event_id, e.event_type, e.payload, e.occurred_at FROM outbox_events e
+		WHERE NOT EXISTS (SELECT 1 FROM outbox_deliveries d WHERE d.sink = $1 AND d.seq = e.seq)
+		ORDER BY e.seq LIMIT $2`, sink, limit)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]schemas.OutboxEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, row.toSchema())
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) After(ctx context.Context, seq int64, limit int) ([]schemas.OutboxEvent, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var rows []outboxEventRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT seq, event_id, event_type, payload, occurred_at FROM outbox_events WHERE seq > $1 ORDER BY seq LIMIT $2", seq, limit)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]schemas.OutboxEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, row.toSchema())
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) LastSeq(ctx context.Context) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var seq int64
+	err := r.db.GetContext(ctx, &seq, "SELECT COALESCE(MAX(seq), 0) FROM outbox_events")
+	return seq, err
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, sink string, seq int64) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO outbox_deliveries (sink, seq) VALUES ($1, $2) ON CONFLICT DO NOTHING", sink, seq); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE outbox_sinks SET failures = 0, last_error = NULL WHERE sink = $1 AND failures > 0", sink); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *outboxRepository) RecordFailure(ctx context.Context, sink string, message string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `INSERT INTO outbox_sinks (sink, failures, last_error) VALUES ($1, 1, $2)
+		ON CONFLICT (sink) DO UPDATE SET failures = outbox_sinks.failures + 1, last_error = EXCLUDED.last_error`, sink, message)
+	return err
+}
+
+func (r *outboxRepository) ClaimSink(ctx context.Context, sink, owner string, lease time.Duration) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	// Время аренды считается по часам БД, чтобы расхождение часов инстансов не мешало
+	res, err := r.db.ExecContext(ctx, `INSERT INTO outbox_sinks (sink, locked_by, locked_until) VALUES ($1, $2, NOW() + make_interval(secs => $3))
+		ON CONFLICT (sink) DO UPDATE SET locked_by = EXCLUDED.locked_by, locked_until = EXCLUDED.locked_until
+		WHERE outbox_sinks.locked_by IS NULL OR outbox_sinks.locked_by = EXCLUDED.locked_by OR outbox_sinks.locked_until < NOW()`,
+		sink, owner, lease.Seconds())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *outboxRepository) Progress(ctx context.Context, sinks []string) ([]schemas.OutboxSinkProgress, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	progress := make([]schemas.OutboxSinkProgress, 0, len(sinks))
+	for _, sink := range sinks {
+		p := schemas.OutboxSinkProgress{Sink: sink}
+		err := r.db.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(MAX(seq), 0) FROM outbox_deliveries WHERE sink = $1`, sink).
+			Scan(&p.Delivered, &p.LastDeliveredSeq)
+		if err != nil {
+			return nil, err
+		}
+		err = r.db.GetContext(ctx, &p.Pending, `SELECT COUNT(*) FROM outbox_events e
+			WHERE NOT EXISTS (SELECT 1 FROM outbox_deliveries d WHERE d.sink = $1 AND d.seq = e.seq)`, sink)
+		if err != nil {
+			return nil, err
+		}
+		err = r.db.QueryRowContext(ctx, "SELECT COALESCE(SUM(failures), 0), COALESCE(MAX(last_error), '') FROM outbox_sinks WHERE sink = $1", sink).
+			Scan(&p.Failures, &p.LastError)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, p)
+	}
+	return progress, nil
+}
+
+func (r *outboxRepository) Purge(ctx context.Context, before time.Time, sinks []string) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, `DELETE FROM outbox_events e WHERE e.occurred_at < $1
+		AND NOT EXISTS (SELECT 1 FROM unnest($2::text[]) AS s(sink)
+			WHERE NOT EXISTS (SELECT 1 FROM outbox_deliveries d WHERE d.sink = s.sink AND d.seq = e.seq))`,
+		before, pq.Array(sinks))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -1,48 +1,68 @@
 package postgres
 
 import (
+    "context"
     "database/sql"
     "time"
     "ReviewAssigner/internal/domain/schemas"
     "ReviewAssigner/internal/domain/interfaces"
+    "ReviewAssigner/internal/pkg/errors"
 
     "github.com/jmoiron/sqlx"
+    "github.com/lib/pq"
 )
 
 type pullRequestRepository struct {
-    db *sqlx.DB
+    db       dbtx
+    timeouts QueryTimeouts
 }
 
-func NewPullRequestRepository(db *sqlx.DB) interfaces.PullRequestRepository {
-    return &pullRequestRepository{db: db}
+func NewPullRequestRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.PullRequestRepository {
+    return &pullRequestRepository{db: db, timeouts: timeouts}
 }
 
-func (r *pullRequestRepository) Create(pr *schemas.PullRequest) error {
-    tx, err := r.db.Beginx()
-    if err != nil {
-        return err
-    }
-    defer tx.Rollback()
-
-    _, err = tx.Exec("INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at) VALUES ($1, $2, $3, $4, $5)",
-        pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.CreatedAt)
-    if err != nil {
-      return err
-    }
-
-    for _, reviewerID := range pr.AssignedReviewers {
-        _, err = tx.Exec("INSERT INTO pr_reviewers (pull_request_id, user_id) VALUES ($1, $2)", pr.ID, reviewerID)
+func (r *pullRequestRepository) Create(ctx context.Context, pr *schemas.PullRequest, events ...*schemas.Event) error {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+    defer cancel()
+    return inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+        // PR с тем же ID, созданный параллельно после проверки Exists, — та же ошибка PR_EXISTS
+        res, err := tx.ExecContext(ctx, "INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (pull_request_id) DO NOTHING",
+            pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.CreatedAt)
         if err != nil {
             return err
         }
-    }
+        if n, err := res.RowsAffected(); err != nil {
+            return err
+        } else if n == 0 {
+            return errors.ErrPRExists
+        }
+
+        for _, reviewerID := range pr.AssignedReviewers {
+            _, err = tx.ExecContext(ctx, "INSERT INTO pr_reviewers (pull_request_id, user_id) VALUES ($1, $2)", pr.ID, reviewerID)
+            if err != nil {
+                return err
+            }
+        }
+        return insertEvents(ctx, tx, events)
+    })
+}
 
-    return tx.Commit()
+func (r *pullRequestRepository) GetByID(ctx context.Context, id string) (*schemas.PullRequest, error) {
+    return r.get(ctx, id, "")
 }
 
-func (r *pullRequestRepository) GetByID(id string) (*schemas.PullRequest, error) {
+// GetByIDForUpdate — GetByID, блокирующий строку PR до конца единицы работы
+func (r *pullRequestRepository) GetByIDForUpdate(ctx context.Context, id string) (*schemas.PullRequest, error) {
+    return r.get(ctx, id, " FOR UPDATE")
+}
+
+func (r *pullRequestRepository) get(ctx context.Context, id string, lock string) (*schemas.PullRequest, error) {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+    defer cancel()
     var pr schemas.PullRequest
-    err := r.db.Get(&pr, "SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at FROM pull_requests WHERE pull_request_id = $1", id)
+    err := r.db.GetContext(ctx, &pr, `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version,
+        COALESCE(scm_sync_status, '') AS scm_sync_status, COALESCE(scm_sync_error, '') AS scm_sync_error, scm_sync_attempts, scm_synced_at
+        FROM pull_requests WHERE pull_request_id = $1`+lock, id)
     if err == sql.ErrNoRows {
         return nil, nil
     }
@@ -51,59 +71,146 @@ func (r *pullRequestRepository) GetByID(id string) (*schemas.PullRequest, error)
     }
 
     var reviewers []string
-    err = r.db.Select(&reviewers, "SELECT user_id FROM pr_reviewers WHERE pull_request_id = $1", id)
+    err = r.db.SelectContext(ctx, &reviewers, "SELECT user_id FROM pr_reviewers WHERE pull_request_id = $1", id)
     pr.AssignedReviewers = reviewers
     return &pr, err
 }
 
-func (r *pullRequestRepository) UpdateStatus(id string, status string, mergedAt *time.Time) (*schemas.PullRequest, error) {
-    _, err := r.db.Exec("UPDATE pull_requests SET status = $1, merged_at = $2 WHERE pull_request_id = $3", status, mergedAt, id)
+func (r *pullRequestRepository) GetByIDs(ctx context.Context, ids []string) ([]schemas.PullRequest, error) {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+    defer cancel()
+    var prs []schemas.PullRequest
+    err := r.db.SelectContext(ctx, &prs, `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, version,
+        COALESCE(scm_sync_status, '') AS scm_sync_status, COALESCE(scm_sync_error, '') AS scm_sync_error, scm_sync_attempts, scm_synced_at
+        FROM pull_requests WHERE pull_request_id = ANY($1) ORDER BY pull_request_id`, pq.Array(ids))
     if err != nil {
         return nil, err
     }
-    return r.GetByID(id)
-}
 
-func (r *pullRequestRepository) UpdateReviewers(id string, reviewers []string) error {
-    tx, err := r.db.Beginx()
-    if err != nil {
-        return err
+    var rows []struct {
+        PRID   string `db:"pull_request_id"`
+        UserID string `db:"user_id"`
+    }
+    if err := r.db.SelectContext(ctx, &rows, "SELECT pull_request_id, user_id FROM pr_reviewers WHERE pull_request_id = ANY($1)", pq.Array(ids)); err != nil {
+        return nil, err
+    }
+    reviewers := make(map[string][]string)
+    for _, row := range rows {
+        reviewers[row.PRID] = append(reviewers[row.PRID], row.UserID)
+    }
+    for i := range prs {
+        prs[i].AssignedReviewers = reviewers[prs[i].ID]
     }
-    defer tx.Rollback()
+    return prs, nil
+}
 
-    _, err = tx.Exec("DELETE FROM pr_reviewers WHERE pull_request_id = $1", id)
+func (r *pullRequestRepository) UpdateStatus(ctx context.Context, id string, version int, status string, mergedAt *time.Time, events ...*schemas.Event) (*schemas.PullRequest, error) {
+    writeCtx, cancel := withTimeout(ctx, r.timeouts.Write)
+    defer cancel()
+    err := inTx(writeCtx, r.db, func(tx *sqlx.Tx) error {
+        res, err := tx.ExecContext(writeCtx, "UPDATE pull_requests SET status = $1, merged_at = $2, version = version + 1 WHERE pull_request_id = $3 AND version = $4",
+            status, mergedAt, id, version)
+        if err := checkVersion(res, err); err != nil {
+            return err
+        }
+        return insertEvents(writeCtx, tx, events)
+    })
     if err != nil {
-        return err
+        return nil, err
     }
+    return r.GetByID(ctx, id)
+}
+
+func (r *pullRequestRepository) UpdateReviewers(ctx context.Context, id string, version int, reviewers []string, events ...*schemas.Event) error {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+    defer cancel()
+    return inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+        // Увеличение версии блокирует строку PR до конца транзакции: конкурентная запись дождётся её и не совпадёт по версии
+        res, err := tx.ExecContext(ctx, "UPDATE pull_requests SET version = version + 1 WHERE pull_request_id = $1 AND version = $2", id, version)
+        if err := checkVersion(res, err); err != nil {
+            return err
+        }
 
-    for _, reviewerID := range reviewers {
-        _, err = tx.Exec("INSERT INTO pr_reviewers (pull_request_id, user_id) VALUES ($1, $2)", id, reviewerID)
+        _, err = tx.ExecContext(ctx, "DELETE FROM pr_reviewers WHERE pull_request_id = $1", id)
         if err != nil {
             return err
         }
+
+        for _, reviewerID := range reviewers {
+            _, err = tx.ExecContext(ctx, "INSERT INTO pr_reviewers (pull_request_id, user_id) VALUES ($1, $2)", id, reviewerID)
+            if err != nil {
+                return err
+            }
+        }
+        return insertEvents(ctx, tx, events)
+    })
+}
+
+// checkVersion переводит условное обновление, не затронувшее ни одной строки, в ErrVersionConflict
+func checkVersion(res sql.Result, err error) error {
+    if err != nil {
+        return err
     }
+    n, err := res.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if n == 0 {
+        return errors.ErrVersionConflict
+    }
+    return nil
+}
 
-    return tx.Commit()
+func (r *pullRequestRepository) UpdateSCMSync(ctx context.Context, id string, state schemas.SCMSyncState) error {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+    defer cancel()
+    _, err := r.db.ExecContext(ctx, "UPDATE pull_requests SET scm_sync_status = $1, scm_sync_error = NULLIF($2, ''), scm_sync_attempts = $3, scm_synced_at = $4 WHERE pull_request_id = $5",
+        state.Status, state.Error, state.Attempts, state.SyncedAt, id)
+    return err
 }
 
-func (r *pullRequestRepository) GetByReviewerID(userID string) ([]schemas.PullRequestShort, error) {
+func (r *pullRequestRepository) GetByReviewerID(ctx context.Context, userID string) ([]schemas.PullRequestShort, error) {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+    defer cancel()
     var prs []schemas.PullRequestShort
-    err := r.db.Select(&prs, "SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status FROM pull_requests pr JOIN pr_reviewers prr ON pr.pull_request_id = prr.pull_request_id WHERE prr.user_id = $1", userID)
+    err := r.db.SelectContext(ctx, &prs, "SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at FROM pull_requests pr JOIN pr_reviewers prr ON pr.pull_request_id = prr.pull_request_id WHERE prr.user_id = $1", userID)
     return prs, err
 }
 
-func (r *pullRequestRepository) Exists(id string) (bool, error) {
+func (r *pullRequestRepository) GetByReviewerIDs(ctx context.Context, userIDs []string) (map[string][]schemas.PullRequestShort, error) {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+    defer cancel()
+    var rows []struct {
+        ReviewerID string `db:"user_id"`
+        schemas.PullRequestShort
+    }
+    err := r.db.SelectContext(ctx, &rows, "SELECT prr.user_id, pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.created_at FROM pull_requests pr JOIN pr_reviewers prr ON pr.pull_request_id = prr.pull_request_id WHERE prr.user_id = ANY($1)", pq.Array(userIDs))
+    if err != nil {
+        return nil, err
+    }
+    prs := make(map[string][]schemas.PullRequestShort)
+    for _, row := range rows {
+        prs[row.ReviewerID] = append(prs[row.ReviewerID], row.PullRequestShort)
+    }
+    return prs, nil
+}
+
+func (r *pullRequestRepository) Exists(ctx context.Context, id string) (bool, error) {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+    defer cancel()
     var count int
-    err := r.db.Get(&count, "SELECT COUNT(*) FROM pull_requests WHERE pull_request_id = $1", id)
+    err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM pull_requests WHERE pull_request_id = $1", id)
     return count > 0, err
 }
 
-func (r *pullRequestRepository) GetStats() (map[string]int, map[string]int, error) {
+func (r *pullRequestRepository) GetStats(ctx context.Context) (map[string]int, map[string]int, error) {
+    ctx, cancel := withTimeout(ctx, r.timeouts.List)
+    defer cancel()
     userStats := make(map[string]int)
     prStats := make(map[string]int)
 
     // Статистика по пользователям
-    rows, err := r.db.Query("SELECT user_id, COUNT(*) FROM pr_reviewers GROUP BY user_id")
+    rows, err := r.db.QueryContext(ctx, "SELECT user_id, COUNT(*) FROM pr_reviewers GROUP BY user_id")
     if err != nil {
         return nil, nil, err
     }
@@ -118,7 +225,7 @@ func (r *pullRequestRepository) GetStats() (map[string]int, map[string]int, erro
     }
 
     // Статистика по PR
-    rows2, err := r.db.Query("SELECT pull_request_id, COUNT(*) FROM pr_reviewers GROUP BY pull_request_id")
+    rows2, err := r.db.QueryContext(ctx, "SELECT pull_request_id, COUNT(*) FROM pr_reviewers GROUP BY pull_request_id")
     if err != nil {
         return nil, nil, err
     }
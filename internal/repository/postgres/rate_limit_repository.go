@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// idleBucketTTL — корзины, к которым давно не обращались, удаляются: они всё равно уже полные
+const idleBucketTTL = time.Hour
+
+// rateLimitRepository — общие для всех инстансов счётчики; корзина блокируется на время пересчёта
+type rateLimitRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewRateLimitRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.RateLimitRepository {
+	return &rateLimitRepository{db: db, timeouts: timeouts, lastSweep: time.Now()}
+}
+
+func (r *rateLimitRepository) Take(ctx context.Context, key string, limit schemas.RateLimit) (schemas.RateLimitResult, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	r.sweep(ctx)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return schemas.RateLimitResult{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	// Новая корзина создаётся полной; конкурентные запросы ждут на FOR UPDATE
+	if _, err := tx.ExecContext(ctx, "INSERT INTO rate_limit_buckets (key, tokens, updated_at) VALUES ($1, $2, $3) ON CONFLICT (key) DO NOTHING",
+		key, limit.Burst, now); err != nil {
+		return schemas.RateLimitResult{}, err
+	}
+	var bucket schemas.TokenBucket
+	if err := tx.GetContext(ctx, &bucket, "SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE", key); err != nil {
+		return schemas.RateLimitResult{}, err
+	}
+
+	result := bucket.Take(limit, now)
+	if _, err := tx.ExecContext(ctx, "UPDATE rate_limit_buckets SET tokens = $1, updated_at = $2 WHERE key = $3", bucket.Tokens, bucket.UpdatedAt, key); err != nil {
+		return schemas.RateLimitResult{}, err
+	}
+	return result, tx.Commit()
+}
+
+// sweep раз в idleBucketTTL удаляет старые корзины; ошибки не важны — попробуем в следующий раз
+func (r *rateLimitRepository) sweep(ctx context.Context) {
+	r.mu.Lock()
+	if time.Since(r.lastSweep) < idleBucketTTL {
+		r.mu.Unlock()
+		return
+	}
+	r.lastSweep = time.Now()
+	r.mu.Unlock()
+
+	_, _ = r.db.ExecContext(ctx, "DELETE FROM rate_limit_buckets WHERE updated_at < $1", time.Now().Add(-idleBucketTTL))
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type roleBindingRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewRoleBindingRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.RoleBindingRepository {
+	return &roleBindingRepository{db: db, timeouts: timeouts}
+}
+
+func (r *roleBindingRepository) Create(ctx context.Context, binding *schemas.RoleBinding) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "INSERT INTO role_bindings (login, role, team_name) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING",
+		binding.Login, binding.Role, binding.TeamName)
+	return err
+}
+
+func (r *roleBindingRepository) Delete(ctx context.Context, binding *schemas.RoleBinding) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, "DELETE FROM role_bindings WHERE login = $1 AND role = $2 AND team_name = $3",
+		binding.Login, binding.Role, binding.TeamName)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *roleBindingRepository) ListByLogin(ctx context.Context, login string) ([]schemas.RoleBinding, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	bindings := []schemas.RoleBinding{}
+	err := r.db.SelectContext(ctx, &bindings, "SELECT login, role, team_name, created_at FROM role_bindings WHERE login = $1 ORDER BY team_name", login)
+	return bindings, err
+}
+
+func (r *roleBindingRepository) List(ctx context.Context) ([]schemas.RoleBinding, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	bindings := []schemas.RoleBinding{}
+	err := r.db.SelectContext(ctx, &bindings, "SELECT login, role, team_name, created_at FROM role_bindings ORDER BY login, team_name")
+	return bindings, err
+}
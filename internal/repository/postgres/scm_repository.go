@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// deliveryRetention — сколько помнить обработанные доставки; провайдеры не повторяют их дольше
+const deliveryRetention = 7 * 24 * time.Hour
+
+type scmIdentityRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewSCMIdentityRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.SCMIdentityRepository {
+	return &scmIdentityRepository{db: db, timeouts: timeouts}
+}
+
+// Upsert привязывает логин провайдера к пользователю; прежняя привязка пользователя у того же провайдера заменяется
+func (r *scmIdentityRepository) Upsert(ctx context.Context, identity *schemas.SCMIdentity) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM scm_identities WHERE provider = $1 AND (user_id = $2 OR username = $3)",
+		identity.Provider, identity.UserID, identity.Username); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO scm_identities (provider, username, user_id) VALUES ($1, $2, $3)",
+		identity.Provider, identity.Username, identity.UserID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *scmIdentityRepository) Delete(ctx context.Context, provider, username string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, "DELETE FROM scm_identities WHERE provider = $1 AND username = $2", provider, username)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *scmIdentityRepository) GetByUsername(ctx context.Context, provider, username string) (*schemas.SCMIdentity, error) {
+	return r.get(ctx, "SELECT provider, username, user_id, created_at FROM scm_identities WHERE provider = $1 AND username = $2", provider, username)
+}
+
+func (r *scmIdentityRepository) GetByUserID(ctx context.Context, provider, userID string) (*schemas.SCMIdentity, error) {
+	return r.get(ctx, "SELECT provider, username, user_id, created_at FROM scm_identities WHERE provider = $1 AND user_id = $2", provider, userID)
+}
+
+func (r *scmIdentityRepository) get(ctx context.Context, query string, args ...interface{}) (*schemas.SCMIdentity, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var identity schemas.SCMIdentity
+	err := r.db.GetContext(ctx, &identity, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *scmIdentityRepository) List(ctx context.Context, provider string) ([]schemas.SCMIdentity, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	identities := []schemas.SCMIdentity{}
+	err := r.db.SelectContext(ctx, &identities, `SELECT provider, username, user_id, created_at FROM scm_identities
+		WHERE $1 = '' OR provider = $1 ORDER BY provider, username`, provider)
+	return identities, err
+}
+
+type scmDeliveryRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewSCMDeliveryRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.SCMDeliveryRepository {
+	return &scmDeliveryRepository{db: db, timeouts: timeouts}
+}
+
+func (r *scmDeliveryRepository) IsProcessed(ctx context.Context, provider, deliveryID string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM scm_deliveries WHERE provider = $1 AND delivery_id = $2)", provider, deliveryID)
+	return exists, err
+}
+
+func (r *scmDeliveryRepository) MarkProcessed(ctx context.Context, provider, deliveryID string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM scm_deliveries WHERE processed_at < $1", time.Now().Add(-deliveryRetention)); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, "INSERT INTO scm_deliveries (provider, delivery_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", provider, deliveryID)
+	return err
+}
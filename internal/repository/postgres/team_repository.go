@@ -1,56 +1,147 @@
 package postgres
 
 import (
-    // "database/sql" // УДАЛЕНО: неиспользуемый импорт
+    "context"
+    "database/sql"
     "ReviewAssigner/internal/domain/schemas"
     "ReviewAssigner/internal/domain/interfaces"
 
     "github.com/jmoiron/sqlx"
+    "github.com/lib/pq"
 )
 
 type teamRepository struct {
-    db *sqlx.DB
+    db       dbtx
+    timeouts QueryTimeouts
 }
 
-func NewTeamRepository(db *sqlx.DB) interfaces.TeamRepository {
-    return &teamRepository{db: db}
+func NewTeamRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.TeamRepository {
+    return &teamRepository{db: db, timeouts: timeouts}
 }
 
-func (r *teamRepository) Create(team *schemas.Team) error {
-    tx, err := r.db.Beginx()
-    if err != nil {
-        return err
-    }
-    defer tx.Rollback()
-
-    _, err = tx.Exec("INSERT INTO teams (team_name) VALUES ($1)", team.Name)
-    if err != nil {
-        return err
-    }
-
-    for _, member := range team.Members {
-        _, err = tx.Exec("INSERT INTO users (user_id, username, team_name, is_active) VALUES ($1, $2, $3, $4) ON CONFLICT (user_id) DO UPDATE SET username = EXCLUDED.username, team_name = EXCLUDED.team_name, is_active = EXCLUDED.is_active",
-            member.ID, member.Username, team.Name, member.IsActive)
+func (r *teamRepository) Create(ctx context.Context, team *schemas.Team) error {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+    defer cancel()
+    return inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+        _, err := tx.ExecContext(ctx, "INSERT INTO teams (team_name, reviewers_count) VALUES ($1, $2)", team.Name, team.ReviewersCount)
         if err != nil {
             return err
         }
-    }
 
-    return tx.Commit()
+        for _, member := range team.Members {
+            _, err = tx.ExecContext(ctx, "INSERT INTO users (user_id, username, team_name, is_active) VALUES ($1, $2, $3, $4) ON CONFLICT (user_id) DO UPDATE SET username = EXCLUDED.username, team_name = EXCLUDED.team_name, is_active = EXCLUDED.is_active",
+                member.ID, member.Username, team.Name, member.IsActive)
+            if err != nil {
+                return err
+            }
+        }
+        return nil
+    })
 }
 
-func (r *teamRepository) GetByName(name string) (*schemas.Team, error) {
+func (r *teamRepository) GetByName(ctx context.Context, name string) (*schemas.Team, error) {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+    defer cancel()
     var team schemas.Team
-    team.Name = name
-    err := r.db.Select(&team.Members, "SELECT user_id, username, team_name, is_active FROM users WHERE team_name = $1", name)
+    err := r.db.GetContext(ctx, &team, "SELECT team_name, reviewers_count FROM teams WHERE team_name = $1", name)
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    err = r.db.SelectContext(ctx, &team.Members, "SELECT user_id, username, team_name, is_active FROM users WHERE team_name = $1", name)
     if err != nil {
         return nil, err
     }
     return &team, nil
 }
 
-func (r *teamRepository) Exists(name string) (bool, error) {
+func (r *teamRepository) GetByNames(ctx context.Context, names []string) ([]schemas.Team, error) {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+    defer cancel()
+    var teams []schemas.Team
+    if err := r.db.SelectContext(ctx, &teams, "SELECT team_name, reviewers_count FROM teams WHERE team_name = ANY($1) ORDER BY team_name", pq.Array(names)); err != nil {
+        return nil, err
+    }
+
+    var users []schemas.User
+    if err := r.db.SelectContext(ctx, &users, "SELECT user_id, username, team_name, is_active FROM users WHERE team_name = ANY($1) ORDER BY team_name, user_id", pq.Array(names)); err != nil {
+        return nil, err
+    }
+
+    index := make(map[string]int, len(teams))
+    for i := range teams {
+        index[teams[i].Name] = i
+        teams[i].Members = []schemas.User{}
+    }
+    for _, u := range users {
+        if i, ok := index[u.TeamName]; ok {
+            teams[i].Members = append(teams[i].Members, u)
+        }
+    }
+    return teams, nil
+}
+
+func (r *teamRepository) Exists(ctx context.Context, name string) (bool, error) {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+    defer cancel()
     var count int
-    err := r.db.Get(&count, "SELECT COUNT(*) FROM teams WHERE team_name = $1", name)
+    err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM teams WHERE team_name = $1", name)
     return count > 0, err
 }
+
+func (r *teamRepository) List(ctx context.Context) ([]schemas.Team, error) {
+    ctx, cancel := withTimeout(ctx, r.timeouts.List)
+    defer cancel()
+    var teams []schemas.Team
+    if err := r.db.SelectContext(ctx, &teams, "SELECT team_name, reviewers_count FROM teams ORDER BY team_name"); err != nil {
+        return nil, err
+    }
+
+    var users []schemas.User
+    if err := r.db.SelectContext(ctx, &users, "SELECT user_id, username, team_name, is_active FROM users WHERE team_name IS NOT NULL ORDER BY team_name, user_id"); err != nil {
+        return nil, err
+    }
+
+    index := make(map[string]int, len(teams))
+    for i := range teams {
+        index[teams[i].Name] = i
+        teams[i].Members = []schemas.User{}
+    }
+    for _, u := range users {
+        if i, ok := index[u.TeamName]; ok {
+            teams[i].Members = append(teams[i].Members, u)
+        }
+    }
+    return teams, nil
+}
+
+func (r *teamRepository) ApplyRoster(ctx context.Context, diff *schemas.RosterDiff) error {
+    ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+    defer cancel()
+    return inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+        for _, name := range diff.TeamsCreated {
+            _, err := tx.ExecContext(ctx, "INSERT INTO teams (team_name) VALUES ($1) ON CONFLICT (team_name) DO NOTHING", name)
+            if err != nil {
+                return err
+            }
+        }
+
+        for _, settings := range diff.SettingsChanged {
+            _, err := tx.ExecContext(ctx, "UPDATE teams SET reviewers_count = $1 WHERE team_name = $2", settings.ReviewersCount, settings.TeamName)
+            if err != nil {
+                return err
+            }
+        }
+
+        for _, user := range diff.ChangedUsers() {
+            _, err := tx.ExecContext(ctx, "INSERT INTO users (user_id, username, team_name, is_active) VALUES ($1, $2, NULLIF($3, ''), $4) ON CONFLICT (user_id) DO UPDATE SET username = EXCLUDED.username, team_name = EXCLUDED.team_name, is_active = EXCLUDED.is_active",
+                user.ID, user.Username, user.TeamName, user.IsActive)
+            if err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+	"time"
+)
+
+// QueryTimeouts ограничивает время операций репозиториев поверх
+// дедлайна запроса клиента; 0 — без собственного ограничения
+type QueryTimeouts struct {
+	Read  time.Duration // чтение одной сущности или пачки по ID
+	Write time.Duration // изменение вместе с его транзакцией
+	List  time.Duration // полные списки и статистика
+}
+
+// withTimeout возвращает контекст операции; cancel нужно вызвать по её завершении
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type tokenRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewTokenRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.TokenRepository {
+	return &tokenRepository{db: db, timeouts: timeouts}
+}
+
+func (r *tokenRepository) CreateRefreshToken(ctx context.Context, token *schemas.RefreshToken) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "INSERT INTO refresh_tokens (token_hash, login, family_id, expires_at) VALUES ($1, $2, $3, $4)",
+		token.TokenHash, token.Login, token.FamilyID, token.ExpiresAt)
+	return err
+}
+
+func (r *tokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*schemas.RefreshToken, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var token schemas.RefreshToken
+	err := r.db.GetContext(ctx, &token, "SELECT token_hash, login, family_id, expires_at, revoked_at, created_at FROM refresh_tokens WHERE token_hash = $1", tokenHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *tokenRepository) RevokeRefreshToken(ctx context.Context, tokenHash string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = NOW() WHERE token_hash = $1 AND revoked_at IS NULL", tokenHash)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *tokenRepository) RevokeRefreshFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL", familyID)
+	return err
+}
+
+func (r *tokenRepository) RevokeRefreshTokensByLogin(ctx context.Context, login string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = NOW() WHERE login = $1 AND revoked_at IS NULL", login)
+	return err
+}
+
+func (r *tokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Истёкшие токены и так не пройдут проверку — список держим компактным
+	if _, err = tx.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expires_at < NOW()"); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING", jti, expiresAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *tokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var count int
+	err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM revoked_tokens WHERE jti = $1", jti)
+	return count > 0, err
+}
+
+func (r *tokenRepository) SetCutoff(ctx context.Context, login string, issuedBefore time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "INSERT INTO token_cutoffs (login, issued_before) VALUES ($1, $2) ON CONFLICT (login) DO UPDATE SET issued_before = EXCLUDED.issued_before",
+		login, issuedBefore)
+	return err
+}
+
+func (r *tokenRepository) GetCutoff(ctx context.Context, login string) (*time.Time, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var cutoff time.Time
+	err := r.db.GetContext(ctx, &cutoff, "SELECT issued_before FROM token_cutoffs WHERE login = $1", login)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cutoff, nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"ReviewAssigner/internal/domain/interfaces"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// dbtx — *sqlx.DB или *sqlx.Tx: репозиторий одинаково работает сам по себе и внутри единицы работы
+type dbtx interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// inTx выполняет fn в транзакции единицы работы, если она открыта, иначе в собственной
+func inTx(ctx context.Context, db dbtx, fn func(tx *sqlx.Tx) error) error {
+	if tx, ok := db.(*sqlx.Tx); ok {
+		return fn(tx)
+	}
+	tx, err := db.(*sqlx.DB).BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type txManager struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+// Вся единица работы ограничена timeouts.Write, каждый запрос внутри — своим таймаутом
+func NewTxManager(db *sqlx.DB, timeouts QueryTimeouts) interfaces.TxManager {
+	return &txManager{db: db, timeouts: timeouts}
+}
+
+func (m *txManager) Do(ctx context.Context, fn func(repos interfaces.TxRepositories) error) error {
+	ctx, cancel := withTimeout(ctx, m.timeouts.Write)
+	defer cancel()
+
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	repos := interfaces.TxRepositories{
+		Users:        &userRepository{db: tx, timeouts: m.timeouts},
+		Teams:        &teamRepository{db: tx, timeouts: m.timeouts},
+		PullRequests: &pullRequestRepository{db: tx, timeouts: m.timeouts},
+	}
+	if err := fn(repos); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -1,40 +1,87 @@
   package postgres
 
   import (
+      "context"
       "database/sql"
       "ReviewAssigner/internal/domain/schemas"
       "ReviewAssigner/internal/domain/interfaces"
 
       "github.com/jmoiron/sqlx"
+      "github.com/lib/pq"
   )
 
   type userRepository struct {
-      db *sqlx.DB
+      db       dbtx
+      timeouts QueryTimeouts
   }
 
-  func NewUserRepository(db *sqlx.DB) interfaces.UserRepository {
-      return &userRepository{db: db}
+  func NewUserRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.UserRepository {
+      return &userRepository{db: db, timeouts: timeouts}
   }
 
-  func (r *userRepository) GetByID(userID string) (*schemas.User, error) {
+  func (r *userRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
+      ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+      defer cancel()
       var user schemas.User
-      err := r.db.Get(&user, "SELECT user_id, username, team_name, is_active FROM users WHERE user_id = $1", userID)
+      err := r.db.GetContext(ctx, &user, "SELECT user_id, username, team_name, is_active FROM users WHERE user_id = $1", userID)
       if err == sql.ErrNoRows {
           return nil, nil
       }
       return &user, err
   }
 
-  func (r *userRepository) UpdateIsActive(userID string, isActive bool) (*schemas.User, error) {
-      _, err := r.db.Exec("UPDATE users SET is_active = $1 WHERE user_id = $2", isActive, userID)
+  func (r *userRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+      ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+      defer cancel()
+      var users []schemas.User
+      err := r.db.SelectContext(ctx, &users, "SELECT user_id, username, COALESCE(team_name, '') AS team_name, is_active FROM users WHERE user_id = ANY($1)", pq.Array(userIDs))
+      return users, err
+  }
+
+  func (r *userRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+      writeCtx, cancel := withTimeout(ctx, r.timeouts.Write)
+      defer cancel()
+      err := inTx(writeCtx, r.db, func(tx *sqlx.Tx) error {
+          res, err := tx.ExecContext(writeCtx, "UPDATE users SET is_active = $1 WHERE user_id = $2", isActive, userID)
+          if err != nil {
+              return err
+          }
+          // Событие о несуществующем пользователе не пишется
+          if n, err := res.RowsAffected(); err != nil {
+              return err
+          } else if n > 0 {
+              return insertEvents(writeCtx, tx, events)
+          }
+          return nil
+      })
       if err != nil {
           return nil, err
       }
-      return r.GetByID(userID)
+      return r.GetByID(ctx, userID)
+  }
+
+  func (r *userRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+      ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+      defer cancel()
+      var users []schemas.User
+      err := r.db.SelectContext(ctx, &users, "SELECT user_id, username, team_name, is_active FROM users WHERE team_name = $1 AND is_active = true AND user_id != $2", teamName, excludeUserID)
+      return users, err
+  }
+
+  // GetActiveByTeamForShare — GetActiveByTeam, блокирующий кандидатов от изменения до конца единицы работы:
+  // деактивация, начатая параллельно, дождётся назначения или исключит пользователя из выборки
+  func (r *userRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+      ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+      defer cancel()
+      var users []schemas.User
+      err := r.db.SelectContext(ctx, &users, "SELECT user_id, username, team_name, is_active FROM users WHERE team_name = $1 AND is_active = true AND user_id != $2 FOR SHARE", teamName, excludeUserID)
+      return users, err
   }
 
-  func (r *userRepository) GetActiveByTeam(teamName string, excludeUserID string) ([]schemas.User, error) {
+  func (r *userRepository) List(ctx context.Context) ([]schemas.User, error) {
+      ctx, cancel := withTimeout(ctx, r.timeouts.List)
+      defer cancel()
       var users []schemas.User
-      err := r.db.Select(&users, "SELECT user_id, username, team_name, is_active FROM users WHERE team_name = $1 AND is_active = true AND user_id != $2", teamName, excludeUserID)
+      err := r.db.SelectContext(ctx, &users, "SELECT user_id, username, COALESCE(team_name, '') AS team_name, is_active FROM users ORDER BY user_id")
       return users, err
   }
@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	COALESCE(last_error, '') AS last_error, COALESCE(response_status, 0) AS response_status, created_at, delivered_at`
+
+// webhookSubscriptionRow — типы событий хранятся строкой через запятую
+type webhookSubscriptionRow struct {
+	schemas.WebhookSubscription
+	EventTypesRaw string `db:"event_types"`
+}
+
+func (r webhookSubscriptionRow) toSchema() schemas.WebhookSubscription {
+	sub := r.WebhookSubscription
+	sub.EventTypes = []string{}
+	if r.EventTypesRaw != "" {
+		sub.EventTypes = strings.Split(r.EventTypesRaw, ",")
+	}
+	return sub
+}
+
+type webhookSubscriptionRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewWebhookSubscriptionRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{db: db, timeouts: timeouts}
+}
+
+func (r *webhookSubscriptionRepository) Create(ctx context.Context, sub *schemas.WebhookSubscription) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, "INSERT INTO webhook_subscriptions (id, url, secret, event_types, created_by) VALUES ($1, $2, $3, $4, $5)",
+		sub.ID, sub.URL, sub.Secret, strings.Join(sub.EventTypes, ","), sub.CreatedBy)
+	return err
+}
+
+func (r *webhookSubscriptionRepository) GetByID(ctx context.Context, id string) (*schemas.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+	var row webhookSubscriptionRow
+	err := r.db.GetContext(ctx, &row, "SELECT id, url, secret, event_types, created_by, created_at FROM webhook_subscriptions WHERE id = $1", id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sub := row.toSchema()
+	return &sub, nil
+}
+
+func (r *webhookSubscriptionRepository) List(ctx context.Context) ([]schemas.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	var rows []webhookSubscriptionRow
+	if err := r.db.SelectContext(ctx, &rows, "SELECT id, url, secret, event_types, created_by, created_at FROM webhook_subscriptions ORDER BY created_at"); err != nil {
+		return nil, err
+	}
+	subs := make([]schemas.WebhookSubscription, 0, len(rows))
+	for _, row := range rows {
+		subs = append(subs, row.toSchema())
+	}
+	return subs, nil
+}
+
+func (r *webhookSubscriptionRepository) Delete(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+type webhookDeliveryRepository struct {
+	db       *sqlx.DB
+	timeouts QueryTimeouts
+}
+
+func NewWebhookDeliveryRepository(db *sqlx.DB, timeouts QueryTimeouts) interfaces.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db, timeouts: timeouts}
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, deliveries []schemas.WebhookDelivery) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, d := range deliveries {
+		_, err = tx.ExecContext(ctx, `INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload, status, next_attempt_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+			d.ID, d.SubscriptionID, d.EventID, d.EventType, string(d.Payload), d.Status, d.NextAttemptAt)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]schemas.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED: параллельные воркеры разбирают разные доставки
+	var deliveries []schemas.WebhookDelivery
+	err = tx.SelectContext(ctx, &deliveries, "SELECT "+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED`,
+		schemas.WebhookDeliveryPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deliveries {
+		if _, err := tx.ExecContext(ctx, "UPDATE webhook_deliveries SET next_attempt_at = $1 WHERE id = $2", now.Add(lease), d.ID); err != nil {
+			return nil, err
+		}
+	}
+	return deliveries, tx.Commit()
+}
+
+func (r *webhookDeliveryRepository) Update(ctx context.Context, d *schemas.WebhookDelivery) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	_, err := r.db.ExecContext(ctx, `UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3, last_error = NULLIF($4, ''),
+		response_status = NULLIF($5, 0), delivered_at = $6 WHERE id = $7`,
+		d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.ResponseStatus, d.DeliveredAt, d.ID)
+	return err
+}
+
+func (r *webhookDeliveryRepository) List(ctx context.Context, status string, limit int) ([]schemas.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.List)
+	defer cancel()
+	deliveries := []schemas.WebhookDelivery{}
+	err := r.db.SelectContext(ctx, &deliveries, "SELECT "+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE $1 = '' OR status = $1 ORDER BY created_at DESC LIMIT $2`, status, limit)
+	return deliveries, err
+}
+
+func (r *webhookDeliveryRepository) Requeue(ctx context.Context, id string, at time.Time) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+	res, err := r.db.ExecContext(ctx, "UPDATE webhook_deliveries SET status = $1, next_attempt_at = $2 WHERE id = $3",
+		schemas.WebhookDeliveryPending, at, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,129 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+)
+
+// KeyPrefix отличает API-ключи от JWT в заголовке Authorization
+const KeyPrefix = "ra_"
+
+// lastUsedResolution — last_used_at обновляется не чаще, чем раз в эту единицу времени
+const lastUsedResolution = time.Minute
+
+type Usecase struct {
+	repo interfaces.APIKeyRepository
+}
+
+func NewUsecase(repo interfaces.APIKeyRepository) *Usecase {
+	return &Usecase{repo: repo}
+}
+
+// Create выпускает ключ; открытое значение возвращается только здесь
+func (u *Usecase) Create(ctx context.Context, name string, scopes []string, expiresAt *time.Time, createdBy string) (*schemas.APIKey, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", errors.ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !isKnownScope(scope) {
+			return nil, "", errors.ErrInvalidScope
+		}
+	}
+
+	id := randomID()
+	raw := KeyPrefix + id + "_" + randomString(24)
+	key := &schemas.APIKey{
+		ID:        id,
+		Name:      name,
+		KeyHash:   hashKey(raw),
+		Scopes:    scopes,
+		CreatedBy: createdBy,
+		ExpiresAt: expiresAt,
+	}
+	if err := u.repo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, raw, nil
+}
+
+func (u *Usecase) List(ctx context.Context) ([]schemas.APIKey, error) {
+	return u.repo.List(ctx)
+}
+
+func (u *Usecase) Revoke(ctx context.Context, id string) error {
+	revoked, err := u.repo.Revoke(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// Authenticate проверяет открытое значение ключа и отмечает время использования
+func (u *Usecase) Authenticate(ctx context.Context, raw string) (*schemas.APIKey, error) {
+	if !strings.HasPrefix(raw, KeyPrefix) {
+		return nil, errors.ErrInvalidAPIKey
+	}
+	key, err := u.repo.GetByHash(ctx, hashKey(raw))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if key == nil || key.RevokedAt != nil || (key.ExpiresAt != nil && key.ExpiresAt.Before(now)) {
+		return nil, errors.ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := u.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			return nil, err
+		}
+		key.LastUsedAt = &now
+	}
+	return key, nil
+}
+
+// IsAPIKey — похоже ли значение на API-ключ (а не на JWT)
+func IsAPIKey(raw string) bool {
+	return strings.HasPrefix(raw, KeyPrefix)
+}
+
+func isKnownScope(scope string) bool {
+	for _, s := range schemas.KnownScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func randomID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func hashKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package apikey
+
+import (
+	"context"
+	"testing"
+	"time"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock для APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *schemas.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*schemas.APIKey, error) {
+	args := m.Called(keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]schemas.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func TestUsecase_Create_Success(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	usecase := NewUsecase(mockRepo)
+
+	mockRepo.On("Create", mock.AnythingOfType("*schemas.APIKey")).Return(nil)
+
+	key, raw, err := usecase.Create(context.Background(), "ci", []string{schemas.ScopePRCreate}, nil, "admin")
+	assert.NoError(t, err)
+	assert.True(t, IsAPIKey(raw))
+	assert.Equal(t, hashKey(raw), key.KeyHash)
+	assert.NotContains(t, key.KeyHash, raw)
+}
+
+func TestUsecase_Create_UnknownScope(t *testing.T) {
+	usecase := NewUsecase(new(MockAPIKeyRepository))
+
+	_, _, err := usecase.Create(context.Background(), "ci", []string{"team:delete"}, nil, "admin")
+	assert.Equal(t, pkgerrors.ErrInvalidScope, err)
+}
+
+func TestUsecase_Authenticate_TouchesLastUsed(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	usecase := NewUsecase(mockRepo)
+
+	raw := KeyPrefix + "abc_secret"
+	key := &schemas.APIKey{ID: "abc", Scopes: []string{schemas.ScopeStatsRead}}
+	mockRepo.On("GetByHash", hashKey(raw)).Return(key, nil)
+	mockRepo.On("TouchLastUsed", "abc", mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := usecase.Authenticate(context.Background(), raw)
+	assert.NoError(t, err)
+	assert.True(t, result.HasScope(schemas.ScopeStatsRead))
+	assert.NotNil(t, result.LastUsedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUsecase_Authenticate_RecentlyUsed(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	usecase := NewUsecase(mockRepo)
+
+	raw := KeyPrefix + "abc_secret"
+	lastUsed := time.Now().Add(-time.Second)
+	mockRepo.On("GetByHash", hashKey(raw)).Return(&schemas.APIKey{ID: "abc", LastUsedAt: &lastUsed}, nil)
+
+	_, err := usecase.Authenticate(context.Background(), raw)
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything)
+}
+
+func TestUsecase_Authenticate_Expired(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	usecase := NewUsecase(mockRepo)
+
+	raw := KeyPrefix + "abc_secret"
+	expired := time.Now().Add(-time.Hour)
+	mockRepo.On("GetByHash", hashKey(raw)).Return(&schemas.APIKey{ID: "abc", ExpiresAt: &expired}, nil)
+
+	_, err := usecase.Authenticate(context.Background(), raw)
+	assert.Equal(t, pkgerrors.ErrInvalidAPIKey, err)
+}
+
+func TestUsecase_Authenticate_Revoked(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	usecase := NewUsecase(mockRepo)
+
+	raw := KeyPrefix + "abc_secret"
+	revoked := time.Now()
+	mockRepo.On("GetByHash", hashKey(raw)).Return(&schemas.APIKey{ID: "abc", RevokedAt: &revoked}, nil)
+
+	_, err := usecase.Authenticate(context.Background(), raw)
+	assert.Equal(t, pkgerrors.ErrInvalidAPIKey, err)
+}
+
+func TestUsecase_Revoke_NotFound(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepository)
+	usecase := NewUsecase(mockRepo)
+
+	mockRepo.On("Revoke", "missing").Return(false, nil)
+
+	assert.Equal(t, pkgerrors.ErrNotFound, usecase.Revoke(context.Background(), "missing"))
+}
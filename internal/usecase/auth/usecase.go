@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/password"
+)
+
+// LockoutPolicy — после MaxAttempts неудачных попыток подряд учётка блокируется на Duration
+type LockoutPolicy struct {
+	MaxAttempts int
+	Duration    time.Duration
+}
+
+type Usecase struct {
+	accountRepo interfaces.AccountRepository
+	userRepo    interfaces.UserRepository
+	lockout     LockoutPolicy
+}
+
+func NewUsecase(accountRepo interfaces.AccountRepository, userRepo interfaces.UserRepository, lockout LockoutPolicy) *Usecase {
+	return &Usecase{accountRepo: accountRepo, userRepo: userRepo, lockout: lockout}
+}
+
+// Login проверяет пароль и возвращает учётку. Для несуществующего логина и неверного пароля
+// возвращается одна и та же ошибка ErrInvalidCredentials.
+func (u *Usecase) Login(ctx context.Context, login, plain string) (*schemas.Account, error) {
+	account, err := u.accountRepo.GetByLogin(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		password.VerifyDummy(plain)
+		return nil, errors.ErrInvalidCredentials
+	}
+	if account.LockedUntil != nil && account.LockedUntil.After(time.Now()) {
+		// Отказ занимает столько же времени, сколько проверка пароля: по нему не видно блокировку
+		password.VerifyDummy(plain)
+		return nil, errors.ErrAccountLocked
+	}
+
+	if !password.Verify(account.PasswordHash, plain) {
+		attempts, err := u.accountRepo.IncrementFailedAttempts(ctx, login)
+		if err != nil {
+			return nil, err
+		}
+		if u.lockout.MaxAttempts > 0 && attempts >= u.lockout.MaxAttempts {
+			if err := u.accountRepo.Lock(ctx, login, time.Now().Add(u.lockout.Duration)); err != nil {
+				return nil, err
+			}
+			return nil, errors.ErrAccountLocked
+		}
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	if account.FailedAttempts > 0 || account.LockedUntil != nil {
+		if err := u.accountRepo.ResetFailedAttempts(ctx, login); err != nil {
+			return nil, err
+		}
+	}
+	return account, nil
+}
+
+// CreateAccount создаёт учётку; userID, если задан, должен указывать на существующего пользователя
+func (u *Usecase) CreateAccount(ctx context.Context, login, plain, role, userID string) (*schemas.Account, error) {
+	if !schemas.IsAccountRole(role) {
+		return nil, errors.ErrInvalidRole
+	}
+	if err := validatePassword(plain); err != nil {
+		return nil, err
+	}
+	existing, err := u.accountRepo.GetByLogin(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.ErrAccountExists
+	}
+	if userID != "" {
+		user, err := u.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, errors.ErrNotFound
+		}
+	}
+
+	hash, err := password.Hash(plain)
+	if err != nil {
+		return nil, err
+	}
+	account := &schemas.Account{Login: login, UserID: userID, PasswordHash: hash, Role: role}
+	if err := u.accountRepo.Create(ctx, account); err != nil {
+		return nil, err
+	}
+	return u.accountRepo.GetByLogin(ctx, login)
+}
+
+// SetPassword — сброс пароля администратором (снимает блокировку)
+func (u *Usecase) SetPassword(ctx context.Context, login, plain string) error {
+	if err := validatePassword(plain); err != nil {
+		return err
+	}
+	account, err := u.accountRepo.GetByLogin(ctx, login)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return errors.ErrNotFound
+	}
+	hash, err := password.Hash(plain)
+	if err != nil {
+		return err
+	}
+	return u.accountRepo.UpdatePassword(ctx, login, hash)
+}
+
+// validatePassword проверяет длину до хеширования: bcrypt не принимает пароли длиннее MaxLength байт
+func validatePassword(plain string) error {
+	if len(plain) < password.MinLength {
+		return errors.ErrWeakPassword
+	}
+	if len(plain) > password.MaxLength {
+		return errors.ErrWeakPassword.WithMessage("password must be at most 72 bytes")
+	}
+	return nil
+}
+
+// ChangePassword — смена собственного пароля с проверкой старого
+func (u *Usecase) ChangePassword(ctx context.Context, login, oldPlain, newPlain string) error {
+	if _, err := u.Login(ctx, login, oldPlain); err != nil {
+		return err
+	}
+	return u.SetPassword(ctx, login, newPlain)
+}
+
+// EnsureAdmin создаёт bootstrap-админа при первом запуске, если учётки с таким логином ещё нет
+func (u *Usecase) EnsureAdmin(ctx context.Context, login, plain string) error {
+	existing, err := u.accountRepo.GetByLogin(ctx, login)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+	_, err = u.CreateAccount(ctx, login, plain, schemas.RoleAdmin, "")
+	return err
+}
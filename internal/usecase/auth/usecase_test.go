@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/password"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock для AccountRepository
+type MockAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountRepository) Create(ctx context.Context, account *schemas.Account) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetByLogin(ctx context.Context, login string) (*schemas.Account, error) {
+	args := m.Called(login)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) UpdatePassword(ctx context.Context, login string, passwordHash string) error {
+	args := m.Called(login, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) IncrementFailedAttempts(ctx context.Context, login string) (int, error) {
+	args := m.Called(login)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAccountRepository) Lock(ctx context.Context, login string, until time.Time) error {
+	args := m.Called(login, until)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) ResetFailedAttempts(ctx context.Context, login string) error {
+	args := m.Called(login)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetByUserID(ctx context.Context, userID string) (*schemas.Account, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) UpdateRole(ctx context.Context, login string, role string) error {
+	args := m.Called(login, role)
+	return args.Error(0)
+}
+
+// Mock для UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	args := m.Called(userID, isActive, events)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context) ([]schemas.User, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+var policy = LockoutPolicy{MaxAttempts: 3, Duration: time.Minute}
+
+func accountWithPassword(t *testing.T, plain string) *schemas.Account {
+	hash, err := password.Hash(plain)
+	assert.NoError(t, err)
+	return &schemas.Account{Login: "alice", UserID: "u1", PasswordHash: hash, Role: schemas.RoleMember}
+}
+
+func TestUsecase_Login_Success(t *testing.T) {
+	mockAccountRepo := new(MockAccountRepository)
+	usecase := NewUsecase(mockAccountRepo, new(MockUserRepository), policy)
+
+	mockAccountRepo.On("GetByLogin", "alice").Return(accountWithPassword(t, "secret-pass"), nil)
+
+	account, err := usecase.Login(context.Background(), "alice", "secret-pass")
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", account.Subject())
+	mockAccountRepo.AssertNotCalled(t, "ResetFailedAttempts", mock.Anything)
+}
+
+func TestUsecase_Login_UnknownLogin(t *testing.T) {
+	mockAccountRepo := new(MockAccountRepository)
+	usecase := NewUsecase(mockAccountRepo, new(MockUserRepository), policy)
+
+	mockAccountRepo.On("GetByLogin", "bob").Return(nil, nil)
+
+	_, err := usecase.Login(context.Background(), "bob", "whatever")
+	assert.Equal(t, pkgerrors.ErrInvalidCredentials, err)
+}
+
+func TestUsecase_Login_WrongPassword(t *testing.T) {
+	mockAccountRepo := new(MockAccountRepository)
+	usecase := NewUsecase(mockAccountRepo, new(MockUserRepository), policy)
+
+	mockAccountRepo.On("GetByLogin", "alice").Return(accountWithPassword(t, "secret-pass"), nil)
+	mockAccountRepo.On("IncrementFailedAttempts", "alice").Return(1, nil)
+
+	_, err := usecase.Login(context.Background(), "alice", "wrong-pass")
+	assert.Equal(t, pkgerrors.ErrInvalidCredentials, err)
+	mockAccountRepo.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything)
+}
+
+func TestUsecase_Login_LocksAfterMaxAttempts(t *testing.T) {
+	mockAccountRepo := new(MockAccountRepository)
+	usecase := NewUsecase(mockAccountRepo, new(MockUserRepository), policy)
+
+	mockAccountRepo.On("GetByLogin", "alice").Return(accountWithPassword(t, "secret-pass"), nil)
+	mockAccountRepo.On("IncrementFailedAttempts", "alice").Return(3, nil)
+	mockAccountRepo.On("Lock", "alice", mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, err := usecase.Login(context.Background(), "alice", "wrong-pass")
+	assert.Equal(t, pkgerrors.ErrAccountLocked, err)
+	mockAccountRepo.AssertExpectations(t)
+}
+
+func TestUsecase_Login_Locked(t *testing.T) {
+	mockAccountRepo := new(MockAccountRepository)
+	usecase := NewUsecase(mockAccountRepo, new(MockUserRepository), policy)
+
+	account := accountWithPassword(t, "secret-pass")
+	until := time.Now().Add(time.Minute)
+	account.LockedUntil = &until
+	mockAccountRepo.On("GetByLogin", "alice").Return(account, nil)
+
+	// Даже верный пароль не проходит, пока учётка заблокирована
+	_, err := usecase.Login(context.Background(), "alice", "secret-pass")
+	assert.Equal(t, pkgerrors.ErrAccountLocked, err)
+}
+
+func TestUsecase_CreateAccount_UnknownUser(t *testing.T) {
+	mockAccountRepo := new(MockAccountRepository)
+	mockUserRepo := new(MockUserRepository)
+	usecase := NewUsecase(mockAccountRepo, mockUserRepo, policy)
+
+	mockAccountRepo.On("GetByLogin", "alice").Return(nil, nil)
+	mockUserRepo.On("GetByID", "u404").Return(nil, nil)
+
+	_, err := usecase.CreateAccount(context.Background(), "alice", "secret-pass", schemas.RoleMember, "u404")
+	assert.Equal(t, pkgerrors.ErrNotFound, err)
+	mockAccountRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestUsecase_CreateAccount_WeakPassword(t *testing.T) {
+	usecase := NewUsecase(new(MockAccountRepository), new(MockUserRepository), policy)
+
+	_, err := usecase.CreateAccount(context.Background(), "alice", "short", schemas.RoleMember, "")
+	assert.Equal(t, pkgerrors.ErrWeakPassword, err)
+}
+
+func TestUsecase_SetPassword_TooLong(t *testing.T) {
+	mockAccountRepo := new(MockAccountRepository)
+	usecase := NewUsecase(mockAccountRepo, new(MockUserRepository), policy)
+
+	err := usecase.SetPassword(context.Background(), "alice", strings.Repeat("p", 73))
+	assert.ErrorIs(t, err, pkgerrors.ErrWeakPassword)
+	assert.Equal(t, 400, pkgerrors.From(err).Status)
+	mockAccountRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
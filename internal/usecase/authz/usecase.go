@@ -0,0 +1,224 @@
+package authz
+
+import (
+	"context"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+)
+
+// rolePermissions — что разрешает роль без учёта границ команды. admin разрешено всё.
+// Для member права на PR ограничены собственными PR и ревью, для team_lead — своей командой.
+var rolePermissions = map[string][]string{
+	schemas.RoleTeamLead: {
+		schemas.PermTeamRead, schemas.PermTeamManage, schemas.PermUsersRead, schemas.PermUsersSetActive,
+		schemas.PermPRCreate, schemas.PermPRMerge, schemas.PermPRReassign, schemas.PermStatsRead,
+	},
+	schemas.RoleMember: {
+		schemas.PermTeamRead, schemas.PermUsersRead,
+		schemas.PermPRCreate, schemas.PermPRMerge, schemas.PermPRReassign, schemas.PermStatsRead,
+	},
+	schemas.RoleReadOnly: {
+		schemas.PermTeamRead, schemas.PermUsersRead, schemas.PermStatsRead,
+	},
+}
+
+type Usecase struct {
+	bindingRepo interfaces.RoleBindingRepository
+	accountRepo interfaces.AccountRepository
+	userRepo    interfaces.UserRepository
+	prRepo      interfaces.PullRequestRepository
+	teamRepo    interfaces.TeamRepository
+}
+
+func NewUsecase(bindingRepo interfaces.RoleBindingRepository, accountRepo interfaces.AccountRepository, userRepo interfaces.UserRepository,
+	prRepo interfaces.PullRequestRepository, teamRepo interfaces.TeamRepository) *Usecase {
+	return &Usecase{bindingRepo: bindingRepo, accountRepo: accountRepo, userRepo: userRepo, prRepo: prRepo, teamRepo: teamRepo}
+}
+
+// Allowed — грубая проверка: может ли principal выполнять действие хоть над каким-то объектом
+func (u *Usecase) Allowed(ctx context.Context, p *schemas.Principal, perm string) (bool, error) {
+	if p.APIKey != nil {
+		return p.APIKey.HasScope(perm), nil
+	}
+	if p.Role == schemas.RoleAdmin || roleGrants(p.Role, perm) {
+		return true, nil
+	}
+	bindings, err := u.bindingRepo.ListByLogin(ctx, p.Login)
+	if err != nil {
+		return false, err
+	}
+	for _, b := range bindings {
+		if roleGrants(b.Role, perm) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Authorize проверяет действие над конкретным объектом с учётом границ команды
+func (u *Usecase) Authorize(ctx context.Context, p *schemas.Principal, perm string, res schemas.AuthzResource) error {
+	if p.APIKey != nil {
+		if p.APIKey.HasScope(perm) {
+			return nil
+		}
+		return errors.ErrForbidden
+	}
+	if p.Role == schemas.RoleAdmin {
+		return nil
+	}
+
+	switch perm {
+	case schemas.PermTeamRead, schemas.PermUsersRead, schemas.PermStatsRead:
+		return u.allow(u.Allowed(ctx, p, perm))
+
+	case schemas.PermTeamManage:
+		if err := u.allow(u.leads(ctx, p, res.TeamName)); err != nil {
+			return err
+		}
+		// Перевод пользователя из другой команды требует прав и на исходную команду
+		if res.TargetUserID == "" {
+			return nil
+		}
+		user, err := u.userRepo.GetByID(ctx, res.TargetUserID)
+		if err != nil {
+			return err
+		}
+		if user == nil || user.TeamName == "" || user.TeamName == res.TeamName {
+			return nil
+		}
+		return u.allow(u.leads(ctx, p, user.TeamName))
+
+	case schemas.PermUsersSetActive:
+		team, err := u.userTeam(ctx, res.TargetUserID)
+		if err != nil {
+			return err
+		}
+		return u.allow(u.leads(ctx, p, team))
+
+	case schemas.PermPRCreate:
+		return u.authorizeAuthor(ctx, p, perm, res.AuthorID)
+
+	case schemas.PermPRMerge:
+		pr, err := u.prRepo.GetByID(ctx, res.PRID)
+		if err != nil {
+			return err
+		}
+		if pr == nil {
+			return errors.ErrNotFound
+		}
+		return u.authorizeAuthor(ctx, p, perm, pr.AuthorID)
+
+	case schemas.PermPRReassign:
+		// Участник может отказаться от своего ревью, лид — переназначить ревьюверов своей команды
+		if res.TargetUserID == p.UserID && roleGrants(p.Role, perm) {
+			return nil
+		}
+		team, err := u.userTeam(ctx, res.TargetUserID)
+		if err != nil {
+			return err
+		}
+		return u.allow(u.leads(ctx, p, team))
+	}
+	return errors.ErrForbidden
+}
+
+// CreateBinding назначает командную роль; пока поддерживается только team_lead
+func (u *Usecase) CreateBinding(ctx context.Context, binding *schemas.RoleBinding) error {
+	if binding.Role != schemas.RoleTeamLead {
+		return errors.ErrInvalidRole
+	}
+	account, err := u.accountRepo.GetByLogin(ctx, binding.Login)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return errors.ErrNotFound
+	}
+	exists, err := u.teamRepo.Exists(ctx, binding.TeamName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.ErrNotFound
+	}
+	return u.bindingRepo.Create(ctx, binding)
+}
+
+func (u *Usecase) DeleteBinding(ctx context.Context, binding *schemas.RoleBinding) error {
+	deleted, err := u.bindingRepo.Delete(ctx, binding)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+func (u *Usecase) ListBindings(ctx context.Context, login string) ([]schemas.RoleBinding, error) {
+	if login != "" {
+		return u.bindingRepo.ListByLogin(ctx, login)
+	}
+	return u.bindingRepo.List(ctx)
+}
+
+// authorizeAuthor — действие над PR автора authorID: свой PR или PR своей команды (для лида)
+func (u *Usecase) authorizeAuthor(ctx context.Context, p *schemas.Principal, perm, authorID string) error {
+	if authorID == p.UserID && roleGrants(p.Role, perm) {
+		return nil
+	}
+	team, err := u.userTeam(ctx, authorID)
+	if err != nil {
+		return err
+	}
+	return u.allow(u.leads(ctx, p, team))
+}
+
+// leads — является ли principal лидом команды teamName
+func (u *Usecase) leads(ctx context.Context, p *schemas.Principal, teamName string) (bool, error) {
+	if teamName == "" {
+		return false, nil
+	}
+	bindings, err := u.bindingRepo.ListByLogin(ctx, p.Login)
+	if err != nil {
+		return false, err
+	}
+	for _, b := range bindings {
+		if b.Role == schemas.RoleTeamLead && b.TeamName == teamName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (u *Usecase) userTeam(ctx context.Context, userID string) (string, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return "", errors.ErrNotFound
+	}
+	return user.TeamName, nil
+}
+
+func (u *Usecase) allow(ok bool, err error) error {
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.ErrForbidden
+	}
+	return nil
+}
+
+func roleGrants(role, perm string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
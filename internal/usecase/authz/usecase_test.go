@@ -0,0 +1,375 @@
+package authz
+
+import (
+	"context"
+	"testing"
+	"time"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock для RoleBindingRepository
+type MockRoleBindingRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleBindingRepository) Create(ctx context.Context, binding *schemas.RoleBinding) error {
+	args := m.Called(binding)
+	return args.Error(0)
+}
+
+func (m *MockRoleBindingRepository) Delete(ctx context.Context, binding *schemas.RoleBinding) (bool, error) {
+	args := m.Called(binding)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRoleBindingRepository) ListByLogin(ctx context.Context, login string) ([]schemas.RoleBinding, error) {
+	args := m.Called(login)
+	return args.Get(0).([]schemas.RoleBinding), args.Error(1)
+}
+
+func (m *MockRoleBindingRepository) List(ctx context.Context) ([]schemas.RoleBinding, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.RoleBinding), args.Error(1)
+}
+
+// Mock для AccountRepository
+type MockAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountRepository) Create(ctx context.Context, account *schemas.Account) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetByLogin(ctx context.Context, login string) (*schemas.Account, error) {
+	args := m.Called(login)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) UpdatePassword(ctx context.Context, login string, passwordHash string) error {
+	args := m.Called(login, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) IncrementFailedAttempts(ctx context.Context, login string) (int, error) {
+	args := m.Called(login)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAccountRepository) Lock(ctx context.Context, login string, until time.Time) error {
+	args := m.Called(login, until)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) ResetFailedAttempts(ctx context.Context, login string) error {
+	args := m.Called(login)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetByUserID(ctx context.Context, userID string) (*schemas.Account, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) UpdateRole(ctx context.Context, login string, role string) error {
+	args := m.Called(login, role)
+	return args.Error(0)
+}
+
+// Mock для UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	args := m.Called(userID, isActive, events)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context) ([]schemas.User, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+// Mock для PullRequestRepository
+type MockPullRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockPullRequestRepository) Create(ctx context.Context, pr *schemas.PullRequest, events ...*schemas.Event) error {
+	args := m.Called(pr, events)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) GetByID(ctx context.Context, id string) (*schemas.PullRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetByIDForUpdate(ctx context.Context, id string) (*schemas.PullRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetByIDs(ctx context.Context, ids []string) ([]schemas.PullRequest, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) UpdateStatus(ctx context.Context, id string, version int, status string, mergedAt *time.Time, events ...*schemas.Event) (*schemas.PullRequest, error) {
+	args := m.Called(id, version, status, mergedAt, events)
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) UpdateReviewers(ctx context.Context, id string, version int, reviewers []string, events ...*schemas.Event) error {
+	args := m.Called(id, version, reviewers, events)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) UpdateSCMSync(ctx context.Context, id string, state schemas.SCMSyncState) error {
+	args := m.Called(id, state)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) GetByReviewerID(ctx context.Context, userID string) ([]schemas.PullRequestShort, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]schemas.PullRequestShort), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetByReviewerIDs(ctx context.Context, userIDs []string) (map[string][]schemas.PullRequestShort, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).(map[string][]schemas.PullRequestShort), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetStats(ctx context.Context) (map[string]int, map[string]int, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(map[string]int), args.Get(1).(map[string]int), args.Error(2)
+}
+
+// Mock для TeamRepository
+type MockTeamRepository struct {
+	mock.Mock
+}
+
+func (m *MockTeamRepository) Create(ctx context.Context, team *schemas.Team) error {
+	args := m.Called(team)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) GetByName(ctx context.Context, name string) (*schemas.Team, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) GetByNames(ctx context.Context, names []string) ([]schemas.Team, error) {
+	args := m.Called(names)
+	return args.Get(0).([]schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) Exists(ctx context.Context, name string) (bool, error) {
+	args := m.Called(name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTeamRepository) List(ctx context.Context) ([]schemas.Team, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) ApplyRoster(ctx context.Context, diff *schemas.RosterDiff) error {
+	args := m.Called(diff)
+	return args.Error(0)
+}
+
+type fixture struct {
+	bindings *MockRoleBindingRepository
+	accounts *MockAccountRepository
+	users    *MockUserRepository
+	prs      *MockPullRequestRepository
+	teams    *MockTeamRepository
+	usecase  *Usecase
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		bindings: new(MockRoleBindingRepository),
+		accounts: new(MockAccountRepository),
+		users:    new(MockUserRepository),
+		prs:      new(MockPullRequestRepository),
+		teams:    new(MockTeamRepository),
+	}
+	f.usecase = NewUsecase(f.bindings, f.accounts, f.users, f.prs, f.teams)
+	return f
+}
+
+var (
+	lead   = &schemas.Principal{Login: "lead", UserID: "u1", Role: schemas.RoleMember}
+	member = &schemas.Principal{Login: "bob", UserID: "u2", Role: schemas.RoleMember}
+	viewer = &schemas.Principal{Login: "eve", UserID: "u3", Role: schemas.RoleReadOnly}
+	leadOf = []schemas.RoleBinding{{Login: "lead", Role: schemas.RoleTeamLead, TeamName: "backend"}}
+)
+
+func TestUsecase_Allowed_Roles(t *testing.T) {
+	f := newFixture()
+	f.bindings.On("ListByLogin", "eve").Return([]schemas.RoleBinding{}, nil)
+	f.bindings.On("ListByLogin", "bob").Return([]schemas.RoleBinding{}, nil)
+	f.bindings.On("ListByLogin", "lead").Return(leadOf, nil)
+
+	ok, _ := f.usecase.Allowed(context.Background(), viewer, schemas.PermStatsRead)
+	assert.True(t, ok)
+	ok, _ = f.usecase.Allowed(context.Background(), viewer, schemas.PermPRCreate)
+	assert.False(t, ok)
+	ok, _ = f.usecase.Allowed(context.Background(), member, schemas.PermTeamManage)
+	assert.False(t, ok)
+	ok, _ = f.usecase.Allowed(context.Background(), lead, schemas.PermTeamManage)
+	assert.True(t, ok)
+	ok, _ = f.usecase.Allowed(context.Background(), lead, schemas.PermTeamCreate)
+	assert.False(t, ok)
+	ok, _ = f.usecase.Allowed(context.Background(), &schemas.Principal{Login: "root", Role: schemas.RoleAdmin}, schemas.PermAdminister)
+	assert.True(t, ok)
+}
+
+func TestUsecase_Allowed_APIKeyUsesScopes(t *testing.T) {
+	f := newFixture()
+	p := &schemas.Principal{APIKey: &schemas.APIKey{Scopes: []string{schemas.ScopePRCreate}}}
+
+	ok, _ := f.usecase.Allowed(context.Background(), p, schemas.PermPRCreate)
+	assert.True(t, ok)
+	ok, _ = f.usecase.Allowed(context.Background(), p, schemas.PermPRMerge)
+	assert.False(t, ok)
+}
+
+func TestUsecase_Authorize_LeadManagesOwnTeamOnly(t *testing.T) {
+	f := newFixture()
+	f.bindings.On("ListByLogin", "lead").Return(leadOf, nil)
+
+	assert.NoError(t, f.usecase.Authorize(context.Background(), lead, schemas.PermTeamManage, schemas.AuthzResource{TeamName: "backend"}))
+	assert.Equal(t, pkgerrors.ErrForbidden, f.usecase.Authorize(context.Background(), lead, schemas.PermTeamManage, schemas.AuthzResource{TeamName: "frontend"}))
+}
+
+func TestUsecase_Authorize_LeadCannotPullUserFromOtherTeam(t *testing.T) {
+	f := newFixture()
+	f.bindings.On("ListByLogin", "lead").Return(leadOf, nil)
+	f.users.On("GetByID", "u9").Return(&schemas.User{ID: "u9", TeamName: "frontend"}, nil)
+
+	err := f.usecase.Authorize(context.Background(), lead, schemas.PermTeamManage, schemas.AuthzResource{TeamName: "backend", TargetUserID: "u9"})
+	assert.Equal(t, pkgerrors.ErrForbidden, err)
+}
+
+func TestUsecase_Authorize_LeadReassignsWithinTeam(t *testing.T) {
+	f := newFixture()
+	f.bindings.On("ListByLogin", "lead").Return(leadOf, nil)
+	f.users.On("GetByID", "u5").Return(&schemas.User{ID: "u5", TeamName: "backend"}, nil)
+	f.users.On("GetByID", "u6").Return(&schemas.User{ID: "u6", TeamName: "frontend"}, nil)
+
+	assert.NoError(t, f.usecase.Authorize(context.Background(), lead, schemas.PermPRReassign, schemas.AuthzResource{PRID: "pr1", TargetUserID: "u5"}))
+	assert.Equal(t, pkgerrors.ErrForbidden, f.usecase.Authorize(context.Background(), lead, schemas.PermPRReassign, schemas.AuthzResource{PRID: "pr1", TargetUserID: "u6"}))
+}
+
+func TestUsecase_Authorize_MemberDeclinesOwnReview(t *testing.T) {
+	f := newFixture()
+	f.bindings.On("ListByLogin", "bob").Return([]schemas.RoleBinding{}, nil)
+	f.users.On("GetByID", "u5").Return(&schemas.User{ID: "u5", TeamName: "backend"}, nil)
+
+	assert.NoError(t, f.usecase.Authorize(context.Background(), member, schemas.PermPRReassign, schemas.AuthzResource{PRID: "pr1", TargetUserID: "u2"}))
+	assert.Equal(t, pkgerrors.ErrForbidden, f.usecase.Authorize(context.Background(), member, schemas.PermPRReassign, schemas.AuthzResource{PRID: "pr1", TargetUserID: "u5"}))
+}
+
+func TestUsecase_Authorize_MergeOwnPR(t *testing.T) {
+	f := newFixture()
+	f.prs.On("GetByID", "pr1").Return(&schemas.PullRequest{ID: "pr1", AuthorID: "u2"}, nil)
+	f.prs.On("GetByID", "pr2").Return(&schemas.PullRequest{ID: "pr2", AuthorID: "u5"}, nil)
+	f.users.On("GetByID", "u5").Return(&schemas.User{ID: "u5", TeamName: "backend"}, nil)
+	f.bindings.On("ListByLogin", "bob").Return([]schemas.RoleBinding{}, nil)
+
+	assert.NoError(t, f.usecase.Authorize(context.Background(), member, schemas.PermPRMerge, schemas.AuthzResource{PRID: "pr1"}))
+	assert.Equal(t, pkgerrors.ErrForbidden, f.usecase.Authorize(context.Background(), member, schemas.PermPRMerge, schemas.AuthzResource{PRID: "pr2"}))
+}
+
+func TestUsecase_Authorize_ReadOnlyCannotWrite(t *testing.T) {
+	f := newFixture()
+	f.bindings.On("ListByLogin", "eve").Return([]schemas.RoleBinding{}, nil)
+	f.users.On("GetByID", "u3").Return(&schemas.User{ID: "u3", TeamName: "backend"}, nil)
+
+	err := f.usecase.Authorize(context.Background(), viewer, schemas.PermPRCreate, schemas.AuthzResource{PRID: "pr1", AuthorID: "u3"})
+	assert.Equal(t, pkgerrors.ErrForbidden, err)
+}
+
+func TestUsecase_CreateBinding_OnlyTeamLead(t *testing.T) {
+	f := newFixture()
+
+	err := f.usecase.CreateBinding(context.Background(), &schemas.RoleBinding{Login: "bob", Role: schemas.RoleAdmin, TeamName: "backend"})
+	assert.Equal(t, pkgerrors.ErrInvalidRole, err)
+}
+
+func TestUsecase_CreateBinding_TeamNotFound(t *testing.T) {
+	f := newFixture()
+	f.accounts.On("GetByLogin", "bob").Return(&schemas.Account{Login: "bob"}, nil)
+	f.teams.On("Exists", "ghost").Return(false, nil)
+
+	err := f.usecase.CreateBinding(context.Background(), &schemas.RoleBinding{Login: "bob", Role: schemas.RoleTeamLead, TeamName: "ghost"})
+	assert.Equal(t, pkgerrors.ErrNotFound, err)
+	f.bindings.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestUsecase_DeleteBinding_NotFound(t *testing.T) {
+	f := newFixture()
+	binding := &schemas.RoleBinding{Login: "bob", Role: schemas.RoleTeamLead, TeamName: "backend"}
+	f.bindings.On("Delete", binding).Return(false, nil)
+
+	assert.Equal(t, pkgerrors.ErrNotFound, f.usecase.DeleteBinding(context.Background(), binding))
+}
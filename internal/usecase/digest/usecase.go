@@ -0,0 +1,289 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/notify"
+)
+
+// Sender рендерит и отправляет сообщения по шаблонам уведомлений (notifier.Usecase)
+type Sender interface {
+	Render(template string, data schemas.NotificationData) (notify.Message, error)
+	NotifyVia(ctx context.Context, userID, channel, template string, data schemas.NotificationData) error
+}
+
+// Usecase собирает ежедневные сводки ожидающих ревью и рассылает их в местное время пользователей
+type Usecase struct {
+	settingRepo interfaces.DigestSettingRepository
+	prefRepo    interfaces.NotificationPreferenceRepository
+	userRepo    interfaces.UserRepository
+	prRepo      interfaces.PullRequestRepository
+	sender      Sender
+	defaults    *schemas.DigestSetting // расписание для активных пользователей без своей настройки; nil — только по подписке
+	now         func() time.Time
+}
+
+func NewUsecase(settingRepo interfaces.DigestSettingRepository, prefRepo interfaces.NotificationPreferenceRepository,
+	userRepo interfaces.UserRepository, prRepo interfaces.PullRequestRepository, sender Sender, defaults *schemas.DigestSetting) *Usecase {
+	return &Usecase{settingRepo: settingRepo, prefRepo: prefRepo, userRepo: userRepo, prRepo: prRepo, sender: sender, defaults: defaults, now: time.Now}
+}
+
+// DefaultSetting проверяет расписание по умолчанию; пустой channel — сводки получают только настроившие их
+func DefaultSetting(channel, sendAt, timezone string) (*schemas.DigestSetting, error) {
+	if channel == "" {
+		return nil, nil
+	}
+	if !slices.Contains(notify.KnownChannels, channel) {
+		return nil, fmt.Errorf("unknown channel %q", channel)
+	}
+	if _, err := parseSendAt(sendAt); err != nil {
+		return nil, err
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, err
+	}
+	return &schemas.DigestSetting{Channel: channel, SendAt: sendAt, Timezone: timezone, Enabled: true}, nil
+}
+
+// SetSetting сохраняет расписание сводки; канал должен быть включён в настройках уведомлений пользователя
+func (u *Usecase) SetSetting(ctx context.Context, setting *schemas.DigestSetting) error {
+	if setting.Timezone == "" {
+		setting.Timezone = "UTC"
+	}
+	if _, err := parseSendAt(setting.SendAt); err != nil {
+		return errors.ErrInvalidDigest
+	}
+	if _, err := time.LoadLocation(setting.Timezone); err != nil {
+		return errors.ErrInvalidDigest
+	}
+
+	user, err := u.userRepo.GetByID(ctx, setting.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.ErrNotFound
+	}
+	prefs, err := u.prefRepo.ListByUser(ctx, setting.UserID)
+	if err != nil {
+		return err
+	}
+	for _, pref := range prefs {
+		if pref.Channel == setting.Channel && pref.Enabled {
+			return u.settingRepo.Upsert(ctx, setting)
+		}
+	}
+	return errors.ErrInvalidDigest
+}
+
+func (u *Usecase) GetSetting(ctx context.Context, userID string) (*schemas.DigestSetting, error) {
+	setting, err := u.settingRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		return nil, errors.ErrNotFound
+	}
+	return setting, nil
+}
+
+func (u *Usecase) DeleteSetting(ctx context.Context, userID string) error {
+	deleted, err := u.settingRepo.Delete(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// Build собирает сводку: открытые PR, где пользователь ревьювер, от дольше всех ждущих
+func (u *Usecase) Build(ctx context.Context, userID string) (*schemas.Digest, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.ErrNotFound
+	}
+	prs, err := u.prRepo.GetByReviewerID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := u.now()
+	digest := &schemas.Digest{User: user, Reviews: []schemas.PendingReview{}, GeneratedAt: now}
+	for _, pr := range prs {
+		if pr.Status != "OPEN" {
+			continue
+		}
+		var waiting time.Duration
+		if pr.CreatedAt != nil {
+			waiting = now.Sub(*pr.CreatedAt)
+		}
+		digest.Reviews = append(digest.Reviews, schemas.PendingReview{
+			PullRequestShort: pr,
+			WaitingSeconds:   int64(waiting / time.Second),
+			Waiting:          schemas.FormatWaiting(waiting),
+		})
+	}
+	sort.SliceStable(digest.Reviews, func(i, j int) bool {
+		return digest.Reviews[i].WaitingSeconds > digest.Reviews[j].WaitingSeconds
+	})
+	return digest, nil
+}
+
+// Preview собирает сводку пользователя и текст сообщения, не отправляя его
+func (u *Usecase) Preview(ctx context.Context, userID string) (*schemas.Digest, notify.Message, error) {
+	digest, err := u.Build(ctx, userID)
+	if err != nil {
+		return nil, notify.Message{}, err
+	}
+	msg, err := u.sender.Render(notify.TemplateDigest, schemas.NotificationData{User: digest.User, Reviews: digest.Reviews})
+	return digest, msg, err
+}
+
+// Run рассылает сводки до отмены ctx, проверяя расписания раз в poll
+func (u *Usecase) Run(ctx context.Context, poll time.Duration) {
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		if _, err := u.SendDue(ctx); err != nil {
+			log.Printf("digest: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue отправляет сводки, чьё местное время наступило сегодня, и возвращает их число.
+// Пустые сводки и неактивные пользователи пропускаются, но день всё равно отмечается.
+// Активные пользователи без своей настройки получают сводку по расписанию по умолчанию, если оно задано.
+func (u *Usecase) SendDue(ctx context.Context) (int, error) {
+	settings, err := u.settingRepo.ListEnabled(ctx)
+	if err != nil {
+		return 0, err
+	}
+	sent, err := u.sendDue(ctx, settings, u.settingRepo.MarkSent)
+	if err != nil || u.defaults == nil {
+		return sent, err
+	}
+
+	defaults, err := u.defaultSettings(ctx)
+	if err != nil {
+		return sent, err
+	}
+	n, err := u.sendDue(ctx, defaults, u.settingRepo.MarkDefaultSent)
+	return sent + n, err
+}
+
+func (u *Usecase) sendDue(ctx context.Context, settings []schemas.DigestSetting, markSent func(ctx context.Context, userID, day string) (bool, error)) (int, error) {
+	sent := 0
+	for _, setting := range settings {
+		day, due := u.due(setting)
+		if !due {
+			continue
+		}
+		// День отмечается до отправки: при нескольких инстансах сводку отправит только один
+		claimed, err := markSent(ctx, setting.UserID, day)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+		ok, err := u.send(ctx, setting)
+		if err != nil {
+			log.Printf("digest for %s: %v", setting.UserID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// defaultSettings — расписание по умолчанию для активных пользователей, у которых нет своей настройки.
+// Выключенная своя настройка тоже считается: так пользователь отказывается от сводок.
+func (u *Usecase) defaultSettings(ctx context.Context) ([]schemas.DigestSetting, error) {
+	ids, err := u.settingRepo.ListConfiguredUserIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	configured := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		configured[id] = true
+	}
+	lastSent, err := u.settingRepo.ListDefaultSent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := u.userRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := []schemas.DigestSetting{}
+	for _, user := range users {
+		if !user.IsActive || configured[user.ID] {
+			continue
+		}
+		setting := *u.defaults
+		setting.UserID = user.ID
+		setting.LastSentOn = lastSent[user.ID]
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// due возвращает местную дату и наступило ли время сводки, которую в этот день ещё не отправляли
+func (u *Usecase) due(setting schemas.DigestSetting) (string, bool) {
+	loc, err := time.LoadLocation(setting.Timezone)
+	if err != nil {
+		log.Printf("digest for %s: %v", setting.UserID, err)
+		return "", false
+	}
+	sendAt, err := parseSendAt(setting.SendAt)
+	if err != nil {
+		log.Printf("digest for %s: %v", setting.UserID, err)
+		return "", false
+	}
+	local := u.now().In(loc)
+	day := local.Format("2006-01-02")
+	minutes := local.Hour()*60 + local.Minute()
+	return day, minutes >= sendAt && setting.LastSentOn < day
+}
+
+func (u *Usecase) send(ctx context.Context, setting schemas.DigestSetting) (bool, error) {
+	digest, err := u.Build(ctx, setting.UserID)
+	if err != nil {
+		return false, err
+	}
+	if !digest.User.IsActive || len(digest.Reviews) == 0 {
+		return false, nil
+	}
+	data := schemas.NotificationData{User: digest.User, Reviews: digest.Reviews}
+	return true, u.sender.NotifyVia(ctx, setting.UserID, setting.Channel, notify.TemplateDigest, data)
+}
+
+// parseSendAt переводит "HH:MM" в минуты от полуночи
+func parseSendAt(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("send_at %q: %w", value, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,403 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock для UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	args := m.Called(userID, isActive, events)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context) ([]schemas.User, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+// Mock для PullRequestRepository
+type MockPullRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockPullRequestRepository) Create(ctx context.Context, pr *schemas.PullRequest, events ...*schemas.Event) error {
+	args := m.Called(pr, events)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) GetByID(ctx context.Context, id string) (*schemas.PullRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetByIDForUpdate(ctx context.Context, id string) (*schemas.PullRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetByIDs(ctx context.Context, ids []string) ([]schemas.PullRequest, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) UpdateStatus(ctx context.Context, id string, version int, status string, mergedAt *time.Time, events ...*schemas.Event) (*schemas.PullRequest, error) {
+	args := m.Called(id, version, status, mergedAt, events)
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) UpdateReviewers(ctx context.Context, id string, version int, reviewers []string, events ...*schemas.Event) error {
+	args := m.Called(id, version, reviewers, events)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) UpdateSCMSync(ctx context.Context, id string, state schemas.SCMSyncState) error {
+	args := m.Called(id, state)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) GetByReviewerID(ctx context.Context, userID string) ([]schemas.PullRequestShort, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]schemas.PullRequestShort), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetByReviewerIDs(ctx context.Context, userIDs []string) (map[string][]schemas.PullRequestShort, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).(map[string][]schemas.PullRequestShort), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetStats(ctx context.Context) (map[string]int, map[string]int, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(map[string]int), args.Get(1).(map[string]int), args.Error(2)
+}
+
+
+// Mock для NotificationPreferenceRepository
+type MockNotificationPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceRepository) Upsert(ctx context.Context, pref *schemas.NotificationPreference) error {
+	args := m.Called(pref)
+	return args.Error(0)
+}
+
+func (m *MockNotificationPreferenceRepository) ListByUser(ctx context.Context, userID string) ([]schemas.NotificationPreference, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]schemas.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) Delete(ctx context.Context, userID, channel string) (bool, error) {
+	args := m.Called(userID, channel)
+	return args.Bool(0), args.Error(1)
+}
+
+// Mock для DigestSettingRepository
+type MockDigestSettingRepository struct {
+	mock.Mock
+}
+
+func (m *MockDigestSettingRepository) Upsert(ctx context.Context, setting *schemas.DigestSetting) error {
+	args := m.Called(setting)
+	return args.Error(0)
+}
+
+func (m *MockDigestSettingRepository) GetByUser(ctx context.Context, userID string) (*schemas.DigestSetting, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.DigestSetting), args.Error(1)
+}
+
+func (m *MockDigestSettingRepository) Delete(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDigestSettingRepository) ListEnabled(ctx context.Context) ([]schemas.DigestSetting, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.DigestSetting), args.Error(1)
+}
+
+func (m *MockDigestSettingRepository) MarkSent(ctx context.Context, userID, day string) (bool, error) {
+	args := m.Called(userID, day)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDigestSettingRepository) ListConfiguredUserIDs(ctx context.Context) ([]string, error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDigestSettingRepository) ListDefaultSent(ctx context.Context) (map[string]string, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+func (m *MockDigestSettingRepository) MarkDefaultSent(ctx context.Context, userID, day string) (bool, error) {
+	args := m.Called(userID, day)
+	return args.Bool(0), args.Error(1)
+}
+
+// Mock для Sender
+type MockSender struct {
+	mock.Mock
+}
+
+func (m *MockSender) Render(template string, data schemas.NotificationData) (notify.Message, error) {
+	args := m.Called(template, data)
+	return args.Get(0).(notify.Message), args.Error(1)
+}
+
+func (m *MockSender) NotifyVia(ctx context.Context, userID, channel, template string, data schemas.NotificationData) error {
+	args := m.Called(userID, channel, template, data)
+	return args.Error(0)
+}
+
+// 2026-03-02 06:30 UTC — 09:30 в Москве
+var now = time.Date(2026, 3, 2, 6, 30, 0, 0, time.UTC)
+
+type testDeps struct {
+	settings *MockDigestSettingRepository
+	prefs    *MockNotificationPreferenceRepository
+	users    *MockUserRepository
+	prs      *MockPullRequestRepository
+	sender   *MockSender
+}
+
+func newTestUsecase() (*Usecase, testDeps) {
+	d := testDeps{
+		settings: new(MockDigestSettingRepository),
+		prefs:    new(MockNotificationPreferenceRepository),
+		users:    new(MockUserRepository),
+		prs:      new(MockPullRequestRepository),
+		sender:   new(MockSender),
+	}
+	u := NewUsecase(d.settings, d.prefs, d.users, d.prs, d.sender, nil)
+	u.now = func() time.Time { return now }
+	return u, d
+}
+
+func ago(d time.Duration) *time.Time {
+	t := now.Add(-d)
+	return &t
+}
+
+func TestBuild_OnlyOpenOldestFirst(t *testing.T) {
+	u, d := newTestUsecase()
+	d.users.On("GetByID", "u2").Return(&schemas.User{ID: "u2", Username: "Alice", IsActive: true}, nil)
+	d.prs.On("GetByReviewerID", "u2").Return([]schemas.PullRequestShort{
+		{ID: "pr1", Status: "OPEN", CreatedAt: ago(3 * time.Hour)},
+		{ID: "pr2", Status: "MERGED", CreatedAt: ago(72 * time.Hour)},
+		{ID: "pr3", Status: "OPEN", CreatedAt: ago(50 * time.Hour)},
+	}, nil)
+
+	digest, err := u.Build(context.Background(), "u2")
+
+	require.NoError(t, err)
+	require.Len(t, digest.Reviews, 2)
+	assert.Equal(t, "pr3", digest.Reviews[0].ID)
+	assert.Equal(t, "2d 2h", digest.Reviews[0].Waiting)
+	assert.Equal(t, int64(3*3600), digest.Reviews[1].WaitingSeconds)
+	assert.Equal(t, "3h 0m", digest.Reviews[1].Waiting)
+}
+
+func TestBuild_UserNotFound(t *testing.T) {
+	u, d := newTestUsecase()
+	d.users.On("GetByID", "ghost").Return(nil, nil)
+
+	_, err := u.Build(context.Background(), "ghost")
+	assert.Equal(t, pkgerrors.ErrNotFound, err)
+}
+
+func TestSetSetting_Validation(t *testing.T) {
+	u, d := newTestUsecase()
+	d.users.On("GetByID", "u2").Return(&schemas.User{ID: "u2"}, nil)
+	d.prefs.On("ListByUser", "u2").Return([]schemas.NotificationPreference{
+		{UserID: "u2", Channel: notify.ChannelSlack, Address: "@alice", Enabled: true},
+	}, nil)
+	d.settings.On("Upsert", mock.AnythingOfType("*schemas.DigestSetting")).Return(nil)
+
+	assert.Equal(t, pkgerrors.ErrInvalidDigest, u.SetSetting(context.Background(), &schemas.DigestSetting{UserID: "u2", Channel: notify.ChannelSlack, SendAt: "25:00"}))
+	assert.Equal(t, pkgerrors.ErrInvalidDigest, u.SetSetting(context.Background(), &schemas.DigestSetting{UserID: "u2", Channel: notify.ChannelSlack, SendAt: "09:00", Timezone: "Mars/Olympus"}))
+	// Канал не настроен в уведомлениях пользователя
+	assert.Equal(t, pkgerrors.ErrInvalidDigest, u.SetSetting(context.Background(), &schemas.DigestSetting{UserID: "u2", Channel: notify.ChannelEmail, SendAt: "09:00"}))
+
+	setting := &schemas.DigestSetting{UserID: "u2", Channel: notify.ChannelSlack, SendAt: "09:00"}
+	require.NoError(t, u.SetSetting(context.Background(), setting))
+	assert.Equal(t, "UTC", setting.Timezone)
+}
+
+func TestSendDue_RespectsLocalTimeAndSendsOncePerDay(t *testing.T) {
+	u, d := newTestUsecase()
+	d.settings.On("ListEnabled").Return([]schemas.DigestSetting{
+		{UserID: "u2", Channel: notify.ChannelSlack, SendAt: "09:00", Timezone: "Europe/Moscow", Enabled: true},                          // 09:30 — пора
+		{UserID: "u3", Channel: notify.ChannelSlack, SendAt: "09:00", Timezone: "America/New_York", Enabled: true},                       // 01:30 — рано
+		{UserID: "u4", Channel: notify.ChannelSlack, SendAt: "09:00", Timezone: "Europe/Moscow", Enabled: true, LastSentOn: "2026-03-02"}, // уже отправлена
+		{UserID: "u5", Channel: notify.ChannelSlack, SendAt: "06:00", Timezone: "UTC", Enabled: true},                                    // отправил другой инстанс
+	}, nil)
+	d.settings.On("MarkSent", "u2", "2026-03-02").Return(true, nil)
+	d.settings.On("MarkSent", "u5", "2026-03-02").Return(false, nil)
+	d.users.On("GetByID", "u2").Return(&schemas.User{ID: "u2", Username: "Alice", IsActive: true}, nil)
+	d.prs.On("GetByReviewerID", "u2").Return([]schemas.PullRequestShort{{ID: "pr1", Status: "OPEN", CreatedAt: ago(time.Hour)}}, nil)
+	d.sender.On("NotifyVia", "u2", notify.ChannelSlack, notify.TemplateDigest, mock.MatchedBy(func(data schemas.NotificationData) bool {
+		return len(data.Reviews) == 1 && data.Reviews[0].ID == "pr1"
+	})).Return(nil)
+
+	sent, err := u.SendDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	d.sender.AssertNumberOfCalls(t, "NotifyVia", 1)
+	d.settings.AssertNotCalled(t, "MarkSent", "u3", mock.Anything)
+	d.settings.AssertNotCalled(t, "MarkSent", "u4", mock.Anything)
+}
+
+func TestSendDue_SkipsInactiveAndEmpty(t *testing.T) {
+	u, d := newTestUsecase()
+	d.settings.On("ListEnabled").Return([]schemas.DigestSetting{
+		{UserID: "u2", Channel: notify.ChannelSlack, SendAt: "06:00", Timezone: "UTC", Enabled: true},
+		{UserID: "u3", Channel: notify.ChannelSlack, SendAt: "06:00", Timezone: "UTC", Enabled: true},
+	}, nil)
+	d.settings.On("MarkSent", mock.Anything, "2026-03-02").Return(true, nil)
+	d.users.On("GetByID", "u2").Return(&schemas.User{ID: "u2", IsActive: false}, nil)
+	d.users.On("GetByID", "u3").Return(&schemas.User{ID: "u3", IsActive: true}, nil)
+	d.prs.On("GetByReviewerID", "u2").Return([]schemas.PullRequestShort{{ID: "pr1", Status: "OPEN"}}, nil)
+	d.prs.On("GetByReviewerID", "u3").Return([]schemas.PullRequestShort{{ID: "pr2", Status: "MERGED"}}, nil)
+
+	sent, err := u.SendDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	d.sender.AssertNotCalled(t, "NotifyVia", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendDue_DefaultScheduleForUsersWithoutSetting(t *testing.T) {
+	u, d := newTestUsecase()
+	u.defaults = &schemas.DigestSetting{Channel: notify.ChannelEmail, SendAt: "06:00", Timezone: "UTC", Enabled: true}
+	// u2 переопределил расписание, u3 выключил сводки, u4 неактивен, u5 сегодня уже получил сводку
+	d.settings.On("ListEnabled").Return([]schemas.DigestSetting{
+		{UserID: "u2", Channel: notify.ChannelSlack, SendAt: "06:00", Timezone: "UTC", Enabled: true},
+	}, nil)
+	d.settings.On("ListConfiguredUserIDs").Return([]string{"u2", "u3"}, nil)
+	d.settings.On("ListDefaultSent").Return(map[string]string{"u5": "2026-03-02", "u6": "2026-03-01"}, nil)
+	d.users.On("List").Return([]schemas.User{
+		{ID: "u2", IsActive: true}, {ID: "u3", IsActive: true}, {ID: "u4", IsActive: false},
+		{ID: "u5", IsActive: true}, {ID: "u6", IsActive: true},
+	}, nil)
+	d.settings.On("MarkSent", "u2", "2026-03-02").Return(true, nil)
+	d.settings.On("MarkDefaultSent", "u6", "2026-03-02").Return(true, nil)
+	for _, id := range []string{"u2", "u6"} {
+		d.users.On("GetByID", id).Return(&schemas.User{ID: id, IsActive: true}, nil)
+		d.prs.On("GetByReviewerID", id).Return([]schemas.PullRequestShort{{ID: "pr-" + id, Status: "OPEN"}}, nil)
+	}
+	d.sender.On("NotifyVia", "u2", notify.ChannelSlack, notify.TemplateDigest, mock.Anything).Return(nil)
+	d.sender.On("NotifyVia", "u6", notify.ChannelEmail, notify.TemplateDigest, mock.Anything).Return(nil)
+
+	sent, err := u.SendDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	d.sender.AssertNumberOfCalls(t, "NotifyVia", 2)
+	d.settings.AssertNumberOfCalls(t, "MarkDefaultSent", 1)
+}
+
+func TestSendDue_NoDefaultScheduleIsOptIn(t *testing.T) {
+	u, d := newTestUsecase()
+	d.settings.On("ListEnabled").Return([]schemas.DigestSetting{}, nil)
+
+	sent, err := u.SendDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	d.users.AssertNotCalled(t, "List")
+	d.settings.AssertNotCalled(t, "ListConfiguredUserIDs")
+}
+
+func TestDefaultSetting(t *testing.T) {
+	setting, err := DefaultSetting("", "09:00", "UTC")
+	require.NoError(t, err)
+	assert.Nil(t, setting)
+
+	setting, err = DefaultSetting(notify.ChannelSlack, "09:30", "Europe/Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, &schemas.DigestSetting{Channel: notify.ChannelSlack, SendAt: "09:30", Timezone: "Europe/Moscow", Enabled: true}, setting)
+
+	_, err = DefaultSetting("pager", "09:00", "UTC")
+	assert.Error(t, err)
+	_, err = DefaultSetting(notify.ChannelSlack, "25:00", "UTC")
+	assert.Error(t, err)
+	_, err = DefaultSetting(notify.ChannelSlack, "09:00", "Mars/Olympus")
+	assert.Error(t, err)
+}
+
+func TestPreview_RendersDigestTemplate(t *testing.T) {
+	u, d := newTestUsecase()
+	d.users.On("GetByID", "u2").Return(&schemas.User{ID: "u2", Username: "Alice", IsActive: true}, nil)
+	d.prs.On("GetByReviewerID", "u2").Return([]schemas.PullRequestShort{{ID: "pr1", Name: "Add search", AuthorID: "u1", Status: "OPEN", CreatedAt: ago(26 * time.Hour)}}, nil)
+	rendered := schemas.NotificationData{}
+	d.sender.On("Render", notify.TemplateDigest, mock.Anything).Run(func(args mock.Arguments) {
+		rendered = args.Get(1).(schemas.NotificationData)
+	}).Return(notify.Message{Subject: "digest"}, nil)
+
+	digest, msg, err := u.Preview(context.Background(), "u2")
+
+	require.NoError(t, err)
+	assert.Len(t, digest.Reviews, 1)
+	assert.Equal(t, "digest", msg.Subject)
+	assert.Equal(t, "Alice", rendered.User.Username)
+	assert.Equal(t, "1d 2h", rendered.Reviews[0].Waiting)
+}
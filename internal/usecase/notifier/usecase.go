@@ -0,0 +1,152 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"log"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/notify"
+)
+
+// Usecase уведомляет ревьюверов о назначении и снятии с ревью по каналам из их настроек
+type Usecase struct {
+	channels  map[string]notify.Channel // настроенные каналы; каналы без настройки пропускаются
+	templates *notify.Templates
+	prefRepo  interfaces.NotificationPreferenceRepository
+	userRepo  interfaces.UserRepository
+}
+
+func NewUsecase(channels map[string]notify.Channel, templates *notify.Templates, prefRepo interfaces.NotificationPreferenceRepository,
+	userRepo interfaces.UserRepository) *Usecase {
+	return &Usecase{channels: channels, templates: templates, prefRepo: prefRepo, userRepo: userRepo}
+}
+
+// Publish вызывается relay-воркером outbox: временная ошибка канала приводит к повторной попытке,
+// окончательный отказ одному получателю отбрасывается, чтобы не держать уведомления остальным
+func (u *Usecase) Publish(ctx context.Context, event *schemas.Event) error {
+	if len(u.channels) == 0 || (event.Type != schemas.EventPRCreated && event.Type != schemas.EventPRReassigned) {
+		return nil
+	}
+	return u.Handle(ctx, event)
+}
+
+// Handle отправляет уведомления по событию: назначенным ревьюверам и снятому с ревью
+func (u *Usecase) Handle(ctx context.Context, event *schemas.Event) error {
+	var data schemas.PREventData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		return err
+	}
+	if data.PR == nil {
+		return nil
+	}
+
+	var errs []error
+	switch event.Type {
+	case schemas.EventPRCreated:
+		for _, reviewer := range data.PR.AssignedReviewers {
+			errs = append(errs, u.Notify(ctx, reviewer, notify.TemplateAssigned, schemas.NotificationData{PR: data.PR}))
+		}
+	case schemas.EventPRReassigned:
+		errs = append(errs,
+			u.Notify(ctx, data.NewReviewer, notify.TemplateAssigned, schemas.NotificationData{PR: data.PR, ReplacedReviewer: data.ReplacedReviewer}),
+			u.Notify(ctx, data.ReplacedReviewer, notify.TemplateUnassigned, schemas.NotificationData{PR: data.PR, NewReviewer: data.NewReviewer}),
+		)
+	}
+	return stderrors.Join(errs...)
+}
+
+// Notify отправляет пользователю сообщение по шаблону во все включённые каналы
+func (u *Usecase) Notify(ctx context.Context, userID, template string, data schemas.NotificationData) error {
+	return u.send(ctx, userID, "", template, data)
+}
+
+// NotifyVia отправляет сообщение только в канал channel, если он включён у пользователя
+func (u *Usecase) NotifyVia(ctx context.Context, userID, channel, template string, data schemas.NotificationData) error {
+	return u.send(ctx, userID, channel, template, data)
+}
+
+// Render собирает сообщение по шаблону без отправки
+func (u *Usecase) Render(template string, data schemas.NotificationData) (notify.Message, error) {
+	return u.templates.Render(template, data)
+}
+
+// send отправляет сообщение в каналы пользователя; only ограничивает отправку одним каналом
+func (u *Usecase) send(ctx context.Context, userID, only, template string, data schemas.NotificationData) error {
+	if userID == "" {
+		return nil
+	}
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return err
+	}
+	prefs, err := u.prefRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	data.User = user
+	msg, err := u.templates.Render(template, data)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, pref := range prefs {
+		channel := u.channels[pref.Channel]
+		if !pref.Enabled || channel == nil || (only != "" && pref.Channel != only) {
+			continue
+		}
+		if err := channel.Send(pref.Address, msg); err != nil {
+			err = fmt.Errorf("%s to %s: %w", pref.Channel, userID, err)
+			if notify.IsPermanent(err) {
+				// Повтор не поможет, пока пользователь не исправит адрес; остальным уведомления нужны сейчас
+				log.Printf("notifier: dropped: %v", err)
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+func (u *Usecase) SetPreference(ctx context.Context, pref *schemas.NotificationPreference) error {
+	if !knownChannel(pref.Channel) || pref.Address == "" {
+		return errors.ErrInvalidChannel
+	}
+	user, err := u.userRepo.GetByID(ctx, pref.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.ErrNotFound
+	}
+	return u.prefRepo.Upsert(ctx, pref)
+}
+
+func (u *Usecase) ListPreferences(ctx context.Context, userID string) ([]schemas.NotificationPreference, error) {
+	return u.prefRepo.ListByUser(ctx, userID)
+}
+
+func (u *Usecase) DeletePreference(ctx context.Context, userID, channel string) error {
+	deleted, err := u.prefRepo.Delete(ctx, userID, channel)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+func knownChannel(channel string) bool {
+	for _, c := range notify.KnownChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock для UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	args := m.Called(userID, isActive, events)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context) ([]schemas.User, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+// Mock для NotificationPreferenceRepository
+type MockNotificationPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceRepository) Upsert(ctx context.Context, pref *schemas.NotificationPreference) error {
+	args := m.Called(pref)
+	return args.Error(0)
+}
+
+func (m *MockNotificationPreferenceRepository) ListByUser(ctx context.Context, userID string) ([]schemas.NotificationPreference, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]schemas.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) Delete(ctx context.Context, userID, channel string) (bool, error) {
+	args := m.Called(userID, channel)
+	return args.Bool(0), args.Error(1)
+}
+
+// fakeChannel запоминает отправленные сообщения
+type fakeChannel struct {
+	sent map[string][]notify.Message // адрес -> сообщения
+	fail map[string]error            // адрес -> ошибка отправки
+}
+
+func (c *fakeChannel) Send(address string, msg notify.Message) error {
+	if err := c.fail[address]; err != nil {
+		return err
+	}
+	c.sent[address] = append(c.sent[address], msg)
+	return nil
+}
+
+func newTestUsecase(t *testing.T) (*Usecase, *MockNotificationPreferenceRepository, *MockUserRepository, *fakeChannel) {
+	templates, err := notify.LoadTemplates("")
+	require.NoError(t, err)
+	prefs := new(MockNotificationPreferenceRepository)
+	users := new(MockUserRepository)
+	slack := &fakeChannel{sent: map[string][]notify.Message{}, fail: map[string]error{}}
+	u := NewUsecase(map[string]notify.Channel{notify.ChannelSlack: slack}, templates, prefs, users)
+	return u, prefs, users, slack
+}
+
+func TestHandle_CreatedNotifiesEveryReviewer(t *testing.T) {
+	u, prefs, users, slack := newTestUsecase(t)
+	users.On("GetByID", "u2").Return(&schemas.User{ID: "u2", Username: "Alice"}, nil)
+	users.On("GetByID", "u3").Return(&schemas.User{ID: "u3", Username: "Bob"}, nil)
+	prefs.On("ListByUser", "u2").Return([]schemas.NotificationPreference{
+		{UserID: "u2", Channel: notify.ChannelSlack, Address: "@alice", Enabled: true},
+		{UserID: "u2", Channel: notify.ChannelTelegram, Address: "42", Enabled: true}, // канал не настроен
+	}, nil)
+	prefs.On("ListByUser", "u3").Return([]schemas.NotificationPreference{
+		{UserID: "u3", Channel: notify.ChannelSlack, Address: "@bob", Enabled: false},
+	}, nil)
+
+	event, err := schemas.NewEvent(schemas.EventPRCreated, schemas.PREventData{
+		PR: &schemas.PullRequest{ID: "pr1", Name: "Add search", AuthorID: "u1", AssignedReviewers: []string{"u2", "u3"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, u.Handle(context.Background(), event))
+
+	require.Len(t, slack.sent["@alice"], 1)
+	assert.Equal(t, "Review requested: Add search", slack.sent["@alice"][0].Subject)
+	assert.Empty(t, slack.sent["@bob"])
+}
+
+func TestHandle_ReassignedNotifiesBothSides(t *testing.T) {
+	u, prefs, users, slack := newTestUsecase(t)
+	users.On("GetByID", "u2").Return(&schemas.User{ID: "u2", Username: "Alice"}, nil)
+	users.On("GetByID", "u4").Return(&schemas.User{ID: "u4", Username: "Dave"}, nil)
+	prefs.On("ListByUser", "u2").Return([]schemas.NotificationPreference{{Channel: notify.ChannelSlack, Address: "@alice", Enabled: true}}, nil)
+	prefs.On("ListByUser", "u4").Return([]schemas.NotificationPreference{{Channel: notify.ChannelSlack, Address: "@dave", Enabled: true}}, nil)
+
+	event, err := schemas.NewEvent(schemas.EventPRReassigned, schemas.PREventData{
+		PR:               &schemas.PullRequest{ID: "pr1", Name: "Add search", AuthorID: "u1"},
+		ReplacedReviewer: "u2",
+		NewReviewer:      "u4",
+	})
+	require.NoError(t, err)
+	require.NoError(t, u.Handle(context.Background(), event))
+
+	assert.Contains(t, slack.sent["@dave"][0].Text, "You replace u2")
+	assert.Equal(t, "Review reassigned: Add search", slack.sent["@alice"][0].Subject)
+}
+
+func TestPublish_DropsPermanentFailureAndRetriesTransient(t *testing.T) {
+	u, prefs, users, slack := newTestUsecase(t)
+	users.On("GetByID", "u2").Return(&schemas.User{ID: "u2", Username: "Alice"}, nil)
+	users.On("GetByID", "u3").Return(&schemas.User{ID: "u3", Username: "Bob"}, nil)
+	prefs.On("ListByUser", "u2").Return([]schemas.NotificationPreference{{Channel: notify.ChannelSlack, Address: "@nobody", Enabled: true}}, nil)
+	prefs.On("ListByUser", "u3").Return([]schemas.NotificationPreference{{Channel: notify.ChannelSlack, Address: "@bob", Enabled: true}}, nil)
+	slack.fail["@nobody"] = &notify.PermanentError{Err: errors.New("channel_not_found")}
+
+	event, err := schemas.NewEvent(schemas.EventPRCreated, schemas.PREventData{
+		PR: &schemas.PullRequest{ID: "pr1", Name: "Add search", AuthorID: "u1", AssignedReviewers: []string{"u2", "u3"}},
+	})
+	require.NoError(t, err)
+
+	// Неверный адрес одного ревьювера не задерживает событие и уведомления остальным
+	require.NoError(t, u.Publish(context.Background(), event))
+	assert.Len(t, slack.sent["@bob"], 1)
+
+	slack.fail["@bob"] = errors.New("connection refused")
+	assert.Error(t, u.Publish(context.Background(), event))
+}
+
+func TestSetPreference_Validation(t *testing.T) {
+	u, prefs, users, _ := newTestUsecase(t)
+
+	assert.Equal(t, pkgerrors.ErrInvalidChannel, u.SetPreference(context.Background(), &schemas.NotificationPreference{UserID: "u2", Channel: "pager", Address: "x"}))
+	assert.Equal(t, pkgerrors.ErrInvalidChannel, u.SetPreference(context.Background(), &schemas.NotificationPreference{UserID: "u2", Channel: notify.ChannelEmail}))
+
+	users.On("GetByID", "ghost").Return(nil, nil)
+	assert.Equal(t, pkgerrors.ErrNotFound, u.SetPreference(context.Background(), &schemas.NotificationPreference{UserID: "ghost", Channel: notify.ChannelEmail, Address: "g@example.com"}))
+
+	users.On("GetByID", "u2").Return(&schemas.User{ID: "u2"}, nil)
+	prefs.On("Upsert", mock.AnythingOfType("*schemas.NotificationPreference")).Return(nil)
+	assert.NoError(t, u.SetPreference(context.Background(), &schemas.NotificationPreference{UserID: "u2", Channel: notify.ChannelEmail, Address: "a@example.com"}))
+}
@@ -0,0 +1,171 @@
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+// relayBatch — сколько событий получатель разбирает за проход
+const relayBatch = 100
+
+// Usecase — relay: доставляет события из outbox зарегистрированным получателям не менее одного раза.
+// Каждый получатель идёт по outbox независимо, в порядке seq среди уже видимых событий: неудача
+// останавливает его на этом событии до следующей попытки, остальные получатели продолжают работу.
+// Seq выдаётся до коммита, поэтому событие с меньшим seq может стать видимым позже большего и будет
+// доставлено после него; пропуска не будет — доставка отмечается для каждого события отдельно.
+type Usecase struct {
+	repo      interfaces.OutboxRepository
+	sinks     map[string]interfaces.EventPublisher
+	owner     string        // идентификатор инстанса для аренды получателей
+	lease     time.Duration // аренда получателя; продлевается каждым проходом и по ходу долгого прохода
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewUsecase(repo interfaces.OutboxRepository, sinks map[string]interfaces.EventPublisher, retention time.Duration) *Usecase {
+	return &Usecase{repo: repo, sinks: sinks, owner: randomID(), lease: 30 * time.Second, retention: retention, now: time.Now}
+}
+
+// Sinks — имена получателей по алфавиту
+func (u *Usecase) Sinks() []string {
+	names := make([]string, 0, len(u.sinks))
+	for name := range u.sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (u *Usecase) Progress(ctx context.Context) ([]schemas.OutboxSinkProgress, error) {
+	return u.repo.Progress(ctx, u.Sinks())
+}
+
+// Run запускает по воркеру на получателя и чистку outbox; возвращается после отмены ctx
+func (u *Usecase) Run(ctx context.Context, poll time.Duration) {
+	var wg sync.WaitGroup
+	for _, sink := range u.Sinks() {
+		wg.Add(1)
+		go func(sink string) {
+			defer wg.Done()
+			u.runSink(ctx, sink, poll)
+		}(sink)
+	}
+	if u.retention > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u.runPurge(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (u *Usecase) runSink(ctx context.Context, sink string, poll time.Duration) {
+	failures := 0
+	for {
+		delay := poll
+		if _, err := u.Relay(ctx, sink); err != nil {
+			log.Printf("outbox relay to %s: %v", sink, err)
+			failures++
+			delay = backoff(poll, failures)
+		} else {
+			failures = 0
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (u *Usecase) runPurge(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		if n, err := u.Purge(ctx); err != nil {
+			log.Printf("outbox purge: %v", err)
+		} else if n > 0 {
+			log.Printf("outbox purge: removed %d events", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge удаляет события старше retention, уже доставленные всем получателям. Получатель, который
+// не может доставить событие дольше retention, удерживает его в outbox до успешной доставки.
+func (u *Usecase) Purge(ctx context.Context) (int, error) {
+	return u.repo.Purge(ctx, u.now().UTC().Add(-u.retention), u.Sinks())
+}
+
+// Relay доставляет получателю ожидающие события и возвращает, сколько доставлено.
+// Если получателя обслуживает другой инстанс, ничего не делает.
+func (u *Usecase) Relay(ctx context.Context, sink string) (int, error) {
+	publisher, ok := u.sinks[sink]
+	if !ok {
+		return 0, fmt.Errorf("unknown sink %s", sink)
+	}
+	claimed, err := u.repo.ClaimSink(ctx, sink, u.owner, u.lease)
+	if err != nil || !claimed {
+		return 0, err
+	}
+	claimedAt := u.now()
+	events, err := u.repo.Pending(ctx, sink, relayBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range events {
+		// Долгий проход продлевает аренду, иначе получателя заберёт другой инстанс и разошлёт те же события
+		if u.now().Sub(claimedAt) >= u.lease/3 {
+			if claimed, err := u.repo.ClaimSink(ctx, sink, u.owner, u.lease); err != nil || !claimed {
+				return i, err
+			}
+			claimedAt = u.now()
+		}
+		event := events[i].Event
+		if err := publisher.Publish(ctx, &event); err != nil {
+			if recErr := u.repo.RecordFailure(ctx, sink, err.Error()); recErr != nil {
+				log.Printf("outbox relay to %s: record failure: %v", sink, recErr)
+			}
+			return i, fmt.Errorf("event %d (%s): %w", events[i].Seq, event.Type, err)
+		}
+		if err := u.repo.MarkDelivered(ctx, sink, events[i].Seq); err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+// backoff — пауза перед повтором после failures неудач подряд, не больше минуты
+func backoff(poll time.Duration, failures int) time.Duration {
+	delay := poll
+	for i := 1; i < failures && delay < time.Minute; i++ {
+		delay *= 2
+	}
+	if delay > time.Minute {
+		delay = time.Minute
+	}
+	return delay
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
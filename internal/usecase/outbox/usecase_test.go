@@ -0,0 +1,190 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock для OutboxRepository
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) Append(ctx context.Context, events ...*schemas.Event) error {
+	args := m.Called(events)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) Pending(ctx context.Context, sink string, limit int) ([]schemas.OutboxEvent, error) {
+	args := m.Called(sink, limit)
+	return args.Get(0).([]schemas.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkDelivered(ctx context.Context, sink string, seq int64) error {
+	args := m.Called(sink, seq)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) RecordFailure(ctx context.Context, sink string, message string) error {
+	args := m.Called(sink, message)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) ClaimSink(ctx context.Context, sink, owner string, lease time.Duration) (bool, error) {
+	args := m.Called(sink, owner, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOutboxRepository) Progress(ctx context.Context, sinks []string) ([]schemas.OutboxSinkProgress, error) {
+	args := m.Called(sinks)
+	return args.Get(0).([]schemas.OutboxSinkProgress), args.Error(1)
+}
+
+func (m *MockOutboxRepository) After(ctx context.Context, seq int64, limit int) ([]schemas.OutboxEvent, error) {
+	args := m.Called(seq, limit)
+	return args.Get(0).([]schemas.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) LastSeq(ctx context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOutboxRepository) Purge(ctx context.Context, before time.Time, sinks []string) (int, error) {
+	args := m.Called(before, sinks)
+	return args.Int(0), args.Error(1)
+}
+
+// Mock для EventPublisher
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Publish(ctx context.Context, event *schemas.Event) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func outboxEvents(ids ...string) []schemas.OutboxEvent {
+	events := make([]schemas.OutboxEvent, 0, len(ids))
+	for i, id := range ids {
+		events = append(events, schemas.OutboxEvent{Seq: int64(i + 1), Event: schemas.Event{ID: id, Type: schemas.EventPRCreated}})
+	}
+	return events
+}
+
+func eventID(id string) interface{} {
+	return mock.MatchedBy(func(e *schemas.Event) bool { return e.ID == id })
+}
+
+func TestRelay_DeliversInOrderAndStopsOnFailure(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	webhooks := new(MockEventPublisher)
+	u := NewUsecase(repo, map[string]interfaces.EventPublisher{"webhooks": webhooks}, 0)
+
+	repo.On("ClaimSink", "webhooks", u.owner, u.lease).Return(true, nil)
+	repo.On("Pending", "webhooks", relayBatch).Return(outboxEvents("e1", "e2", "e3"), nil)
+	webhooks.On("Publish", eventID("e1")).Return(nil)
+	webhooks.On("Publish", eventID("e2")).Return(errors.New("connection refused"))
+	repo.On("MarkDelivered", "webhooks", int64(1)).Return(nil)
+	repo.On("RecordFailure", "webhooks", "connection refused").Return(nil)
+
+	n, err := u.Relay(context.Background(), "webhooks")
+
+	require.Error(t, err)
+	assert.Equal(t, 1, n)
+	// e3 не отправляется раньше e2: порядок для получателя сохраняется
+	webhooks.AssertNotCalled(t, "Publish", eventID("e3"))
+	repo.AssertNotCalled(t, "MarkDelivered", "webhooks", int64(2))
+	repo.AssertExpectations(t)
+}
+
+func TestRelay_SinksProgressIndependently(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	webhooks := new(MockEventPublisher)
+	notifier := new(MockEventPublisher)
+	u := NewUsecase(repo, map[string]interfaces.EventPublisher{"webhooks": webhooks, "notifier": notifier}, 0)
+
+	repo.On("ClaimSink", mock.Anything, u.owner, u.lease).Return(true, nil)
+	repo.On("Pending", "webhooks", relayBatch).Return(outboxEvents("e1"), nil)
+	repo.On("Pending", "notifier", relayBatch).Return(outboxEvents("e1"), nil)
+	webhooks.On("Publish", eventID("e1")).Return(errors.New("boom"))
+	notifier.On("Publish", eventID("e1")).Return(nil)
+	repo.On("RecordFailure", "webhooks", "boom").Return(nil)
+	repo.On("MarkDelivered", "notifier", int64(1)).Return(nil)
+
+	_, err := u.Relay(context.Background(), "webhooks")
+	assert.Error(t, err)
+	n, err := u.Relay(context.Background(), "notifier")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"notifier", "webhooks"}, u.Sinks())
+}
+
+func TestRelay_SkipsSinkClaimedByAnotherInstance(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	webhooks := new(MockEventPublisher)
+	u := NewUsecase(repo, map[string]interfaces.EventPublisher{"webhooks": webhooks}, 0)
+
+	repo.On("ClaimSink", "webhooks", u.owner, u.lease).Return(false, nil)
+
+	n, err := u.Relay(context.Background(), "webhooks")
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	repo.AssertNotCalled(t, "Pending", mock.Anything, mock.Anything)
+
+	_, err = u.Relay(context.Background(), "unknown")
+	assert.Error(t, err)
+}
+
+func TestRelay_RenewsLeaseDuringLongBatch(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	webhooks := new(MockEventPublisher)
+	u := NewUsecase(repo, map[string]interfaces.EventPublisher{"webhooks": webhooks}, 0)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	u.now = func() time.Time { return now }
+
+	repo.On("ClaimSink", "webhooks", u.owner, u.lease).Return(true, nil).Once()
+	repo.On("ClaimSink", "webhooks", u.owner, u.lease).Return(false, nil).Once()
+	repo.On("Pending", "webhooks", relayBatch).Return(outboxEvents("e1", "e2"), nil)
+	// Отправка e1 занимает половину аренды: перед e2 аренда продлевается, но её уже забрал другой инстанс
+	webhooks.On("Publish", eventID("e1")).Run(func(mock.Arguments) { now = now.Add(u.lease / 2) }).Return(nil)
+	repo.On("MarkDelivered", "webhooks", int64(1)).Return(nil)
+
+	n, err := u.Relay(context.Background(), "webhooks")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	webhooks.AssertNotCalled(t, "Publish", eventID("e2"))
+	repo.AssertExpectations(t)
+}
+
+func TestPurge_OnlyEventsDeliveredToAllSinks(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	u := NewUsecase(repo, map[string]interfaces.EventPublisher{"webhooks": new(MockEventPublisher), "notifier": new(MockEventPublisher)}, 24*time.Hour)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	u.now = func() time.Time { return now }
+
+	// Недоставленные хотя бы одному получателю события не удаляются, даже если они старше retention
+	repo.On("Purge", now.Add(-24*time.Hour), []string{"notifier", "webhooks"}).Return(3, nil)
+
+	n, err := u.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	repo.AssertExpectations(t)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(time.Second, 1))
+	assert.Equal(t, 4*time.Second, backoff(time.Second, 3))
+	assert.Equal(t, time.Minute, backoff(time.Second, 20))
+}
@@ -1,6 +1,7 @@
 package pr
 
 import (
+	"context"
 	"math/rand"
 	"time"
 	"ReviewAssigner/internal/domain/interfaces"
@@ -8,17 +9,44 @@ import (
 	"ReviewAssigner/internal/pkg/errors"
 )
 
+// ReviewerSyncer переносит назначенных ревьюверов в Git-хостинг PR (реализация — reviewsync.Usecase)
+type ReviewerSyncer interface {
+	ReviewersChanged(pr *schemas.PullRequest, removed []string)
+}
+
 type Usecase struct {
 	userRepo interfaces.UserRepository
 	prRepo   interfaces.PullRequestRepository
+	teamRepo interfaces.TeamRepository
+	tx       interfaces.TxManager
+	syncer   ReviewerSyncer // nil — синхронизация с провайдером выключена
+}
+
+// Доменные события передаются репозиторию и сохраняются в outbox вместе с изменением PR.
+// Чтение и запись каждой операции над PR выполняются одной единицей работы tx.
+func NewUsecase(userRepo interfaces.UserRepository, prRepo interfaces.PullRequestRepository, teamRepo interfaces.TeamRepository, tx interfaces.TxManager, syncer ReviewerSyncer) *Usecase {
+	return &Usecase{userRepo: userRepo, prRepo: prRepo, teamRepo: teamRepo, tx: tx, syncer: syncer}
 }
 
-func NewUsecase(userRepo interfaces.UserRepository, prRepo interfaces.PullRequestRepository) *Usecase {
-	return &Usecase{userRepo: userRepo, prRepo: prRepo}
+// CreatePR создаёт PR и назначает ревьюверов; выбранные кандидаты не могут быть деактивированы до записи PR
+func (u *Usecase) CreatePR(ctx context.Context, prID, name, authorID string) (*schemas.PullRequest, error) {
+	var pr *schemas.PullRequest
+	err := u.tx.Do(ctx, func(repos interfaces.TxRepositories) error {
+		var err error
+		pr, err = createPR(ctx, repos, prID, name, authorID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if u.syncer != nil {
+		u.syncer.ReviewersChanged(pr, nil)
+	}
+	return pr, nil
 }
 
-func (u *Usecase) CreatePR(prID, name, authorID string) (*schemas.PullRequest, error) {
-	exists, err := u.prRepo.Exists(prID)
+func createPR(ctx context.Context, repos interfaces.TxRepositories, prID, name, authorID string) (*schemas.PullRequest, error) {
+	exists, err := repos.PullRequests.Exists(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +54,7 @@ func (u *Usecase) CreatePR(prID, name, authorID string) (*schemas.PullRequest, e
 		return nil, errors.ErrPRExists
 	}
 
-	author, err := u.userRepo.GetByID(authorID)
+	author, err := repos.Users.GetByID(ctx, authorID)
 	if err != nil {
 		return nil, err
 	}
@@ -34,17 +62,27 @@ func (u *Usecase) CreatePR(prID, name, authorID string) (*schemas.PullRequest, e
 		return nil, errors.ErrNotFound
 	}
 
-	candidates, err := u.userRepo.GetActiveByTeam(author.TeamName, authorID)
+	candidates, err := repos.Users.GetActiveByTeamForShare(ctx, author.TeamName, authorID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Случайный выбор до 2 ревьюверов
+	// Количество ревьюверов берётся из настроек команды автора
+	reviewersCount := schemas.DefaultReviewersCount
+	team, err := repos.Teams.GetByName(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team != nil && team.ReviewersCount > 0 {
+		reviewersCount = team.ReviewersCount
+	}
+
+	// Случайный выбор до reviewersCount ревьюверов
 	rand.New(rand.NewSource(time.Now().UnixNano())) // Исправлено: вместо rand.Seed
 	selected := []string{}
 	if len(candidates) > 0 {
 		perm := rand.Perm(len(candidates))
-		for i := 0; i < len(perm) && i < 2; i++ {
+		for i := 0; i < len(perm) && i < reviewersCount; i++ {
 			selected = append(selected, candidates[perm[i]].ID)
 		}
 	}
@@ -58,38 +96,150 @@ func (u *Usecase) CreatePR(prID, name, authorID string) (*schemas.PullRequest, e
 		Status:            "OPEN",
 		AssignedReviewers: selected,
 		CreatedAt:         &createdAt, // Исправлено: используем переменную
+		Version:           1,
 	}
 
-	err = u.prRepo.Create(pr)
-	return pr, err
+	event, err := schemas.NewEvent(schemas.EventPRCreated, schemas.PREventData{PR: pr})
+	if err != nil {
+		return nil, err
+	}
+	err = repos.PullRequests.Create(ctx, pr, event)
+	if err != nil {
+		return nil, err
+	}
+	return pr, nil
 }
 
-func (u *Usecase) MergePR(prID string) (*schemas.PullRequest, error) {
-	pr, err := u.prRepo.GetByID(prID)
+// MergePR мержит PR (идемпотентно). version — ожидаемая версия PR из If-Match, 0 — без проверки;
+// если PR изменили между чтением и записью, возвращается ErrVersionConflict
+func (u *Usecase) MergePR(ctx context.Context, prID string, version int) (*schemas.PullRequest, error) {
+	var merged *schemas.PullRequest
+	err := u.tx.Do(ctx, func(repos interfaces.TxRepositories) error {
+		var err error
+		merged, err = mergePR(ctx, repos.PullRequests, prID, version)
+		return err
+	})
+	return merged, err
+}
+
+func mergePR(ctx context.Context, prRepo interfaces.PullRequestRepository, prID string, version int) (*schemas.PullRequest, error) {
+	pr, err := prRepo.GetByIDForUpdate(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
 	if pr == nil {
 		return nil, errors.ErrNotFound
 	}
+	if err := checkVersion(pr, version); err != nil {
+		return nil, err
+	}
 	if pr.Status == "MERGED" {
 		return pr, nil // Идемпотентность
 	}
 	mergedAt := time.Now()
-	return u.prRepo.UpdateStatus(prID, "MERGED", &mergedAt)
+	merged := *pr
+	merged.Status = "MERGED"
+	merged.MergedAt = &mergedAt
+	merged.Version = pr.Version + 1
+	event, err := schemas.NewEvent(schemas.EventPRMerged, schemas.PREventData{PR: &merged})
+	if err != nil {
+		return nil, err
+	}
+	return prRepo.UpdateStatus(ctx, prID, pr.Version, "MERGED", &mergedAt, event)
 }
 
-func (u *Usecase) ReassignPR(prID, oldUserID string) (*schemas.PullRequest, string, error) {
-	pr, err := u.prRepo.GetByID(prID)
+// ClosePR закрывает PR без merge (идемпотентно); замерженный PR не меняется
+func (u *Usecase) ClosePR(ctx context.Context, prID string) (*schemas.PullRequest, error) {
+	var closed *schemas.PullRequest
+	err := u.tx.Do(ctx, func(repos interfaces.TxRepositories) error {
+		pr, err := repos.PullRequests.GetByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if pr == nil {
+			return errors.ErrNotFound
+		}
+		if pr.Status != "OPEN" {
+			closed = pr
+			return nil
+		}
+		closed, err = repos.PullRequests.UpdateStatus(ctx, prID, pr.Version, "CLOSED", nil)
+		return err
+	})
+	return closed, err
+}
+
+// ReopenPR снова открывает закрытый PR (идемпотентно); ревьюверы сохраняются
+func (u *Usecase) ReopenPR(ctx context.Context, prID string) (*schemas.PullRequest, error) {
+	var reopened *schemas.PullRequest
+	err := u.tx.Do(ctx, func(repos interfaces.TxRepositories) error {
+		pr, err := repos.PullRequests.GetByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if pr == nil {
+			return errors.ErrNotFound
+		}
+		if pr.Status == "MERGED" {
+			return errors.ErrPRMerged
+		}
+		if pr.Status == "OPEN" {
+			reopened = pr
+			return nil
+		}
+		reopened, err = repos.PullRequests.UpdateStatus(ctx, prID, pr.Version, "OPEN", nil)
+		return err
+	})
+	return reopened, err
+}
+
+// checkVersion сверяет версию PR с ожидаемой клиентом; 0 — клиент версию не передал
+func checkVersion(pr *schemas.PullRequest, version int) error {
+	if version != 0 && pr.Version != version {
+		return errors.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// ReassignPR заменяет ревьювера oldUserID случайным активным участником его команды.
+// version — ожидаемая версия PR из If-Match, 0 — без проверки. PR и кандидаты блокируются
+// до записи нового списка ревьюверов, поэтому параллельный merge или деактивация кандидата
+// дождутся переназначения; запись по устаревшей версии отклоняется с ErrVersionConflict
+func (u *Usecase) ReassignPR(ctx context.Context, prID, oldUserID string, version int) (*schemas.PullRequest, string, error) {
+	var pr *schemas.PullRequest
+	var newReviewer string
+	err := u.tx.Do(ctx, func(repos interfaces.TxRepositories) error {
+		var err error
+		pr, newReviewer, err = reassignPR(ctx, repos, prID, oldUserID, version)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if u.syncer != nil {
+		u.syncer.ReviewersChanged(pr, []string{oldUserID})
+	}
+	return pr, newReviewer, nil
+}
+
+// reassignPR возвращает PR в том виде, в каком он записан в транзакции
+func reassignPR(ctx context.Context, repos interfaces.TxRepositories, prID, oldUserID string, version int) (*schemas.PullRequest, string, error) {
+	pr, err := repos.PullRequests.GetByIDForUpdate(ctx, prID)
 	if err != nil {
 		return nil, "", err
 	}
 	if pr == nil {
 		return nil, "", errors.ErrNotFound
 	}
+	if err := checkVersion(pr, version); err != nil {
+		return nil, "", err
+	}
 	if pr.Status == "MERGED" {
 		return nil, "", errors.ErrPRMerged
 	}
+	if pr.Status == "CLOSED" {
+		return nil, "", errors.ErrPRClosed
+	}
 
 	// Проверить, что oldUserID назначен
 	assigned := false
@@ -104,7 +254,7 @@ func (u *Usecase) ReassignPR(prID, oldUserID string) (*schemas.PullRequest, stri
 	}
 
 	// Найти команду oldUserID
-	oldUser, err := u.userRepo.GetByID(oldUserID)
+	oldUser, err := repos.Users.GetByID(ctx, oldUserID)
 	if err != nil {
 		return nil, "", err
 	}
@@ -113,7 +263,7 @@ func (u *Usecase) ReassignPR(prID, oldUserID string) (*schemas.PullRequest, stri
 	}
 
 	// Кандидаты из команды oldUser (активные, исключая автора и уже назначенных)
-	candidates, err := u.userRepo.GetActiveByTeam(oldUser.TeamName, pr.AuthorID)
+	candidates, err := repos.Users.GetActiveByTeamForShare(ctx, oldUser.TeamName, pr.AuthorID)
 	if err != nil {
 		return nil, "", err
 	}
@@ -149,14 +299,39 @@ func (u *Usecase) ReassignPR(prID, oldUserID string) (*schemas.PullRequest, stri
 	}
 	newReviewers = append(newReviewers, newReviewer)
 
-	err = u.prRepo.UpdateReviewers(prID, newReviewers)
+	reassigned := *pr
+	reassigned.AssignedReviewers = newReviewers
+	reassigned.Version = pr.Version + 1
+	event, err := schemas.NewEvent(schemas.EventPRReassigned, schemas.PREventData{PR: &reassigned, ReplacedReviewer: oldUserID, NewReviewer: newReviewer})
 	if err != nil {
 		return nil, "", err
 	}
-	pr, _ = u.prRepo.GetByID(prID)
-	return pr, newReviewer, nil
+	if err := repos.PullRequests.UpdateReviewers(ctx, prID, pr.Version, newReviewers, event); err != nil {
+		return nil, "", err
+	}
+	return &reassigned, newReviewer, nil
+}
+
+// GetPR возвращает PR с ревьюверами; его Version — текущий ETag для If-Match
+func (u *Usecase) GetPR(ctx context.Context, prID string) (*schemas.PullRequest, error) {
+    pr, err := u.prRepo.GetByID(ctx, prID)
+    if err != nil {
+        return nil, err
+    }
+    if pr == nil {
+        return nil, errors.ErrNotFound
+    }
+    return pr, nil
+}
+
+func (u *Usecase) GetStats(ctx context.Context) (map[string]int, map[string]int, error) {
+    return u.prRepo.GetStats(ctx)
 }
 
-func (u *Usecase) GetStats() (map[string]int, map[string]int, error) {
-    return u.prRepo.GetStats()
+// GetPRs загружает PR с ревьюверами по идентификаторам одним запросом; несуществующие пропускаются
+func (u *Usecase) GetPRs(ctx context.Context, ids []string) ([]schemas.PullRequest, error) {
+    if len(ids) == 0 {
+        return nil, nil
+    }
+    return u.prRepo.GetByIDs(ctx, ids)
 }
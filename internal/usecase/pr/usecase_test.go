@@ -1,8 +1,10 @@
 package pr
 
 import (
+	"context"
 	"testing"
 	"time"
+	"ReviewAssigner/internal/domain/interfaces"
 	"ReviewAssigner/internal/domain/schemas"
 	pkgerrors "ReviewAssigner/internal/pkg/errors"
 
@@ -15,7 +17,7 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) GetByID(userID string) (*schemas.User, error) {
+func (m *MockUserRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -23,27 +25,50 @@ func (m *MockUserRepository) GetByID(userID string) (*schemas.User, error) {
 	return args.Get(0).(*schemas.User), args.Error(1)
 }
 
-func (m *MockUserRepository) UpdateIsActive(userID string, isActive bool) (*schemas.User, error) {
-	args := m.Called(userID, isActive)
+func (m *MockUserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	args := m.Called(userID, isActive, events)
 	return args.Get(0).(*schemas.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetActiveByTeam(teamName string, excludeUserID string) ([]schemas.User, error) {
+func (m *MockUserRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
 	args := m.Called(teamName, excludeUserID)
 	return args.Get(0).([]schemas.User), args.Error(1)
 }
 
+func (m *MockUserRepository) List(ctx context.Context) ([]schemas.User, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
 // Mock для PullRequestRepository
 type MockPullRequestRepository struct {
 	mock.Mock
 }
 
-func (m *MockPullRequestRepository) Create(pr *schemas.PullRequest) error {
-	args := m.Called(pr)
+func (m *MockPullRequestRepository) Create(ctx context.Context, pr *schemas.PullRequest, events ...*schemas.Event) error {
+	args := m.Called(pr, events)
 	return args.Error(0)
 }
 
-func (m *MockPullRequestRepository) GetByID(id string) (*schemas.PullRequest, error) {
+func (m *MockPullRequestRepository) GetByID(ctx context.Context, id string) (*schemas.PullRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetByIDForUpdate(ctx context.Context, id string) (*schemas.PullRequest, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -51,27 +76,42 @@ func (m *MockPullRequestRepository) GetByID(id string) (*schemas.PullRequest, er
 	return args.Get(0).(*schemas.PullRequest), args.Error(1)
 }
 
-func (m *MockPullRequestRepository) UpdateStatus(id string, status string, mergedAt *time.Time) (*schemas.PullRequest, error) {
-	args := m.Called(id, status, mergedAt)
+func (m *MockPullRequestRepository) GetByIDs(ctx context.Context, ids []string) ([]schemas.PullRequest, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) UpdateStatus(ctx context.Context, id string, version int, status string, mergedAt *time.Time, events ...*schemas.Event) (*schemas.PullRequest, error) {
+	args := m.Called(id, version, status, mergedAt, events)
 	return args.Get(0).(*schemas.PullRequest), args.Error(1)
 }
 
-func (m *MockPullRequestRepository) UpdateReviewers(id string, reviewers []string) error {
-	args := m.Called(id, reviewers)
+func (m *MockPullRequestRepository) UpdateReviewers(ctx context.Context, id string, version int, reviewers []string, events ...*schemas.Event) error {
+	args := m.Called(id, version, reviewers, events)
 	return args.Error(0)
 }
 
-func (m *MockPullRequestRepository) GetByReviewerID(userID string) ([]schemas.PullRequestShort, error) {
+func (m *MockPullRequestRepository) UpdateSCMSync(ctx context.Context, id string, state schemas.SCMSyncState) error {
+	args := m.Called(id, state)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) GetByReviewerID(ctx context.Context, userID string) ([]schemas.PullRequestShort, error) {
 	args := m.Called(userID)
 	return args.Get(0).([]schemas.PullRequestShort), args.Error(1)
 }
 
-func (m *MockPullRequestRepository) Exists(id string) (bool, error) {
+func (m *MockPullRequestRepository) GetByReviewerIDs(ctx context.Context, userIDs []string) (map[string][]schemas.PullRequestShort, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).(map[string][]schemas.PullRequestShort), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) Exists(ctx context.Context, id string) (bool, error) {
 	args := m.Called(id)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockPullRequestRepository) GetStats() (map[string]int, map[string]int, error) {
+func (m *MockPullRequestRepository) GetStats(ctx context.Context) (map[string]int, map[string]int, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, nil, args.Error(2)
@@ -79,20 +119,73 @@ func (m *MockPullRequestRepository) GetStats() (map[string]int, map[string]int,
 	return args.Get(0).(map[string]int), args.Get(1).(map[string]int), args.Error(2)
 }
 
+// Mock для TeamRepository
+type MockTeamRepository struct {
+	mock.Mock
+}
+
+func (m *MockTeamRepository) Create(ctx context.Context, team *schemas.Team) error {
+	args := m.Called(team)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) GetByName(ctx context.Context, name string) (*schemas.Team, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) GetByNames(ctx context.Context, names []string) ([]schemas.Team, error) {
+	args := m.Called(names)
+	return args.Get(0).([]schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) Exists(ctx context.Context, name string) (bool, error) {
+	args := m.Called(name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTeamRepository) List(ctx context.Context) ([]schemas.Team, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) ApplyRoster(ctx context.Context, diff *schemas.RosterDiff) error {
+	args := m.Called(diff)
+	return args.Error(0)
+}
+
+// mockTx выполняет единицу работы на тех же моках, без изоляции и отката
+type mockTx struct {
+	repos interfaces.TxRepositories
+}
+
+func (m *mockTx) Do(ctx context.Context, fn func(repos interfaces.TxRepositories) error) error {
+	return fn(m.repos)
+}
+
+func passTx(users *MockUserRepository, prs *MockPullRequestRepository, teams *MockTeamRepository) *mockTx {
+	return &mockTx{repos: interfaces.TxRepositories{Users: users, Teams: teams, PullRequests: prs}}
+}
+
 func TestUsecase_CreatePR_Success(t *testing.T) {
 	mockUserRepo := new(MockUserRepository)
 	mockPRRepo := new(MockPullRequestRepository)
-	usecase := NewUsecase(mockUserRepo, mockPRRepo)
+	mockTeamRepo := new(MockTeamRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), nil)
 
 	author := &schemas.User{ID: "u1", TeamName: "backend"}
 	candidates := []schemas.User{{ID: "u2"}}
 
 	mockPRRepo.On("Exists", "pr1").Return(false, nil)
 	mockUserRepo.On("GetByID", "u1").Return(author, nil)
-	mockUserRepo.On("GetActiveByTeam", "backend", "u1").Return(candidates, nil)
-	mockPRRepo.On("Create", mock.AnythingOfType("*schemas.PullRequest")).Return(nil)
+	mockUserRepo.On("GetActiveByTeamForShare", "backend", "u1").Return(candidates, nil)
+	mockTeamRepo.On("GetByName", "backend").Return(&schemas.Team{Name: "backend", ReviewersCount: 2}, nil)
+	mockPRRepo.On("Create", mock.AnythingOfType("*schemas.PullRequest"), eventOfType(schemas.EventPRCreated)).Return(nil)
 
-	result, err := usecase.CreatePR("pr1", "Test", "u1")
+	result, err := usecase.CreatePR(context.Background(), "pr1", "Test", "u1")
 	assert.NoError(t, err)
 	assert.Equal(t, "pr1", result.ID)
 	assert.Equal(t, "Test", result.Name)
@@ -102,15 +195,36 @@ func TestUsecase_CreatePR_Success(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestUsecase_CreatePR_TeamReviewersCount(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockPRRepo := new(MockPullRequestRepository)
+	mockTeamRepo := new(MockTeamRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), nil)
+
+	author := &schemas.User{ID: "u1", TeamName: "backend"}
+	candidates := []schemas.User{{ID: "u2"}, {ID: "u3"}, {ID: "u4"}}
+
+	mockPRRepo.On("Exists", "pr1").Return(false, nil)
+	mockUserRepo.On("GetByID", "u1").Return(author, nil)
+	mockUserRepo.On("GetActiveByTeamForShare", "backend", "u1").Return(candidates, nil)
+	mockTeamRepo.On("GetByName", "backend").Return(&schemas.Team{Name: "backend", ReviewersCount: 3}, nil)
+	mockPRRepo.On("Create", mock.AnythingOfType("*schemas.PullRequest"), eventOfType(schemas.EventPRCreated)).Return(nil)
+
+	result, err := usecase.CreatePR(context.Background(), "pr1", "Test", "u1")
+	assert.NoError(t, err)
+	assert.Len(t, result.AssignedReviewers, 3)
+}
+
 func TestUsecase_MergePR_Idempotent(t *testing.T) {
 	mockUserRepo := new(MockUserRepository)
 	mockPRRepo := new(MockPullRequestRepository)
-	usecase := NewUsecase(mockUserRepo, mockPRRepo)
+	mockTeamRepo := new(MockTeamRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), nil)
 
 	pr := &schemas.PullRequest{ID: "pr1", Status: "MERGED"}
-	mockPRRepo.On("GetByID", "pr1").Return(pr, nil)
+	mockPRRepo.On("GetByIDForUpdate", "pr1").Return(pr, nil)
 
-	result, err := usecase.MergePR("pr1")
+	result, err := usecase.MergePR(context.Background(), "pr1", 0)
 	assert.NoError(t, err)
 	assert.Equal(t, "MERGED", result.Status)
 
@@ -120,7 +234,8 @@ func TestUsecase_MergePR_Idempotent(t *testing.T) {
 func TestUsecase_ReassignPR_NoCandidate(t *testing.T) {
 	mockUserRepo := new(MockUserRepository)
 	mockPRRepo := new(MockPullRequestRepository)
-	usecase := NewUsecase(mockUserRepo, mockPRRepo)
+	mockTeamRepo := new(MockTeamRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), nil)
 
 	pr := &schemas.PullRequest{
 		ID:                "pr1",
@@ -130,13 +245,170 @@ func TestUsecase_ReassignPR_NoCandidate(t *testing.T) {
 	}
 	oldUser := &schemas.User{ID: "u2", TeamName: "backend"}
 
-	mockPRRepo.On("GetByID", "pr1").Return(pr, nil)
+	mockPRRepo.On("GetByIDForUpdate", "pr1").Return(pr, nil)
 	mockUserRepo.On("GetByID", "u2").Return(oldUser, nil)
-	mockUserRepo.On("GetActiveByTeam", "backend", mock.AnythingOfType("string")).Return([]schemas.User{}, nil)
+	mockUserRepo.On("GetActiveByTeamForShare", "backend", mock.AnythingOfType("string")).Return([]schemas.User{}, nil)
 
-	_, _, err := usecase.ReassignPR("pr1", "u2")
+	_, _, err := usecase.ReassignPR(context.Background(), "pr1", "u2", 0)
 	assert.Equal(t, pkgerrors.ErrNoCandidate, err)
 
 	mockPRRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
 }
+
+func TestUsecase_ClosePR_ThenReopen(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockPRRepo := new(MockPullRequestRepository)
+	mockTeamRepo := new(MockTeamRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), nil)
+
+	open := &schemas.PullRequest{ID: "pr1", Status: "OPEN", Version: 1}
+	closed := &schemas.PullRequest{ID: "pr1", Status: "CLOSED", Version: 2}
+	mockPRRepo.On("GetByIDForUpdate", "pr1").Return(open, nil).Once()
+	mockPRRepo.On("UpdateStatus", "pr1", 1, "CLOSED", (*time.Time)(nil), []*schemas.Event(nil)).Return(closed, nil)
+	mockPRRepo.On("GetByIDForUpdate", "pr1").Return(closed, nil).Once()
+	mockPRRepo.On("UpdateStatus", "pr1", 2, "OPEN", (*time.Time)(nil), []*schemas.Event(nil)).Return(open, nil)
+
+	result, err := usecase.ClosePR(context.Background(), "pr1")
+	assert.NoError(t, err)
+	assert.Equal(t, "CLOSED", result.Status)
+
+	result, err = usecase.ReopenPR(context.Background(), "pr1")
+	assert.NoError(t, err)
+	assert.Equal(t, "OPEN", result.Status)
+	mockPRRepo.AssertExpectations(t)
+}
+
+func TestUsecase_ReopenPR_Merged(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockPRRepo := new(MockPullRequestRepository)
+	mockTeamRepo := new(MockTeamRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), nil)
+
+	mockPRRepo.On("GetByIDForUpdate", "pr1").Return(&schemas.PullRequest{ID: "pr1", Status: "MERGED"}, nil)
+
+	_, err := usecase.ReopenPR(context.Background(), "pr1")
+	assert.Equal(t, pkgerrors.ErrPRMerged, err)
+}
+
+func TestUsecase_ReassignPR_Closed(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockPRRepo := new(MockPullRequestRepository)
+	mockTeamRepo := new(MockTeamRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), nil)
+
+	mockPRRepo.On("GetByIDForUpdate", "pr1").Return(&schemas.PullRequest{ID: "pr1", Status: "CLOSED", AssignedReviewers: []string{"u2"}}, nil)
+
+	_, _, err := usecase.ReassignPR(context.Background(), "pr1", "u2", 0)
+	assert.Equal(t, pkgerrors.ErrPRClosed, err)
+}
+
+// Mock для ReviewerSyncer
+type MockReviewerSyncer struct {
+	mock.Mock
+}
+
+func (m *MockReviewerSyncer) ReviewersChanged(pr *schemas.PullRequest, removed []string) {
+	m.Called(pr, removed)
+}
+
+func TestUsecase_ReassignPR_NotifiesSyncer(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockPRRepo := new(MockPullRequestRepository)
+	mockTeamRepo := new(MockTeamRepository)
+	syncer := new(MockReviewerSyncer)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), syncer)
+
+	pr := &schemas.PullRequest{ID: "github:acme/api#1", Status: "OPEN", AuthorID: "u1", AssignedReviewers: []string{"u2"}, Version: 3}
+	mockPRRepo.On("GetByIDForUpdate", "github:acme/api#1").Return(pr, nil)
+	mockUserRepo.On("GetByID", "u2").Return(&schemas.User{ID: "u2", TeamName: "backend"}, nil)
+	mockUserRepo.On("GetActiveByTeamForShare", "backend", "u1").Return([]schemas.User{{ID: "u2"}, {ID: "u3"}}, nil)
+	mockPRRepo.On("UpdateReviewers", "github:acme/api#1", 3, []string{"u3"}, eventOfType(schemas.EventPRReassigned)).Return(nil)
+	syncer.On("ReviewersChanged", mock.AnythingOfType("*schemas.PullRequest"), []string{"u2"}).Return()
+
+	// PR берётся из транзакции, а не перечитывается после коммита
+	result, newReviewer, err := usecase.ReassignPR(context.Background(), "github:acme/api#1", "u2", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "u3", newReviewer)
+	assert.Equal(t, []string{"u3"}, result.AssignedReviewers)
+	assert.Equal(t, 4, result.Version)
+	syncer.AssertExpectations(t)
+	mockPRRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+}
+
+// eventOfType сопоставляет события, переданные репозиторию для outbox
+func eventOfType(eventType string) interface{} {
+	return mock.MatchedBy(func(events []*schemas.Event) bool {
+		return len(events) == 1 && events[0].Type == eventType
+	})
+}
+
+func TestUsecase_MergePR_WritesEventOnce(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockPRRepo := new(MockPullRequestRepository)
+	mockTeamRepo := new(MockTeamRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), nil)
+
+	mockPRRepo.On("GetByIDForUpdate", "pr1").Return(&schemas.PullRequest{ID: "pr1", Status: "OPEN", Version: 1}, nil).Once()
+	mockPRRepo.On("UpdateStatus", "pr1", 1, "MERGED", mock.AnythingOfType("*time.Time"), eventOfType(schemas.EventPRMerged)).
+		Return(&schemas.PullRequest{ID: "pr1", Status: "MERGED"}, nil).Once()
+
+	_, err := usecase.MergePR(context.Background(), "pr1", 0)
+	assert.NoError(t, err)
+
+	// Повторный merge идемпотентен и события не порождает
+	mockPRRepo.On("GetByIDForUpdate", "pr1").Return(&schemas.PullRequest{ID: "pr1", Status: "MERGED"}, nil)
+	_, err = usecase.MergePR(context.Background(), "pr1", 0)
+	assert.NoError(t, err)
+	mockPRRepo.AssertNumberOfCalls(t, "UpdateStatus", 1)
+}
+
+func TestUsecase_MergePR_StaleIfMatch(t *testing.T) {
+	mockPRRepo := new(MockPullRequestRepository)
+	mockUserRepo, mockTeamRepo := new(MockUserRepository), new(MockTeamRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), nil)
+
+	mockPRRepo.On("GetByIDForUpdate", "pr1").Return(&schemas.PullRequest{ID: "pr1", Status: "OPEN", Version: 2}, nil)
+
+	_, err := usecase.MergePR(context.Background(), "pr1", 1)
+	assert.ErrorIs(t, err, pkgerrors.ErrPreconditionFailed)
+	mockPRRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Переназначение, пересёкшееся с merge: запись по прочитанной версии отклоняется, провайдер не уведомляется
+func TestUsecase_ReassignPR_VersionConflict(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockPRRepo := new(MockPullRequestRepository)
+	syncer := new(MockReviewerSyncer)
+	mockTeamRepo := new(MockTeamRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), syncer)
+
+	pr := &schemas.PullRequest{ID: "pr1", Status: "OPEN", AuthorID: "u1", AssignedReviewers: []string{"u2"}, Version: 4}
+	mockPRRepo.On("GetByIDForUpdate", "pr1").Return(pr, nil)
+	mockUserRepo.On("GetByID", "u2").Return(&schemas.User{ID: "u2", TeamName: "backend"}, nil)
+	mockUserRepo.On("GetActiveByTeamForShare", "backend", "u1").Return([]schemas.User{{ID: "u3"}}, nil)
+	mockPRRepo.On("UpdateReviewers", "pr1", 4, []string{"u3"}, eventOfType(schemas.EventPRReassigned)).Return(pkgerrors.ErrVersionConflict)
+
+	_, _, err := usecase.ReassignPR(context.Background(), "pr1", "u2", 4)
+	assert.ErrorIs(t, err, pkgerrors.ErrVersionConflict)
+	syncer.AssertNotCalled(t, "ReviewersChanged", mock.Anything, mock.Anything)
+}
+
+// Параллельное создание PR с тем же ID: дубликат обнаруживается при вставке, провайдер не уведомляется
+func TestUsecase_CreatePR_ConcurrentDuplicate(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockPRRepo := new(MockPullRequestRepository)
+	mockTeamRepo := new(MockTeamRepository)
+	syncer := new(MockReviewerSyncer)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo, mockTeamRepo, passTx(mockUserRepo, mockPRRepo, mockTeamRepo), syncer)
+
+	mockPRRepo.On("Exists", "pr1").Return(false, nil)
+	mockUserRepo.On("GetByID", "u1").Return(&schemas.User{ID: "u1", TeamName: "backend"}, nil)
+	mockUserRepo.On("GetActiveByTeamForShare", "backend", "u1").Return([]schemas.User{{ID: "u2"}}, nil)
+	mockTeamRepo.On("GetByName", "backend").Return(nil, nil)
+	mockPRRepo.On("Create", mock.AnythingOfType("*schemas.PullRequest"), eventOfType(schemas.EventPRCreated)).Return(pkgerrors.ErrPRExists)
+
+	_, err := usecase.CreatePR(context.Background(), "pr1", "Test", "u1")
+	assert.ErrorIs(t, err, pkgerrors.ErrPRExists)
+	syncer.AssertNotCalled(t, "ReviewersChanged", mock.Anything, mock.Anything)
+}
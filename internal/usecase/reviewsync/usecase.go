@@ -0,0 +1,173 @@
+package reviewsync
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/scm"
+)
+
+// RetryPolicy — повторы неудачных вызовов API провайдера с экспоненциальной задержкой
+type RetryPolicy struct {
+	Attempts  int           // всего попыток, включая первую
+	BaseDelay time.Duration // задержка перед второй попыткой, дальше удваивается
+	MaxDelay  time.Duration
+}
+
+// Usecase переносит назначенных ревьюверов в Git-хостинг, откуда пришёл PR.
+// Синхронизируются только PR с ID вида "github:acme/api#42"; итог пишется в PR.
+type Usecase struct {
+	clients      map[string]scm.ReviewerClient // провайдер -> клиент; провайдеры без клиента пропускаются
+	prRepo       interfaces.PullRequestRepository
+	identityRepo interfaces.SCMIdentityRepository
+	retry        RetryPolicy
+	sleep        func(time.Duration)
+	inflight     sync.WaitGroup // фоновые синхронизации ReviewersChanged
+}
+
+func NewUsecase(clients map[string]scm.ReviewerClient, prRepo interfaces.PullRequestRepository,
+	identityRepo interfaces.SCMIdentityRepository, retry RetryPolicy) *Usecase {
+	if retry.Attempts < 1 {
+		retry.Attempts = 1
+	}
+	return &Usecase{clients: clients, prRepo: prRepo, identityRepo: identityRepo, retry: retry, sleep: time.Sleep}
+}
+
+// ReviewersChanged запускает синхронизацию в фоне, чтобы повторы не задерживали ответ API;
+// синхронизация не привязана к контексту запроса, который отменяется после ответа
+func (u *Usecase) ReviewersChanged(pr *schemas.PullRequest, removed []string) {
+	if !u.Supports(pr.ID) {
+		return
+	}
+	u.inflight.Add(1)
+	go func() {
+		defer u.inflight.Done()
+		if _, err := u.Sync(context.Background(), pr.ID, removed); err != nil {
+			log.Printf("scm reviewer sync %s: %v", pr.ID, err)
+		}
+	}()
+}
+
+// Wait ждёт фоновые синхронизации, чтобы при остановке они успели записать итог в PR
+func (u *Usecase) Wait() {
+	u.inflight.Wait()
+}
+
+// Supports — есть ли клиент для провайдера PR
+func (u *Usecase) Supports(prID string) bool {
+	provider, _, _, ok := schemas.ParseSCMPRID(prID)
+	return ok && u.clients[provider] != nil
+}
+
+// Sync отправляет текущих ревьюверов PR провайдеру и снимает запрос с removed.
+// Ошибка возвращается только при сбое хранилища; сбой провайдера записывается в PR как failed.
+func (u *Usecase) Sync(ctx context.Context, prID string, removed []string) (*schemas.PullRequest, error) {
+	provider, repo, number, ok := schemas.ParseSCMPRID(prID)
+	client := u.clients[provider]
+	if !ok || client == nil {
+		return nil, pkgerrors.ErrNotFound
+	}
+	pr, err := u.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, pkgerrors.ErrNotFound
+	}
+
+	reviewers, unmapped, err := u.usernames(ctx, provider, pr.AssignedReviewers)
+	if err != nil {
+		return nil, err
+	}
+	removedNames, _, err := u.usernames(ctx, provider, removed)
+	if err != nil {
+		return nil, err
+	}
+
+	state := schemas.SCMSyncState{Status: schemas.SCMSyncPending}
+	if len(unmapped) > 0 {
+		state.Error = "no scm identity for " + strings.Join(unmapped, ", ")
+	}
+	if len(reviewers) == 0 && len(removedNames) == 0 {
+		state.Status = schemas.SCMSyncSkipped
+		return u.save(ctx, pr, state)
+	}
+	if err := u.prRepo.UpdateSCMSync(ctx, prID, state); err != nil {
+		return nil, err
+	}
+
+	var callErr error
+	for state.Attempts < u.retry.Attempts {
+		if state.Attempts > 0 {
+			u.sleep(u.backoff(state.Attempts))
+		}
+		state.Attempts++
+		callErr = client.SetReviewers(repo, number, reviewers, removedNames)
+		if callErr == nil || !retryable(callErr) {
+			break
+		}
+	}
+
+	if callErr != nil {
+		state.Status = schemas.SCMSyncFailed
+		state.Error = callErr.Error()
+		return u.save(ctx, pr, state)
+	}
+	now := time.Now()
+	state.Status = schemas.SCMSyncSynced
+	state.SyncedAt = &now
+	return u.save(ctx, pr, state)
+}
+
+// usernames переводит ID пользователей в логины провайдера; пользователи без привязки возвращаются отдельно
+func (u *Usecase) usernames(ctx context.Context, provider string, userIDs []string) ([]string, []string, error) {
+	var names, unmapped []string
+	for _, userID := range userIDs {
+		identity, err := u.identityRepo.GetByUserID(ctx, provider, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if identity == nil {
+			unmapped = append(unmapped, userID)
+			continue
+		}
+		names = append(names, identity.Username)
+	}
+	return names, unmapped, nil
+}
+
+func (u *Usecase) save(ctx context.Context, pr *schemas.PullRequest, state schemas.SCMSyncState) (*schemas.PullRequest, error) {
+	if err := u.prRepo.UpdateSCMSync(ctx, pr.ID, state); err != nil {
+		return nil, err
+	}
+	pr.SCMSyncStatus = state.Status
+	pr.SCMSyncError = state.Error
+	pr.SCMSyncAttempts = state.Attempts
+	pr.SCMSyncedAt = state.SyncedAt
+	return pr, nil
+}
+
+// backoff — задержка перед попыткой attempt+1
+func (u *Usecase) backoff(attempt int) time.Duration {
+	delay := u.retry.BaseDelay << (attempt - 1)
+	if u.retry.MaxDelay > 0 && (delay > u.retry.MaxDelay || delay <= 0) {
+		delay = u.retry.MaxDelay
+	}
+	return delay
+}
+
+// retryable — ошибки сети и временные ответы провайдера; 4xx повторять бесполезно
+func retryable(err error) bool {
+	var apiErr *scm.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	return !errors.Is(err, scm.ErrUnknownUser)
+}
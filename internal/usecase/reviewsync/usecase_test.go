@@ -0,0 +1,254 @@
+package reviewsync
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/scm"
+	"ReviewAssigner/internal/pkg/scm/scmtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock для PullRequestRepository
+type MockPullRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockPullRequestRepository) Create(ctx context.Context, pr *schemas.PullRequest, events ...*schemas.Event) error {
+	args := m.Called(pr, events)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) GetByID(ctx context.Context, id string) (*schemas.PullRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetByIDForUpdate(ctx context.Context, id string) (*schemas.PullRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetByIDs(ctx context.Context, ids []string) ([]schemas.PullRequest, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) UpdateStatus(ctx context.Context, id string, version int, status string, mergedAt *time.Time, events ...*schemas.Event) (*schemas.PullRequest, error) {
+	args := m.Called(id, version, status, mergedAt, events)
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) UpdateReviewers(ctx context.Context, id string, version int, reviewers []string, events ...*schemas.Event) error {
+	args := m.Called(id, version, reviewers, events)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) UpdateSCMSync(ctx context.Context, id string, state schemas.SCMSyncState) error {
+	args := m.Called(id, state)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) GetByReviewerID(ctx context.Context, userID string) ([]schemas.PullRequestShort, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]schemas.PullRequestShort), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetByReviewerIDs(ctx context.Context, userIDs []string) (map[string][]schemas.PullRequestShort, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).(map[string][]schemas.PullRequestShort), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) GetStats(ctx context.Context) (map[string]int, map[string]int, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(map[string]int), args.Get(1).(map[string]int), args.Error(2)
+}
+
+// Mock для SCMIdentityRepository
+type MockSCMIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockSCMIdentityRepository) Upsert(ctx context.Context, identity *schemas.SCMIdentity) error {
+	args := m.Called(identity)
+	return args.Error(0)
+}
+
+func (m *MockSCMIdentityRepository) Delete(ctx context.Context, provider, username string) (bool, error) {
+	args := m.Called(provider, username)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSCMIdentityRepository) GetByUsername(ctx context.Context, provider, username string) (*schemas.SCMIdentity, error) {
+	args := m.Called(provider, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.SCMIdentity), args.Error(1)
+}
+
+func (m *MockSCMIdentityRepository) GetByUserID(ctx context.Context, provider, userID string) (*schemas.SCMIdentity, error) {
+	args := m.Called(provider, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.SCMIdentity), args.Error(1)
+}
+
+func (m *MockSCMIdentityRepository) List(ctx context.Context, provider string) ([]schemas.SCMIdentity, error) {
+	args := m.Called(provider)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]schemas.SCMIdentity), args.Error(1)
+}
+
+const prID = "github:acme/api#42"
+
+type fixture struct {
+	api        *scmtest.API
+	prs        *MockPullRequestRepository
+	identities *MockSCMIdentityRepository
+	usecase    *Usecase
+	sleeps     []time.Duration
+}
+
+func newFixture(t *testing.T) *fixture {
+	api, srv := scmtest.NewServer("t0ken")
+	t.Cleanup(srv.Close)
+	client, err := scm.NewReviewerClient("github", srv.URL, "t0ken")
+	require.NoError(t, err)
+
+	f := &fixture{api: api, prs: new(MockPullRequestRepository), identities: new(MockSCMIdentityRepository)}
+	f.usecase = NewUsecase(map[string]scm.ReviewerClient{"github": client}, f.prs, f.identities,
+		RetryPolicy{Attempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute})
+	f.usecase.sleep = func(d time.Duration) { f.sleeps = append(f.sleeps, d) }
+	return f
+}
+
+func (f *fixture) link(userID, username string) {
+	f.identities.On("GetByUserID", "github", userID).Return(&schemas.SCMIdentity{Provider: "github", Username: username, UserID: userID}, nil)
+}
+
+func TestSync_RequestsMappedReviewers(t *testing.T) {
+	f := newFixture(t)
+	f.prs.On("GetByID", prID).Return(&schemas.PullRequest{ID: prID, AssignedReviewers: []string{"u2", "u3"}}, nil)
+	f.link("u2", "alice")
+	f.link("u3", "bob")
+	f.prs.On("UpdateSCMSync", prID, mock.Anything).Return(nil)
+
+	pr, err := f.usecase.Sync(context.Background(), prID, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, schemas.SCMSyncSynced, pr.SCMSyncStatus)
+	assert.Equal(t, 1, pr.SCMSyncAttempts)
+	assert.NotNil(t, pr.SCMSyncedAt)
+	assert.Equal(t, []string{"alice", "bob"}, f.api.Reviewers("acme/api", 42))
+}
+
+func TestSync_ReassignRemovesOldReviewer(t *testing.T) {
+	f := newFixture(t)
+	f.prs.On("GetByID", prID).Return(&schemas.PullRequest{ID: prID, AssignedReviewers: []string{"u2", "u3"}}, nil)
+	f.link("u2", "alice")
+	f.link("u3", "bob")
+	f.link("u4", "carol")
+	f.prs.On("UpdateSCMSync", prID, mock.Anything).Return(nil)
+
+	_, err := f.usecase.Sync(context.Background(), prID, []string{"u4"})
+	require.NoError(t, err)
+	_, err = f.usecase.Sync(context.Background(), prID, []string{"u4"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"alice", "bob"}, f.api.Reviewers("acme/api", 42))
+}
+
+func TestSync_RetriesTemporaryFailuresWithBackoff(t *testing.T) {
+	f := newFixture(t)
+	f.prs.On("GetByID", prID).Return(&schemas.PullRequest{ID: prID, AssignedReviewers: []string{"u2"}}, nil)
+	f.link("u2", "alice")
+	f.prs.On("UpdateSCMSync", prID, mock.Anything).Return(nil)
+	f.api.FailNext(http.StatusBadGateway, http.StatusTooManyRequests)
+
+	pr, err := f.usecase.Sync(context.Background(), prID, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, schemas.SCMSyncSynced, pr.SCMSyncStatus)
+	assert.Equal(t, 3, pr.SCMSyncAttempts)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, f.sleeps)
+}
+
+func TestSync_RecordsFailure(t *testing.T) {
+	f := newFixture(t)
+	f.prs.On("GetByID", prID).Return(&schemas.PullRequest{ID: prID, AssignedReviewers: []string{"u2"}}, nil)
+	f.link("u2", "alice")
+	f.prs.On("UpdateSCMSync", prID, schemas.SCMSyncState{Status: schemas.SCMSyncPending}).Return(nil).Once()
+	f.prs.On("UpdateSCMSync", prID, mock.MatchedBy(func(s schemas.SCMSyncState) bool {
+		return s.Status == schemas.SCMSyncFailed && s.Attempts == 3
+	})).Return(nil).Once()
+	f.api.FailNext(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+
+	pr, err := f.usecase.Sync(context.Background(), prID, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, schemas.SCMSyncFailed, pr.SCMSyncStatus)
+	assert.Contains(t, pr.SCMSyncError, "500")
+	f.prs.AssertExpectations(t)
+}
+
+func TestSync_ClientErrorIsNotRetried(t *testing.T) {
+	f := newFixture(t)
+	f.prs.On("GetByID", prID).Return(&schemas.PullRequest{ID: prID, AssignedReviewers: []string{"u2"}}, nil)
+	f.link("u2", "alice")
+	f.prs.On("UpdateSCMSync", prID, mock.Anything).Return(nil)
+	f.api.FailNext(http.StatusUnprocessableEntity)
+
+	pr, err := f.usecase.Sync(context.Background(), prID, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, schemas.SCMSyncFailed, pr.SCMSyncStatus)
+	assert.Equal(t, 1, f.api.Calls())
+	assert.Empty(t, f.sleeps)
+}
+
+func TestSync_SkipsUnmappedReviewers(t *testing.T) {
+	f := newFixture(t)
+	f.prs.On("GetByID", prID).Return(&schemas.PullRequest{ID: prID, AssignedReviewers: []string{"u2"}}, nil)
+	f.identities.On("GetByUserID", "github", "u2").Return(nil, nil)
+	f.prs.On("UpdateSCMSync", prID, mock.Anything).Return(nil)
+
+	pr, err := f.usecase.Sync(context.Background(), prID, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, schemas.SCMSyncSkipped, pr.SCMSyncStatus)
+	assert.Contains(t, pr.SCMSyncError, "u2")
+	assert.Equal(t, 0, f.api.Calls())
+}
+
+func TestSync_ManualPRNotSupported(t *testing.T) {
+	f := newFixture(t)
+
+	assert.False(t, f.usecase.Supports("pr-1001"))
+	assert.False(t, f.usecase.Supports("gitlab:group/project#1"))
+	_, err := f.usecase.Sync(context.Background(), "pr-1001", nil)
+	assert.Equal(t, pkgerrors.ErrNotFound, err)
+}
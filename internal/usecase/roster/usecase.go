@@ -0,0 +1,160 @@
+package roster
+
+import (
+	"context"
+	"fmt"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+)
+
+type Usecase struct {
+	teamRepo interfaces.TeamRepository
+	userRepo interfaces.UserRepository
+}
+
+func NewUsecase(teamRepo interfaces.TeamRepository, userRepo interfaces.UserRepository) *Usecase {
+	return &Usecase{teamRepo: teamRepo, userRepo: userRepo}
+}
+
+// Import проверяет ростер, считает diff с текущим состоянием и, если dryRun == false, применяет его.
+// Пользователи и настройки, не указанные в ростере, не затрагиваются.
+func (u *Usecase) Import(ctx context.Context, roster *schemas.Roster, dryRun bool) (*schemas.RosterDiff, error) {
+	return u.reconcile(ctx, roster, false, !dryRun)
+}
+
+// Sync приводит БД в точное соответствие с конфигурацией: пользователи, которых нет в конфиге,
+// деактивируются, а незаданные настройки сбрасываются к значениям по умолчанию.
+// При apply == false только возвращает расхождения (plan). Повторный apply ничего не меняет.
+func (u *Usecase) Sync(ctx context.Context, config *schemas.Roster, apply bool) (*schemas.RosterDiff, error) {
+	return u.reconcile(ctx, config, true, apply)
+}
+
+func (u *Usecase) reconcile(ctx context.Context, desired *schemas.Roster, authoritative, apply bool) (*schemas.RosterDiff, error) {
+	if err := Validate(desired); err != nil {
+		return nil, err
+	}
+	teams, err := u.teamRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := u.userRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := Diff(teams, users, desired, authoritative)
+	if !apply || !diff.HasChanges() {
+		return diff, nil
+	}
+	if err := u.teamRepo.ApplyRoster(ctx, diff); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+func (u *Usecase) Export(ctx context.Context) (*schemas.Roster, error) {
+	teams, err := u.teamRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &schemas.Roster{Teams: teams}, nil
+}
+
+// Validate проверяет обязательные поля и то, что пользователь указан только в одной команде
+func Validate(roster *schemas.Roster) error {
+	teams := make(map[string]bool, len(roster.Teams))
+	users := make(map[string]string)
+	for _, team := range roster.Teams {
+		if team.Name == "" {
+			return errors.ErrInvalidRoster.WithMessage("team_name is required")
+		}
+		if teams[team.Name] {
+			return errors.ErrInvalidRoster.WithMessage(fmt.Sprintf("team %q is listed twice", team.Name))
+		}
+		teams[team.Name] = true
+		if team.ReviewersCount < 0 {
+			return errors.ErrInvalidRoster.WithMessage(fmt.Sprintf("team %q: reviewers_count must be positive", team.Name))
+		}
+
+		for _, m := range team.Members {
+			if m.ID == "" {
+				return errors.ErrInvalidRoster.WithMessage(fmt.Sprintf("team %q: user_id is required", team.Name))
+			}
+			if m.Username == "" {
+				return errors.ErrInvalidRoster.WithMessage(fmt.Sprintf("user %q: username is required", m.ID))
+			}
+			if prev, ok := users[m.ID]; ok {
+				return errors.ErrInvalidRoster.WithMessage(fmt.Sprintf("user %q is listed in teams %q and %q", m.ID, prev, team.Name))
+			}
+			users[m.ID] = team.Name
+		}
+	}
+	return nil
+}
+
+// Diff сравнивает текущее состояние с желаемым.
+// В режиме authoritative активные пользователи, отсутствующие в desired, попадают в UsersDeactivated,
+// а reviewers_count = 0 трактуется как значение по умолчанию, а не как «не задано».
+func Diff(teams []schemas.Team, users []schemas.User, desired *schemas.Roster, authoritative bool) *schemas.RosterDiff {
+	diff := &schemas.RosterDiff{
+		TeamsCreated:     []string{},
+		SettingsChanged:  []schemas.TeamSettings{},
+		UsersCreated:     []schemas.User{},
+		UsersMoved:       []schemas.UserMove{},
+		UsersDeactivated: []schemas.User{},
+		UsersUpdated:     []schemas.User{},
+	}
+
+	existingTeams := make(map[string]schemas.Team, len(teams))
+	for _, team := range teams {
+		existingTeams[team.Name] = team
+	}
+	existingUsers := make(map[string]schemas.User, len(users))
+	for _, user := range users {
+		existingUsers[user.ID] = user
+	}
+
+	listed := make(map[string]bool)
+	for _, team := range desired.Teams {
+		reviewersCount := team.ReviewersCount
+		if reviewersCount == 0 && authoritative {
+			reviewersCount = schemas.DefaultReviewersCount
+		}
+		old, exists := existingTeams[team.Name]
+		if !exists {
+			diff.TeamsCreated = append(diff.TeamsCreated, team.Name)
+			old.ReviewersCount = schemas.DefaultReviewersCount
+		}
+		if reviewersCount != 0 && reviewersCount != old.ReviewersCount {
+			diff.SettingsChanged = append(diff.SettingsChanged, schemas.TeamSettings{TeamName: team.Name, ReviewersCount: reviewersCount})
+		}
+
+		for _, m := range team.Members {
+			m.TeamName = team.Name
+			listed[m.ID] = true
+			old, exists := existingUsers[m.ID]
+			switch {
+			case !exists:
+				diff.UsersCreated = append(diff.UsersCreated, m)
+			case old.TeamName != m.TeamName:
+				diff.UsersMoved = append(diff.UsersMoved, schemas.UserMove{User: m, FromTeam: old.TeamName})
+			case old.IsActive && !m.IsActive:
+				diff.UsersDeactivated = append(diff.UsersDeactivated, m)
+			case old != m:
+				diff.UsersUpdated = append(diff.UsersUpdated, m)
+			}
+		}
+	}
+
+	if authoritative {
+		for _, user := range users {
+			if !listed[user.ID] && user.IsActive {
+				user.IsActive = false
+				diff.UsersDeactivated = append(diff.UsersDeactivated, user)
+			}
+		}
+	}
+	return diff
+}
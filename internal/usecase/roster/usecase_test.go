@@ -0,0 +1,224 @@
+package roster
+
+import (
+	"context"
+	"testing"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock для TeamRepository
+type MockTeamRepository struct {
+	mock.Mock
+}
+
+func (m *MockTeamRepository) Create(ctx context.Context, team *schemas.Team) error {
+	args := m.Called(team)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) GetByName(ctx context.Context, name string) (*schemas.Team, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) GetByNames(ctx context.Context, names []string) ([]schemas.Team, error) {
+	args := m.Called(names)
+	return args.Get(0).([]schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) Exists(ctx context.Context, name string) (bool, error) {
+	args := m.Called(name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTeamRepository) List(ctx context.Context) ([]schemas.Team, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) ApplyRoster(ctx context.Context, diff *schemas.RosterDiff) error {
+	args := m.Called(diff)
+	return args.Error(0)
+}
+
+// Mock для UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	args := m.Called(userID, isActive, events)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context) ([]schemas.User, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func currentTeams() []schemas.Team {
+	return []schemas.Team{
+		{Name: "backend", ReviewersCount: 2, Members: currentUsers()},
+	}
+}
+
+func currentUsers() []schemas.User {
+	return []schemas.User{
+		{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+	}
+}
+
+func TestUsecase_Import_DryRun(t *testing.T) {
+	mockRepo := &MockTeamRepository{}
+	mockUserRepo := &MockUserRepository{}
+	usecase := NewUsecase(mockRepo, mockUserRepo)
+
+	roster := &schemas.Roster{Teams: []schemas.Team{
+		{Name: "backend", Members: []schemas.User{
+			{ID: "u1", Username: "Alice", IsActive: false},
+		}},
+		{Name: "frontend", Members: []schemas.User{
+			{ID: "u2", Username: "Bob", IsActive: true},
+			{ID: "u3", Username: "Carol", IsActive: true},
+		}},
+	}}
+	mockRepo.On("List").Return(currentTeams(), nil)
+	mockUserRepo.On("List").Return(currentUsers(), nil)
+
+	diff, err := usecase.Import(context.Background(), roster, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"frontend"}, diff.TeamsCreated)
+	assert.Equal(t, []schemas.User{{ID: "u3", Username: "Carol", TeamName: "frontend", IsActive: true}}, diff.UsersCreated)
+	assert.Equal(t, []schemas.UserMove{{User: schemas.User{ID: "u2", Username: "Bob", TeamName: "frontend", IsActive: true}, FromTeam: "backend"}}, diff.UsersMoved)
+	assert.Equal(t, []schemas.User{{ID: "u1", Username: "Alice", TeamName: "backend", IsActive: false}}, diff.UsersDeactivated)
+	assert.Empty(t, diff.UsersUpdated)
+	mockRepo.AssertNotCalled(t, "ApplyRoster", mock.Anything)
+}
+
+func TestUsecase_Import_Apply(t *testing.T) {
+	mockRepo := &MockTeamRepository{}
+	mockUserRepo := &MockUserRepository{}
+	usecase := NewUsecase(mockRepo, mockUserRepo)
+
+	roster := &schemas.Roster{Teams: []schemas.Team{
+		{Name: "backend", Members: []schemas.User{{ID: "u2", Username: "Robert", IsActive: true}}},
+	}}
+	mockRepo.On("List").Return(currentTeams(), nil)
+	mockUserRepo.On("List").Return(currentUsers(), nil)
+	mockRepo.On("ApplyRoster", mock.AnythingOfType("*schemas.RosterDiff")).Return(nil)
+
+	diff, err := usecase.Import(context.Background(), roster, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []schemas.User{{ID: "u2", Username: "Robert", TeamName: "backend", IsActive: true}}, diff.UsersUpdated)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUsecase_Import_NoChanges(t *testing.T) {
+	mockRepo := &MockTeamRepository{}
+	mockUserRepo := &MockUserRepository{}
+	usecase := NewUsecase(mockRepo, mockUserRepo)
+
+	mockRepo.On("List").Return(currentTeams(), nil)
+	mockUserRepo.On("List").Return(currentUsers(), nil)
+
+	diff, err := usecase.Import(context.Background(), &schemas.Roster{Teams: currentTeams()}, false)
+	assert.NoError(t, err)
+	assert.False(t, diff.HasChanges())
+	mockRepo.AssertNotCalled(t, "ApplyRoster", mock.Anything)
+}
+
+func TestUsecase_Import_DuplicateUser(t *testing.T) {
+	mockRepo := &MockTeamRepository{}
+	mockUserRepo := &MockUserRepository{}
+	usecase := NewUsecase(mockRepo, mockUserRepo)
+
+	roster := &schemas.Roster{Teams: []schemas.Team{
+		{Name: "backend", Members: []schemas.User{{ID: "u1", Username: "Alice"}}},
+		{Name: "frontend", Members: []schemas.User{{ID: "u1", Username: "Alice"}}},
+	}}
+
+	_, err := usecase.Import(context.Background(), roster, true)
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidRoster)
+	mockRepo.AssertNotCalled(t, "List")
+}
+
+func TestUsecase_Export(t *testing.T) {
+	mockRepo := &MockTeamRepository{}
+	mockUserRepo := &MockUserRepository{}
+	usecase := NewUsecase(mockRepo, mockUserRepo)
+
+	mockRepo.On("List").Return(currentTeams(), nil)
+	mockUserRepo.On("List").Return(currentUsers(), nil)
+
+	result, err := usecase.Export(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, currentTeams(), result.Teams)
+}
+
+func TestUsecase_Sync_Plan(t *testing.T) {
+	mockRepo := &MockTeamRepository{}
+	mockUserRepo := &MockUserRepository{}
+	usecase := NewUsecase(mockRepo, mockUserRepo)
+
+	config := &schemas.Roster{Teams: []schemas.Team{
+		{Name: "backend", ReviewersCount: 1, Members: []schemas.User{{ID: "u1", Username: "Alice", IsActive: true}}},
+	}}
+	mockRepo.On("List").Return(currentTeams(), nil)
+	mockUserRepo.On("List").Return(currentUsers(), nil)
+
+	diff, err := usecase.Sync(context.Background(), config, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []schemas.TeamSettings{{TeamName: "backend", ReviewersCount: 1}}, diff.SettingsChanged)
+	assert.Equal(t, []schemas.User{{ID: "u2", Username: "Bob", TeamName: "backend", IsActive: false}}, diff.UsersDeactivated)
+	mockRepo.AssertNotCalled(t, "ApplyRoster", mock.Anything)
+}
+
+func TestUsecase_Sync_InSync(t *testing.T) {
+	mockRepo := &MockTeamRepository{}
+	mockUserRepo := &MockUserRepository{}
+	usecase := NewUsecase(mockRepo, mockUserRepo)
+
+	// reviewers_count не задан — считается значением по умолчанию
+	config := &schemas.Roster{Teams: []schemas.Team{{Name: "backend", Members: currentUsers()}}}
+	mockRepo.On("List").Return(currentTeams(), nil)
+	mockUserRepo.On("List").Return(currentUsers(), nil)
+
+	diff, err := usecase.Sync(context.Background(), config, true)
+	assert.NoError(t, err)
+	assert.False(t, diff.HasChanges())
+	mockRepo.AssertNotCalled(t, "ApplyRoster", mock.Anything)
+}
@@ -0,0 +1,176 @@
+package scmhook
+
+import (
+	"context"
+
+	stderrors "errors"
+	"net/http"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/scm"
+)
+
+// PRService — операции с PR, на которые отображаются события провайдера (реализация — pr.Usecase)
+type PRService interface {
+	CreatePR(ctx context.Context, prID, name, authorID string) (*schemas.PullRequest, error)
+	MergePR(ctx context.Context, prID string, version int) (*schemas.PullRequest, error)
+	ClosePR(ctx context.Context, prID string) (*schemas.PullRequest, error)
+	ReopenPR(ctx context.Context, prID string) (*schemas.PullRequest, error)
+}
+
+// Usecase принимает вебхуки Git-хостингов и зеркалит PR в ReviewAssigner.
+// Обработка идемпотентна: повторная доставка распознаётся по ID, а сами операции безопасно повторять.
+type Usecase struct {
+	registry     *scm.Registry
+	secrets      map[string]string // провайдер -> секрет вебхука; провайдеры без секрета отключены
+	prs          PRService
+	identityRepo interfaces.SCMIdentityRepository
+	deliveryRepo interfaces.SCMDeliveryRepository
+	userRepo     interfaces.UserRepository
+}
+
+func NewUsecase(registry *scm.Registry, secrets map[string]string, prs PRService, identityRepo interfaces.SCMIdentityRepository,
+	deliveryRepo interfaces.SCMDeliveryRepository, userRepo interfaces.UserRepository) *Usecase {
+	return &Usecase{registry: registry, secrets: secrets, prs: prs, identityRepo: identityRepo, deliveryRepo: deliveryRepo, userRepo: userRepo}
+}
+
+// Handle проверяет подпись доставки и применяет событие
+func (u *Usecase) Handle(ctx context.Context, provider string, header http.Header, body []byte) (*schemas.SCMEventResult, error) {
+	adapter, ok := u.registry.Get(provider)
+	secret := u.secrets[provider]
+	if !ok || secret == "" {
+		return nil, errors.ErrNotFound
+	}
+	if err := adapter.Verify(header, body, secret); err != nil {
+		return nil, errors.ErrInvalidSignature
+	}
+
+	event, err := adapter.Parse(header, body)
+	if err != nil {
+		return nil, errors.ErrInvalidPayload
+	}
+	if event == nil {
+		return &schemas.SCMEventResult{Status: schemas.SCMEventIgnored, Reason: "event is not a tracked pull request action"}, nil
+	}
+
+	processed, err := u.deliveryRepo.IsProcessed(ctx, provider, event.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if processed {
+		return &schemas.SCMEventResult{Status: schemas.SCMEventDuplicate, Action: event.Action, PRID: event.PRID()}, nil
+	}
+
+	result, err := u.apply(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	// Доставка помечается только после успешной обработки, чтобы повтор провайдера после ошибки сработал
+	if err := u.deliveryRepo.MarkProcessed(ctx, provider, event.DeliveryID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (u *Usecase) apply(ctx context.Context, event *schemas.SCMEvent) (*schemas.SCMEventResult, error) {
+	prID := event.PRID()
+	result := &schemas.SCMEventResult{Status: schemas.SCMEventProcessed, Action: event.Action, PRID: prID}
+
+	var err error
+	switch event.Action {
+	case schemas.SCMActionOpened:
+		return u.open(ctx, event, result)
+	case schemas.SCMActionReopened:
+		_, err = u.prs.ReopenPR(ctx, prID)
+		if stderrors.Is(err, errors.ErrNotFound) {
+			// PR открыли до подключения вебхука — заводим его
+			return u.open(ctx, event, result)
+		}
+	case schemas.SCMActionClosed:
+		_, err = u.prs.ClosePR(ctx, prID)
+	case schemas.SCMActionMerged:
+		_, err = u.prs.MergePR(ctx, prID, 0)
+	}
+
+	switch {
+	case stderrors.Is(err, errors.ErrNotFound):
+		return ignored(result, "pull request is not tracked"), nil
+	case stderrors.Is(err, errors.ErrPRMerged):
+		return ignored(result, "pull request is already merged"), nil
+	case err != nil:
+		return nil, err
+	}
+	return result, nil
+}
+
+func (u *Usecase) open(ctx context.Context, event *schemas.SCMEvent, result *schemas.SCMEventResult) (*schemas.SCMEventResult, error) {
+	authorID, err := u.resolveUser(ctx, event.Provider, event.AuthorUsername)
+	if err != nil {
+		return nil, err
+	}
+	if authorID == "" {
+		return ignored(result, "author "+event.AuthorUsername+" is not mapped to a user"), nil
+	}
+
+	// Уже заведённый PR — повтор события, а не ошибка
+	_, err = u.prs.CreatePR(ctx, event.PRID(), event.Title, authorID)
+	if err != nil && !stderrors.Is(err, errors.ErrPRExists) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// resolveUser находит пользователя только по явной привязке логина провайдера (SCMIdentity).
+// Совпадению логина с user_id не доверяем: кто угодно может зарегистрировать у провайдера чужой user_id
+// и открывать PR от имени этого пользователя.
+func (u *Usecase) resolveUser(ctx context.Context, provider, username string) (string, error) {
+	if username == "" {
+		return "", nil
+	}
+	identity, err := u.identityRepo.GetByUsername(ctx, provider, username)
+	if err != nil {
+		return "", err
+	}
+	if identity == nil {
+		return "", nil
+	}
+	return identity.UserID, nil
+}
+
+// LinkIdentity привязывает логин у провайдера к пользователю
+func (u *Usecase) LinkIdentity(ctx context.Context, identity *schemas.SCMIdentity) error {
+	if _, ok := u.registry.Get(identity.Provider); !ok {
+		return errors.ErrNotFound
+	}
+	user, err := u.userRepo.GetByID(ctx, identity.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.ErrNotFound
+	}
+	return u.identityRepo.Upsert(ctx, identity)
+}
+
+func (u *Usecase) UnlinkIdentity(ctx context.Context, provider, username string) error {
+	deleted, err := u.identityRepo.Delete(ctx, provider, username)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+func (u *Usecase) ListIdentities(ctx context.Context, provider string) ([]schemas.SCMIdentity, error) {
+	return u.identityRepo.List(ctx, provider)
+}
+
+func ignored(result *schemas.SCMEventResult, reason string) *schemas.SCMEventResult {
+	result.Status = schemas.SCMEventIgnored
+	result.Reason = reason
+	return result
+}
@@ -0,0 +1,304 @@
+package scmhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/scm"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock для PRService
+type MockPRService struct {
+	mock.Mock
+}
+
+func (m *MockPRService) CreatePR(ctx context.Context, prID, name, authorID string) (*schemas.PullRequest, error) {
+	args := m.Called(prID, name, authorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPRService) MergePR(ctx context.Context, prID string, version int) (*schemas.PullRequest, error) {
+	args := m.Called(prID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPRService) ClosePR(ctx context.Context, prID string) (*schemas.PullRequest, error) {
+	args := m.Called(prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPRService) ReopenPR(ctx context.Context, prID string) (*schemas.PullRequest, error) {
+	args := m.Called(prID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+// Mock для SCMIdentityRepository
+type MockSCMIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockSCMIdentityRepository) Upsert(ctx context.Context, identity *schemas.SCMIdentity) error {
+	args := m.Called(identity)
+	return args.Error(0)
+}
+
+func (m *MockSCMIdentityRepository) Delete(ctx context.Context, provider, username string) (bool, error) {
+	args := m.Called(provider, username)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSCMIdentityRepository) GetByUsername(ctx context.Context, provider, username string) (*schemas.SCMIdentity, error) {
+	args := m.Called(provider, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.SCMIdentity), args.Error(1)
+}
+
+func (m *MockSCMIdentityRepository) GetByUserID(ctx context.Context, provider, userID string) (*schemas.SCMIdentity, error) {
+	args := m.Called(provider, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.SCMIdentity), args.Error(1)
+}
+
+func (m *MockSCMIdentityRepository) List(ctx context.Context, provider string) ([]schemas.SCMIdentity, error) {
+	args := m.Called(provider)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]schemas.SCMIdentity), args.Error(1)
+}
+
+// Mock для SCMDeliveryRepository
+type MockSCMDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockSCMDeliveryRepository) IsProcessed(ctx context.Context, provider, deliveryID string) (bool, error) {
+	args := m.Called(provider, deliveryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSCMDeliveryRepository) MarkProcessed(ctx context.Context, provider, deliveryID string) error {
+	args := m.Called(provider, deliveryID)
+	return args.Error(0)
+}
+
+// Mock для UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	args := m.Called(userID, isActive, events)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context) ([]schemas.User, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+const secret = "s3cret"
+
+func newTestUsecase() (*Usecase, *MockPRService, *MockSCMIdentityRepository, *MockSCMDeliveryRepository, *MockUserRepository) {
+	prs := new(MockPRService)
+	identityRepo := new(MockSCMIdentityRepository)
+	deliveryRepo := new(MockSCMDeliveryRepository)
+	userRepo := new(MockUserRepository)
+	u := NewUsecase(scm.DefaultRegistry(), map[string]string{"github": secret}, prs, identityRepo, deliveryRepo, userRepo)
+	return u, prs, identityRepo, deliveryRepo, userRepo
+}
+
+// githubDelivery собирает заголовки доставки GitHub с корректной подписью
+func githubDelivery(delivery string, body []byte) http.Header {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	header := http.Header{}
+	header.Set("X-GitHub-Event", "pull_request")
+	header.Set("X-GitHub-Delivery", delivery)
+	header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func githubPayload(action string, merged bool) []byte {
+	m := "false"
+	if merged {
+		m = "true"
+	}
+	return []byte(`{"action":"` + action + `","pull_request":{"number":42,"title":"Add search","merged":` + m +
+		`,"user":{"login":"octo"}},"repository":{"full_name":"acme/api"}}`)
+}
+
+func TestHandle_OpenedCreatesPR(t *testing.T) {
+	u, prs, identityRepo, deliveryRepo, _ := newTestUsecase()
+	body := githubPayload("opened", false)
+
+	deliveryRepo.On("IsProcessed", "github", "d1").Return(false, nil)
+	identityRepo.On("GetByUsername", "github", "octo").Return(&schemas.SCMIdentity{Provider: "github", Username: "octo", UserID: "u1"}, nil)
+	prs.On("CreatePR", "github:acme/api#42", "Add search", "u1").Return(&schemas.PullRequest{ID: "github:acme/api#42"}, nil)
+	deliveryRepo.On("MarkProcessed", "github", "d1").Return(nil)
+
+	result, err := u.Handle(context.Background(), "github", githubDelivery("d1", body), body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, schemas.SCMEventProcessed, result.Status)
+	assert.Equal(t, schemas.SCMActionOpened, result.Action)
+	assert.Equal(t, "github:acme/api#42", result.PRID)
+	prs.AssertExpectations(t)
+	deliveryRepo.AssertExpectations(t)
+}
+
+func TestHandle_ClosedMergedMergesPR(t *testing.T) {
+	u, prs, _, deliveryRepo, _ := newTestUsecase()
+	body := githubPayload("closed", true)
+
+	deliveryRepo.On("IsProcessed", "github", "d2").Return(false, nil)
+	prs.On("MergePR", "github:acme/api#42", 0).Return(&schemas.PullRequest{Status: "MERGED"}, nil)
+	deliveryRepo.On("MarkProcessed", "github", "d2").Return(nil)
+
+	result, err := u.Handle(context.Background(), "github", githubDelivery("d2", body), body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, schemas.SCMEventProcessed, result.Status)
+	assert.Equal(t, schemas.SCMActionMerged, result.Action)
+	prs.AssertNotCalled(t, "ClosePR", mock.Anything)
+}
+
+func TestHandle_DuplicateDelivery(t *testing.T) {
+	u, prs, _, deliveryRepo, _ := newTestUsecase()
+	body := githubPayload("opened", false)
+
+	deliveryRepo.On("IsProcessed", "github", "d1").Return(true, nil)
+
+	result, err := u.Handle(context.Background(), "github", githubDelivery("d1", body), body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, schemas.SCMEventDuplicate, result.Status)
+	prs.AssertNotCalled(t, "CreatePR", mock.Anything, mock.Anything, mock.Anything)
+	deliveryRepo.AssertNotCalled(t, "MarkProcessed", mock.Anything, mock.Anything)
+}
+
+func TestHandle_InvalidSignature(t *testing.T) {
+	u, _, _, deliveryRepo, _ := newTestUsecase()
+	body := githubPayload("opened", false)
+	header := githubDelivery("d1", body)
+
+	_, err := u.Handle(context.Background(), "github", header, []byte(`{"action":"opened"}`))
+
+	assert.Equal(t, pkgerrors.ErrInvalidSignature, err)
+	deliveryRepo.AssertNotCalled(t, "IsProcessed", mock.Anything, mock.Anything)
+}
+
+func TestHandle_ProviderWithoutSecret(t *testing.T) {
+	u, _, _, _, _ := newTestUsecase()
+
+	_, err := u.Handle(context.Background(), "gitlab", http.Header{}, []byte(`{}`))
+
+	assert.Equal(t, pkgerrors.ErrNotFound, err)
+}
+
+func TestHandle_UnmappedAuthorIgnored(t *testing.T) {
+	u, prs, identityRepo, deliveryRepo, userRepo := newTestUsecase()
+	body := githubPayload("opened", false)
+
+	deliveryRepo.On("IsProcessed", "github", "d3").Return(false, nil)
+	identityRepo.On("GetByUsername", "github", "octo").Return(nil, nil)
+	deliveryRepo.On("MarkProcessed", "github", "d3").Return(nil)
+
+	result, err := u.Handle(context.Background(), "github", githubDelivery("d3", body), body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, schemas.SCMEventIgnored, result.Status)
+	prs.AssertNotCalled(t, "CreatePR", mock.Anything, mock.Anything, mock.Anything)
+	// Логин без привязки не сопоставляется с одноимённым user_id, даже если такой пользователь есть
+	userRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+}
+
+func TestHandle_ReopenUnknownPRCreatesIt(t *testing.T) {
+	u, prs, identityRepo, deliveryRepo, _ := newTestUsecase()
+	body := githubPayload("reopened", false)
+
+	deliveryRepo.On("IsProcessed", "github", "d4").Return(false, nil)
+	prs.On("ReopenPR", "github:acme/api#42").Return(nil, pkgerrors.ErrNotFound)
+	identityRepo.On("GetByUsername", "github", "octo").Return(&schemas.SCMIdentity{Provider: "github", Username: "octo", UserID: "u1"}, nil)
+	prs.On("CreatePR", "github:acme/api#42", "Add search", "u1").Return(&schemas.PullRequest{}, nil)
+	deliveryRepo.On("MarkProcessed", "github", "d4").Return(nil)
+
+	result, err := u.Handle(context.Background(), "github", githubDelivery("d4", body), body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, schemas.SCMEventProcessed, result.Status)
+	prs.AssertExpectations(t)
+}
+
+func TestHandle_FailedEventNotMarked(t *testing.T) {
+	u, prs, _, deliveryRepo, _ := newTestUsecase()
+	body := githubPayload("closed", false)
+
+	deliveryRepo.On("IsProcessed", "github", "d5").Return(false, nil)
+	prs.On("ClosePR", "github:acme/api#42").Return(nil, assert.AnError)
+
+	_, err := u.Handle(context.Background(), "github", githubDelivery("d5", body), body)
+
+	assert.Equal(t, assert.AnError, err)
+	deliveryRepo.AssertNotCalled(t, "MarkProcessed", mock.Anything, mock.Anything)
+}
+
+func TestLinkIdentity_UnknownUser(t *testing.T) {
+	u, _, identityRepo, _, userRepo := newTestUsecase()
+	userRepo.On("GetByID", "ghost").Return(nil, nil)
+
+	err := u.LinkIdentity(context.Background(), &schemas.SCMIdentity{Provider: "github", Username: "octo", UserID: "ghost"})
+
+	assert.Equal(t, pkgerrors.ErrNotFound, err)
+	identityRepo.AssertNotCalled(t, "Upsert", mock.Anything)
+}
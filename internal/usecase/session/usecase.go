@@ -0,0 +1,165 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/jwt"
+)
+
+// Usecase выдаёт пары access/refresh токенов, ротирует refresh-токены и ведёт список отзыва
+type Usecase struct {
+	accountRepo interfaces.AccountRepository
+	tokenRepo   interfaces.TokenRepository
+	tokens      *jwt.Manager
+	refreshTTL  time.Duration
+}
+
+func NewUsecase(accountRepo interfaces.AccountRepository, tokenRepo interfaces.TokenRepository, tokens *jwt.Manager, refreshTTL time.Duration) *Usecase {
+	return &Usecase{accountRepo: accountRepo, tokenRepo: tokenRepo, tokens: tokens, refreshTTL: refreshTTL}
+}
+
+// Issue выдаёт новую пару токенов (новая цепочка refresh-токенов)
+func (u *Usecase) Issue(ctx context.Context, account *schemas.Account) (*schemas.TokenPair, error) {
+	return u.issue(ctx, account, randomToken())
+}
+
+// Refresh обменивает refresh-токен на новую пару. Старый refresh-токен отзывается;
+// его повторное использование считается утечкой и отзывает всю цепочку.
+func (u *Usecase) Refresh(ctx context.Context, refreshToken string) (*schemas.TokenPair, error) {
+	stored, err := u.tokenRepo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.ExpiresAt.Before(time.Now()) {
+		return nil, errors.ErrInvalidToken
+	}
+
+	active, err := u.tokenRepo.RevokeRefreshToken(ctx, stored.TokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		if err := u.tokenRepo.RevokeRefreshFamily(ctx, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, errors.ErrInvalidToken
+	}
+
+	account, err := u.accountRepo.GetByLogin(ctx, stored.Login)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, errors.ErrInvalidToken
+	}
+	return u.issue(ctx, account, stored.FamilyID)
+}
+
+// Logout отзывает текущий access-токен и, если передан, цепочку refresh-токена
+func (u *Usecase) Logout(ctx context.Context, claims *jwt.Claims, refreshToken string) error {
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := u.tokenRepo.RevokeAccessToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return err
+		}
+	}
+	if refreshToken == "" {
+		return nil
+	}
+	stored, err := u.tokenRepo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	// Чужой refresh-токен выйти не позволяет
+	if stored == nil || stored.Login != claims.Login {
+		return nil
+	}
+	return u.tokenRepo.RevokeRefreshFamily(ctx, stored.FamilyID)
+}
+
+// RevokeAll делает недействительными все выданные учётке токены (например, при увольнении)
+func (u *Usecase) RevokeAll(ctx context.Context, login string) error {
+	account, err := u.accountRepo.GetByLogin(ctx, login)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return errors.ErrNotFound
+	}
+	if err := u.tokenRepo.SetCutoff(ctx, login, time.Now()); err != nil {
+		return err
+	}
+	return u.tokenRepo.RevokeRefreshTokensByLogin(ctx, login)
+}
+
+// CheckAccess вызывается middleware для каждого запроса с уже проверенной подписью
+func (u *Usecase) CheckAccess(ctx context.Context, claims *jwt.Claims) error {
+	if claims.ID != "" {
+		revoked, err := u.tokenRepo.IsAccessTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return errors.ErrTokenRevoked
+		}
+	}
+	if claims.Login != "" {
+		cutoff, err := u.tokenRepo.GetCutoff(ctx, claims.Login)
+		if err != nil {
+			return err
+		}
+		// iat хранится с точностью до секунды
+		if cutoff != nil && (claims.IssuedAt == nil || claims.IssuedAt.Unix() <= cutoff.Unix()) {
+			return errors.ErrTokenRevoked
+		}
+	}
+	return nil
+}
+
+func (u *Usecase) issue(ctx context.Context, account *schemas.Account, familyID string) (*schemas.TokenPair, error) {
+	access, err := u.tokens.GenerateToken(account.Subject(), account.Login, account.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := randomToken()
+	err = u.tokenRepo.CreateRefreshToken(ctx, &schemas.RefreshToken{
+		TokenHash: hashToken(refresh),
+		Login:     account.Login,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(u.refreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemas.TokenPair{
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(u.tokens.TTL().Seconds()),
+		RefreshToken:     refresh,
+		RefreshExpiresIn: int(u.refreshTTL.Seconds()),
+		Role:             account.Role,
+		UserID:           account.Subject(),
+	}, nil
+}
+
+func randomToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,204 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/jwt"
+
+	jwtlib "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock для AccountRepository
+type MockAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountRepository) Create(ctx context.Context, account *schemas.Account) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetByLogin(ctx context.Context, login string) (*schemas.Account, error) {
+	args := m.Called(login)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) UpdatePassword(ctx context.Context, login string, passwordHash string) error {
+	args := m.Called(login, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) IncrementFailedAttempts(ctx context.Context, login string) (int, error) {
+	args := m.Called(login)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAccountRepository) Lock(ctx context.Context, login string, until time.Time) error {
+	args := m.Called(login, until)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) ResetFailedAttempts(ctx context.Context, login string) error {
+	args := m.Called(login)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetByUserID(ctx context.Context, userID string) (*schemas.Account, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) UpdateRole(ctx context.Context, login string, role string) error {
+	args := m.Called(login, role)
+	return args.Error(0)
+}
+
+// Mock для TokenRepository
+type MockTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRepository) CreateRefreshToken(ctx context.Context, token *schemas.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*schemas.RefreshToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.RefreshToken), args.Error(1)
+}
+
+func (m *MockTokenRepository) RevokeRefreshToken(ctx context.Context, tokenHash string) (bool, error) {
+	args := m.Called(tokenHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) RevokeRefreshFamily(ctx context.Context, familyID string) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) RevokeRefreshTokensByLogin(ctx context.Context, login string) error {
+	args := m.Called(login)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	args := m.Called(jti, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	args := m.Called(jti)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) SetCutoff(ctx context.Context, login string, issuedBefore time.Time) error {
+	args := m.Called(login, issuedBefore)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) GetCutoff(ctx context.Context, login string) (*time.Time, error) {
+	args := m.Called(login)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func newTestUsecase(t *testing.T) (*Usecase, *MockAccountRepository, *MockTokenRepository) {
+	tokens, err := jwt.NewManager(jwt.Config{Secret: []byte("0123456789abcdef0123456789abcdef"), TTL: time.Minute})
+	assert.NoError(t, err)
+	accountRepo := new(MockAccountRepository)
+	tokenRepo := new(MockTokenRepository)
+	return NewUsecase(accountRepo, tokenRepo, tokens, time.Hour), accountRepo, tokenRepo
+}
+
+var alice = &schemas.Account{Login: "alice", UserID: "u1", Role: schemas.RoleMember}
+
+func TestUsecase_Refresh_Rotates(t *testing.T) {
+	usecase, accountRepo, tokenRepo := newTestUsecase(t)
+
+	stored := &schemas.RefreshToken{TokenHash: hashToken("rt"), Login: "alice", FamilyID: "fam", ExpiresAt: time.Now().Add(time.Hour)}
+	tokenRepo.On("GetRefreshToken", hashToken("rt")).Return(stored, nil)
+	tokenRepo.On("RevokeRefreshToken", stored.TokenHash).Return(true, nil)
+	accountRepo.On("GetByLogin", "alice").Return(alice, nil)
+	tokenRepo.On("CreateRefreshToken", mock.MatchedBy(func(rt *schemas.RefreshToken) bool {
+		return rt.FamilyID == "fam" && rt.Login == "alice"
+	})).Return(nil)
+
+	pair, err := usecase.Refresh(context.Background(), "rt")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "rt", pair.RefreshToken)
+	assert.Equal(t, "u1", pair.UserID)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestUsecase_Refresh_ReuseRevokesFamily(t *testing.T) {
+	usecase, _, tokenRepo := newTestUsecase(t)
+
+	stored := &schemas.RefreshToken{TokenHash: hashToken("rt"), Login: "alice", FamilyID: "fam", ExpiresAt: time.Now().Add(time.Hour)}
+	tokenRepo.On("GetRefreshToken", hashToken("rt")).Return(stored, nil)
+	tokenRepo.On("RevokeRefreshToken", stored.TokenHash).Return(false, nil)
+	tokenRepo.On("RevokeRefreshFamily", "fam").Return(nil)
+
+	_, err := usecase.Refresh(context.Background(), "rt")
+	assert.Equal(t, pkgerrors.ErrInvalidToken, err)
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestUsecase_Refresh_Expired(t *testing.T) {
+	usecase, _, tokenRepo := newTestUsecase(t)
+
+	stored := &schemas.RefreshToken{TokenHash: hashToken("rt"), Login: "alice", FamilyID: "fam", ExpiresAt: time.Now().Add(-time.Second)}
+	tokenRepo.On("GetRefreshToken", hashToken("rt")).Return(stored, nil)
+
+	_, err := usecase.Refresh(context.Background(), "rt")
+	assert.Equal(t, pkgerrors.ErrInvalidToken, err)
+	tokenRepo.AssertNotCalled(t, "RevokeRefreshToken", mock.Anything)
+}
+
+func TestUsecase_CheckAccess_RevokedJTI(t *testing.T) {
+	usecase, _, tokenRepo := newTestUsecase(t)
+
+	claims := &jwt.Claims{Login: "alice", RegisteredClaims: jwtlib.RegisteredClaims{ID: "jti-1"}}
+	tokenRepo.On("IsAccessTokenRevoked", "jti-1").Return(true, nil)
+
+	assert.Equal(t, pkgerrors.ErrTokenRevoked, usecase.CheckAccess(context.Background(), claims))
+}
+
+func TestUsecase_CheckAccess_IssuedBeforeCutoff(t *testing.T) {
+	usecase, _, tokenRepo := newTestUsecase(t)
+
+	issued := time.Now().Add(-time.Minute)
+	cutoff := time.Now()
+	claims := &jwt.Claims{Login: "alice", RegisteredClaims: jwtlib.RegisteredClaims{ID: "jti-1", IssuedAt: jwtlib.NewNumericDate(issued)}}
+	tokenRepo.On("IsAccessTokenRevoked", "jti-1").Return(false, nil)
+	tokenRepo.On("GetCutoff", "alice").Return(&cutoff, nil)
+
+	assert.Equal(t, pkgerrors.ErrTokenRevoked, usecase.CheckAccess(context.Background(), claims))
+}
+
+func TestUsecase_CheckAccess_Valid(t *testing.T) {
+	usecase, _, tokenRepo := newTestUsecase(t)
+
+	claims := &jwt.Claims{Login: "alice", RegisteredClaims: jwtlib.RegisteredClaims{ID: "jti-1", IssuedAt: jwtlib.NewNumericDate(time.Now())}}
+	tokenRepo.On("IsAccessTokenRevoked", "jti-1").Return(false, nil)
+	tokenRepo.On("GetCutoff", "alice").Return(nil, nil)
+
+	assert.NoError(t, usecase.CheckAccess(context.Background(), claims))
+}
@@ -0,0 +1,168 @@
+package sso
+
+import (
+	"context"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/jwt"
+	"ReviewAssigner/internal/pkg/oidc"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+)
+
+// Provider — OIDC-провайдер (реализация — oidc.Provider)
+type Provider interface {
+	AuthCodeURL(state, nonce, verifier string) (string, error)
+	Exchange(code, verifier string) (*oidc.TokenResponse, error)
+	VerifyIDToken(raw, nonce string) (oidc.Claims, error)
+	VerifyAccessToken(raw string) (oidc.Claims, error)
+}
+
+// Mapping — как claims провайдера превращаются в пользователя домена и роль.
+// Если ни одна группа не сопоставлена роли, выдаётся DefaultRole; пустая DefaultRole запрещает вход.
+type Mapping struct {
+	UserIDClaim string            // claim со значением schemas.User.ID, например "preferred_username"
+	GroupsClaim string            // claim со списком групп, например "groups"
+	GroupRoles  map[string]string // группа -> глобальная роль
+	DefaultRole string
+}
+
+// rolePriority — при нескольких сопоставленных группах выбирается самая сильная роль
+var rolePriority = map[string]int{schemas.RoleReadOnly: 1, schemas.RoleMember: 2, schemas.RoleAdmin: 3}
+
+type Usecase struct {
+	provider    Provider
+	stateRepo   interfaces.OIDCStateRepository
+	accountRepo interfaces.AccountRepository
+	userRepo    interfaces.UserRepository
+	mapping     Mapping
+	stateTTL    time.Duration
+}
+
+func NewUsecase(provider Provider, stateRepo interfaces.OIDCStateRepository, accountRepo interfaces.AccountRepository,
+	userRepo interfaces.UserRepository, mapping Mapping, stateTTL time.Duration) *Usecase {
+	return &Usecase{provider: provider, stateRepo: stateRepo, accountRepo: accountRepo, userRepo: userRepo, mapping: mapping, stateTTL: stateTTL}
+}
+
+// Begin начинает вход: сохраняет state, nonce и PKCE-верификатор и возвращает адрес провайдера
+func (u *Usecase) Begin(ctx context.Context) (string, error) {
+	state := &schemas.OIDCLoginState{
+		State:        oidc.RandomString(),
+		Nonce:        oidc.RandomString(),
+		CodeVerifier: oidc.RandomString(),
+		ExpiresAt:    time.Now().Add(u.stateTTL),
+	}
+	if err := u.stateRepo.Save(ctx, state); err != nil {
+		return "", err
+	}
+	return u.provider.AuthCodeURL(state.State, state.Nonce, state.CodeVerifier)
+}
+
+// Complete завершает вход по callback: обменивает код, проверяет ID-токен и возвращает учётку,
+// создавая её при первом входе. Роль учётки синхронизируется с группами провайдера.
+func (u *Usecase) Complete(ctx context.Context, state, code string) (*schemas.Account, error) {
+	pending, err := u.stateRepo.Consume(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if pending == nil || time.Now().After(pending.ExpiresAt) {
+		return nil, errors.ErrSSOFailed
+	}
+
+	tokens, err := u.provider.Exchange(code, pending.CodeVerifier)
+	if err != nil {
+		return nil, errors.ErrSSOFailed
+	}
+	claims, err := u.provider.VerifyIDToken(tokens.IDToken, pending.Nonce)
+	if err != nil {
+		return nil, errors.ErrSSOFailed
+	}
+
+	userID, role, err := u.identify(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := u.accountRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		// Пароль не задан: такая учётка может входить только через SSO
+		account = &schemas.Account{Login: schemas.SSOLoginPrefix + userID, UserID: userID, Role: role}
+		if err := u.accountRepo.Create(ctx, account); err != nil {
+			return nil, err
+		}
+		return u.accountRepo.GetByLogin(ctx, account.Login)
+	}
+	if account.Role != role {
+		if err := u.accountRepo.UpdateRole(ctx, account.Login, role); err != nil {
+			return nil, err
+		}
+		account.Role = role
+	}
+	return account, nil
+}
+
+// VerifyBearer проверяет access-токен провайдера и переводит его в наши claims,
+// чтобы AuthMiddleware и авторизация работали с ним так же, как с собственными токенами
+func (u *Usecase) VerifyBearer(ctx context.Context, raw string) (*jwt.Claims, error) {
+	claims, err := u.provider.VerifyAccessToken(raw)
+	if err != nil {
+		return nil, errors.ErrInvalidToken
+	}
+	userID, role, err := u.identify(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	login := schemas.SSOLoginPrefix + userID
+	account, err := u.accountRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if account != nil {
+		login = account.Login
+	}
+
+	result := &jwt.Claims{UserID: userID, Login: login, Role: role}
+	result.ID = claims.String("jti")
+	result.Subject = claims.String("sub")
+	if exp, ok := claims["exp"].(float64); ok {
+		result.ExpiresAt = gojwt.NewNumericDate(time.Unix(int64(exp), 0))
+	}
+	if iat, ok := claims["iat"].(float64); ok {
+		result.IssuedAt = gojwt.NewNumericDate(time.Unix(int64(iat), 0))
+	}
+	return result, nil
+}
+
+// identify находит пользователя домена по настроенному claim и вычисляет роль по группам
+func (u *Usecase) identify(ctx context.Context, claims oidc.Claims) (string, string, error) {
+	userID := claims.String(u.mapping.UserIDClaim)
+	if userID == "" {
+		return "", "", errors.ErrSSOFailed
+	}
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+	if user == nil {
+		return "", "", errors.ErrUnknownUser
+	}
+
+	role := u.mapping.DefaultRole
+	best := 0
+	for _, group := range claims.Strings(u.mapping.GroupsClaim) {
+		if r, ok := u.mapping.GroupRoles[group]; ok && rolePriority[r] > best {
+			role, best = r, rolePriority[r]
+		}
+	}
+	if role == "" {
+		return "", "", errors.ErrForbidden
+	}
+	return userID, role, nil
+}
@@ -0,0 +1,273 @@
+package sso
+
+import (
+	"context"
+	"testing"
+	"time"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+	"ReviewAssigner/internal/pkg/oidc"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock для Provider
+type MockProvider struct {
+	mock.Mock
+}
+
+func (m *MockProvider) AuthCodeURL(state, nonce, verifier string) (string, error) {
+	args := m.Called(state, nonce, verifier)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockProvider) Exchange(code, verifier string) (*oidc.TokenResponse, error) {
+	args := m.Called(code, verifier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*oidc.TokenResponse), args.Error(1)
+}
+
+func (m *MockProvider) VerifyIDToken(raw, nonce string) (oidc.Claims, error) {
+	args := m.Called(raw, nonce)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(oidc.Claims), args.Error(1)
+}
+
+func (m *MockProvider) VerifyAccessToken(raw string) (oidc.Claims, error) {
+	args := m.Called(raw)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(oidc.Claims), args.Error(1)
+}
+
+// Mock для OIDCStateRepository
+type MockOIDCStateRepository struct {
+	mock.Mock
+}
+
+func (m *MockOIDCStateRepository) Save(ctx context.Context, state *schemas.OIDCLoginState) error {
+	args := m.Called(state)
+	return args.Error(0)
+}
+
+func (m *MockOIDCStateRepository) Consume(ctx context.Context, state string) (*schemas.OIDCLoginState, error) {
+	args := m.Called(state)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.OIDCLoginState), args.Error(1)
+}
+
+// Mock для AccountRepository
+type MockAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountRepository) Create(ctx context.Context, account *schemas.Account) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetByLogin(ctx context.Context, login string) (*schemas.Account, error) {
+	args := m.Called(login)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) UpdatePassword(ctx context.Context, login string, passwordHash string) error {
+	args := m.Called(login, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) IncrementFailedAttempts(ctx context.Context, login string) (int, error) {
+	args := m.Called(login)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAccountRepository) Lock(ctx context.Context, login string, until time.Time) error {
+	args := m.Called(login, until)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) ResetFailedAttempts(ctx context.Context, login string) error {
+	args := m.Called(login)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetByUserID(ctx context.Context, userID string) (*schemas.Account, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) UpdateRole(ctx context.Context, login string, role string) error {
+	args := m.Called(login, role)
+	return args.Error(0)
+}
+
+// Mock для UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	args := m.Called(userID, isActive, events)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context) ([]schemas.User, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+type fixture struct {
+	provider *MockProvider
+	states   *MockOIDCStateRepository
+	accounts *MockAccountRepository
+	users    *MockUserRepository
+	usecase  *Usecase
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		provider: new(MockProvider),
+		states:   new(MockOIDCStateRepository),
+		accounts: new(MockAccountRepository),
+		users:    new(MockUserRepository),
+	}
+	mapping := Mapping{
+		UserIDClaim: "preferred_username",
+		GroupsClaim: "groups",
+		GroupRoles:  map[string]string{"ra-admins": schemas.RoleAdmin, "ra-viewers": schemas.RoleReadOnly},
+		DefaultRole: schemas.RoleMember,
+	}
+	f.usecase = NewUsecase(f.provider, f.states, f.accounts, f.users, mapping, time.Minute)
+	return f
+}
+
+var pending = &schemas.OIDCLoginState{State: "st", Nonce: "n1", CodeVerifier: "v1", ExpiresAt: time.Now().Add(time.Hour)}
+
+func (f *fixture) expectLogin(claims oidc.Claims) {
+	f.states.On("Consume", "st").Return(pending, nil)
+	f.provider.On("Exchange", "code", "v1").Return(&oidc.TokenResponse{IDToken: "id-token"}, nil)
+	f.provider.On("VerifyIDToken", "id-token", "n1").Return(claims, nil)
+}
+
+func TestUsecase_Begin_StoresStateAndReturnsURL(t *testing.T) {
+	f := newFixture()
+	f.states.On("Save", mock.AnythingOfType("*schemas.OIDCLoginState")).Return(nil)
+	f.provider.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything).Return("https://idp/authorize", nil)
+
+	url, err := f.usecase.Begin(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "https://idp/authorize", url)
+
+	saved := f.states.Calls[0].Arguments.Get(0).(*schemas.OIDCLoginState)
+	f.provider.AssertCalled(t, "AuthCodeURL", saved.State, saved.Nonce, saved.CodeVerifier)
+}
+
+func TestUsecase_Complete_CreatesAccountOnFirstLogin(t *testing.T) {
+	f := newFixture()
+	f.expectLogin(oidc.Claims{"preferred_username": "u1", "groups": []interface{}{"ra-admins", "ra-viewers"}})
+	f.users.On("GetByID", "u1").Return(&schemas.User{ID: "u1"}, nil)
+	f.accounts.On("GetByUserID", "u1").Return(nil, nil)
+	f.accounts.On("Create", &schemas.Account{Login: "oidc:u1", UserID: "u1", Role: schemas.RoleAdmin}).Return(nil)
+	f.accounts.On("GetByLogin", "oidc:u1").Return(&schemas.Account{Login: "oidc:u1", UserID: "u1", Role: schemas.RoleAdmin}, nil)
+
+	account, err := f.usecase.Complete(context.Background(), "st", "code")
+	assert.NoError(t, err)
+	assert.Equal(t, schemas.RoleAdmin, account.Role)
+	f.accounts.AssertExpectations(t)
+}
+
+func TestUsecase_Complete_SyncsRoleOfExistingAccount(t *testing.T) {
+	f := newFixture()
+	f.expectLogin(oidc.Claims{"preferred_username": "u1"})
+	f.users.On("GetByID", "u1").Return(&schemas.User{ID: "u1"}, nil)
+	f.accounts.On("GetByUserID", "u1").Return(&schemas.Account{Login: "alice", UserID: "u1", Role: schemas.RoleReadOnly}, nil)
+	f.accounts.On("UpdateRole", "alice", schemas.RoleMember).Return(nil)
+
+	account, err := f.usecase.Complete(context.Background(), "st", "code")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", account.Login)
+	assert.Equal(t, schemas.RoleMember, account.Role)
+}
+
+func TestUsecase_Complete_UnknownState(t *testing.T) {
+	f := newFixture()
+	f.states.On("Consume", "st").Return(nil, nil)
+
+	_, err := f.usecase.Complete(context.Background(), "st", "code")
+	assert.Equal(t, pkgerrors.ErrSSOFailed, err)
+	f.provider.AssertNotCalled(t, "Exchange", mock.Anything, mock.Anything)
+}
+
+func TestUsecase_Complete_UnknownUser(t *testing.T) {
+	f := newFixture()
+	f.expectLogin(oidc.Claims{"preferred_username": "ghost"})
+	f.users.On("GetByID", "ghost").Return(nil, nil)
+
+	_, err := f.usecase.Complete(context.Background(), "st", "code")
+	assert.Equal(t, pkgerrors.ErrUnknownUser, err)
+}
+
+func TestUsecase_VerifyBearer_MapsClaims(t *testing.T) {
+	f := newFixture()
+	f.provider.On("VerifyAccessToken", "raw").Return(oidc.Claims{
+		"preferred_username": "u1", "groups": "ra-viewers", "jti": "j1", "iat": float64(1700000000),
+	}, nil)
+	f.users.On("GetByID", "u1").Return(&schemas.User{ID: "u1"}, nil)
+	f.accounts.On("GetByUserID", "u1").Return(nil, nil)
+
+	claims, err := f.usecase.VerifyBearer(context.Background(), "raw")
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", claims.UserID)
+	assert.Equal(t, "oidc:u1", claims.Login)
+	assert.Equal(t, schemas.RoleReadOnly, claims.Role)
+	assert.Equal(t, "j1", claims.ID)
+	assert.Equal(t, int64(1700000000), claims.IssuedAt.Unix())
+}
+
+func TestUsecase_VerifyBearer_InvalidToken(t *testing.T) {
+	f := newFixture()
+	f.provider.On("VerifyAccessToken", "raw").Return(nil, assert.AnError)
+
+	_, err := f.usecase.VerifyBearer(context.Background(), "raw")
+	assert.Equal(t, pkgerrors.ErrInvalidToken, err)
+}
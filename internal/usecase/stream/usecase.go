@@ -0,0 +1,300 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+)
+
+const (
+	pollBatch = 200
+	// replayLimit — сколько пропущенных событий отдаётся при возобновлении по Last-Event-ID;
+	// если пропущено больше, подписчик получает Reset вместо неполного Backlog
+	replayLimit = 1000
+	// gapWindow — сколько последних seq перечитывается: seq выдаётся до коммита, и событие
+	// с меньшим seq может стать видимым позже большего
+	gapWindow = 100
+	// subscriberBuffer — очередь подписчика; не успевающий подписчик отключается и переподключается сам
+	subscriberBuffer = 256
+)
+
+// ResetEvent — тип сообщения потока: пропущенных событий больше, чем отдаётся при возобновлении
+const ResetEvent = "reset"
+
+// Event — событие потока и кого оно касается
+type Event struct {
+	schemas.OutboxEvent
+	Teams []string `json:"-"`
+	Users []string `json:"-"`
+}
+
+// Filter — какие события нужны подписчику; пустое поле — без ограничения
+type Filter struct {
+	Team string
+	User string
+}
+
+func (f Filter) matches(e Event) bool {
+	return (f.Team == "" || contains(e.Teams, f.Team)) && (f.User == "" || contains(e.Users, f.User))
+}
+
+// Subscription — подписка на поток. Сначала нужно отдать Backlog (или сообщить о Reset), затем читать
+// Events до закрытия Done, пропуская события, для которых Replayed возвращает true.
+type Subscription struct {
+	Backlog []Event
+	Events  <-chan Event
+	// Reset — пропущено больше replayLimit событий: Backlog пуст, клиенту нужно заново загрузить
+	// состояние и продолжать с ResetSeq
+	Reset    bool
+	ResetSeq int64
+
+	events   chan Event
+	filter   Filter
+	replayed map[int64]bool // seq из Backlog; после Subscribe только читается
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Replayed — событие уже было в Backlog (могло прийти и в Events, пока читались пропущенные)
+func (s *Subscription) Replayed(seq int64) bool {
+	return s.replayed[seq]
+}
+
+// Done закрывается, когда подписку отключили (отписка или переполнение очереди)
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Usecase раздаёт события outbox подписчикам живого потока. Каждый инстанс сам читает общую
+// таблицу outbox, поэтому подписчики получают события, записанные любым инстансом.
+type Usecase struct {
+	repo     interfaces.OutboxRepository
+	userRepo interfaces.UserRepository
+
+	mu      sync.Mutex
+	subs    map[*Subscription]struct{}
+	started bool // курсор выставлен на конец outbox: старые события не рассылаются
+	cursor  int64
+	seen    map[int64]bool // уже разосланные seq в пределах gapWindow
+}
+
+func NewUsecase(repo interfaces.OutboxRepository, userRepo interfaces.UserRepository) *Usecase {
+	return &Usecase{repo: repo, userRepo: userRepo, subs: map[*Subscription]struct{}{}, seen: map[int64]bool{}}
+}
+
+// Subscribe подписывает на события по filter; lastEventID > 0 — вернуть в Backlog пропущенные после него.
+// Backlog начинается на gapWindow раньше lastEventID: событие с меньшим seq могло стать видимым уже
+// после того, как клиент получил lastEventID. Поэтому часть событий окна клиент может получить повторно
+// и должен отбрасывать их по ID.
+func (u *Usecase) Subscribe(ctx context.Context, filter Filter, lastEventID int64) (*Subscription, error) {
+	events := make(chan Event, subscriberBuffer)
+	sub := &Subscription{Events: events, events: events, filter: filter, replayed: map[int64]bool{}, done: make(chan struct{})}
+
+	// Подписка регистрируется до чтения пропущенных, чтобы не потерять события между ними
+	u.mu.Lock()
+	u.subs[sub] = struct{}{}
+	u.mu.Unlock()
+
+	if lastEventID > 0 {
+		from := lastEventID - gapWindow
+		if from < 0 {
+			from = 0
+		}
+		missed, err := u.repo.After(ctx, from, replayLimit+1)
+		if err != nil {
+			u.Unsubscribe(sub)
+			return nil, err
+		}
+		if len(missed) > replayLimit {
+			if sub.ResetSeq, err = u.repo.LastSeq(ctx); err != nil {
+				u.Unsubscribe(sub)
+				return nil, err
+			}
+			sub.Reset = true
+			return sub, nil
+		}
+		for _, event := range u.enrich(ctx, missed) {
+			sub.replayed[event.Seq] = true
+			if filter.matches(event) {
+				sub.Backlog = append(sub.Backlog, event)
+			}
+		}
+	}
+	return sub, nil
+}
+
+func (u *Usecase) Unsubscribe(sub *Subscription) {
+	u.mu.Lock()
+	delete(u.subs, sub)
+	u.mu.Unlock()
+	sub.close()
+}
+
+// Run читает новые события outbox раз в poll и рассылает подписчикам до отмены ctx
+func (u *Usecase) Run(ctx context.Context, poll time.Duration) {
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		if err := u.Poll(ctx); err != nil {
+			log.Printf("event stream: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll рассылает события, появившиеся после прошлого прохода
+func (u *Usecase) Poll(ctx context.Context) error {
+	u.mu.Lock()
+	started := u.started
+	u.mu.Unlock()
+	if !started {
+		cursor, err := u.repo.LastSeq(ctx)
+		if err != nil {
+			return err
+		}
+		u.mu.Lock()
+		u.cursor, u.started = cursor, true
+		u.mu.Unlock()
+		return nil
+	}
+
+	for {
+		u.mu.Lock()
+		from := u.cursor - gapWindow
+		u.mu.Unlock()
+		if from < 0 {
+			from = 0
+		}
+
+		events, err := u.repo.After(ctx, from, pollBatch)
+		if err != nil {
+			return err
+		}
+		var fresh []schemas.OutboxEvent
+		for _, e := range events {
+			if u.markSeen(e.Seq) {
+				fresh = append(fresh, e)
+			}
+		}
+		for _, event := range u.enrich(ctx, fresh) {
+			u.broadcast(event)
+		}
+		if len(events) < pollBatch || len(fresh) == 0 {
+			return nil
+		}
+	}
+}
+
+// markSeen отмечает seq разосланным и сдвигает курсор; false — событие уже рассылали
+func (u *Usecase) markSeen(seq int64) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.seen[seq] || seq <= u.cursor-gapWindow {
+		return false
+	}
+	u.seen[seq] = true
+	if seq > u.cursor {
+		u.cursor = seq
+		for s := range u.seen {
+			if s <= u.cursor-gapWindow {
+				delete(u.seen, s)
+			}
+		}
+	}
+	return true
+}
+
+func (u *Usecase) broadcast(event Event) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for sub := range u.subs {
+		if !sub.filter.matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// Очередь переполнена: отключаем, клиент вернётся с Last-Event-ID
+			delete(u.subs, sub)
+			sub.close()
+		}
+	}
+}
+
+// enrich определяет команды и пользователей, которых касаются события; авторы PR загружаются одним запросом
+func (u *Usecase) enrich(ctx context.Context, outboxEvents []schemas.OutboxEvent) []Event {
+	events := make([]Event, 0, len(outboxEvents))
+	authors := map[int]string{} // индекс события PR -> автор
+	var authorIDs []string
+	for _, e := range outboxEvents {
+		event := Event{OutboxEvent: e}
+		switch e.Type {
+		case schemas.EventUserActivityChanged:
+			var data schemas.UserEventData
+			if err := json.Unmarshal(e.Data, &data); err == nil && data.User != nil {
+				event.Users = []string{data.User.ID}
+				event.Teams = []string{data.User.TeamName}
+			}
+		default:
+			var data schemas.PREventData
+			if err := json.Unmarshal(e.Data, &data); err != nil || data.PR == nil {
+				break
+			}
+			event.Users = append([]string{data.PR.AuthorID}, data.PR.AssignedReviewers...)
+			for _, id := range []string{data.ReplacedReviewer, data.NewReviewer} {
+				if id != "" && !contains(event.Users, id) {
+					event.Users = append(event.Users, id)
+				}
+			}
+			authors[len(events)] = data.PR.AuthorID
+			if !contains(authorIDs, data.PR.AuthorID) {
+				authorIDs = append(authorIDs, data.PR.AuthorID)
+			}
+		}
+		events = append(events, event)
+	}
+	if len(authorIDs) == 0 {
+		return events
+	}
+
+	// PR относится к команде автора
+	users, err := u.userRepo.GetByIDs(ctx, authorIDs)
+	if err != nil {
+		log.Printf("event stream: load PR authors: %v", err)
+		return events
+	}
+	teams := make(map[string]string, len(users))
+	for _, user := range users {
+		teams[user.ID] = user.TeamName
+	}
+	for i, authorID := range authors {
+		if team, ok := teams[authorID]; ok {
+			events[i].Teams = []string{team}
+		}
+	}
+	return events
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,279 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+	"ReviewAssigner/internal/domain/schemas"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock для OutboxRepository
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) Append(ctx context.Context, events ...*schemas.Event) error {
+	args := m.Called(events)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) Pending(ctx context.Context, sink string, limit int) ([]schemas.OutboxEvent, error) {
+	args := m.Called(sink, limit)
+	return args.Get(0).([]schemas.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkDelivered(ctx context.Context, sink string, seq int64) error {
+	args := m.Called(sink, seq)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) RecordFailure(ctx context.Context, sink string, message string) error {
+	args := m.Called(sink, message)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) ClaimSink(ctx context.Context, sink, owner string, lease time.Duration) (bool, error) {
+	args := m.Called(sink, owner, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOutboxRepository) Progress(ctx context.Context, sinks []string) ([]schemas.OutboxSinkProgress, error) {
+	args := m.Called(sinks)
+	return args.Get(0).([]schemas.OutboxSinkProgress), args.Error(1)
+}
+
+func (m *MockOutboxRepository) After(ctx context.Context, seq int64, limit int) ([]schemas.OutboxEvent, error) {
+	args := m.Called(seq, limit)
+	return args.Get(0).([]schemas.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) LastSeq(ctx context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOutboxRepository) Purge(ctx context.Context, before time.Time, sinks []string) (int, error) {
+	args := m.Called(before, sinks)
+	return args.Int(0), args.Error(1)
+}
+
+// Mock для UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	args := m.Called(userID, isActive, events)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context) ([]schemas.User, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func prEvent(t *testing.T, seq int64, authorID string, reviewers ...string) schemas.OutboxEvent {
+	event, err := schemas.NewEvent(schemas.EventPRCreated, schemas.PREventData{
+		PR: &schemas.PullRequest{ID: "pr-1", AuthorID: authorID, Status: "OPEN", AssignedReviewers: reviewers},
+	})
+	require.NoError(t, err)
+	return schemas.OutboxEvent{Seq: seq, Event: *event}
+}
+
+func userEvent(t *testing.T, seq int64, user *schemas.User) schemas.OutboxEvent {
+	event, err := schemas.NewEvent(schemas.EventUserActivityChanged, schemas.UserEventData{User: user})
+	require.NoError(t, err)
+	return schemas.OutboxEvent{Seq: seq, Event: *event}
+}
+
+// started возвращает usecase, курсор которого уже стоит на cursor
+func started(repo *MockOutboxRepository, userRepo *MockUserRepository, cursor int64) *Usecase {
+	u := NewUsecase(repo, userRepo)
+	u.started, u.cursor = true, cursor
+	return u
+}
+
+func received(sub *Subscription) []int64 {
+	var seqs []int64
+	for {
+		select {
+		case e := <-sub.Events:
+			seqs = append(seqs, e.Seq)
+		default:
+			return seqs
+		}
+	}
+}
+
+func TestPoll_FirstCallStartsFromEnd(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	u := NewUsecase(repo, new(MockUserRepository))
+	sub, err := u.Subscribe(context.Background(), Filter{}, 0)
+	require.NoError(t, err)
+
+	repo.On("LastSeq").Return(int64(42), nil)
+
+	require.NoError(t, u.Poll(context.Background()))
+
+	// События, записанные до старта, не рассылаются
+	assert.Empty(t, received(sub))
+	assert.Equal(t, int64(42), u.cursor)
+	repo.AssertNotCalled(t, "After", mock.Anything, mock.Anything)
+}
+
+func TestPoll_FiltersByTeamAndUser(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	userRepo := new(MockUserRepository)
+	u := started(repo, userRepo, 10)
+
+	all, _ := u.Subscribe(context.Background(), Filter{}, 0)
+	backend, _ := u.Subscribe(context.Background(), Filter{Team: "backend"}, 0)
+	bob, _ := u.Subscribe(context.Background(), Filter{User: "u2"}, 0)
+
+	repo.On("After", int64(0), pollBatch).Return([]schemas.OutboxEvent{
+		prEvent(t, 11, "u1", "u2", "u3"),
+		userEvent(t, 12, &schemas.User{ID: "u4", TeamName: "frontend"}),
+	}, nil)
+	userRepo.On("GetByIDs", []string{"u1"}).Return([]schemas.User{{ID: "u1", TeamName: "backend"}}, nil)
+
+	require.NoError(t, u.Poll(context.Background()))
+
+	assert.Equal(t, []int64{11, 12}, received(all))
+	assert.Equal(t, []int64{11}, received(backend))
+	assert.Equal(t, []int64{11}, received(bob))
+}
+
+func TestPoll_DoesNotRebroadcastAndPicksUpLateSeq(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	userRepo := new(MockUserRepository)
+	u := started(repo, userRepo, 200)
+	sub, _ := u.Subscribe(context.Background(), Filter{}, 0)
+	userRepo.On("GetByIDs", []string{"u1"}).Return([]schemas.User{{ID: "u1", TeamName: "backend"}}, nil)
+
+	// 202 закоммитилось раньше 201
+	repo.On("After", int64(100), pollBatch).Return([]schemas.OutboxEvent{prEvent(t, 202, "u1")}, nil).Once()
+	require.NoError(t, u.Poll(context.Background()))
+	assert.Equal(t, []int64{202}, received(sub))
+
+	repo.On("After", int64(102), pollBatch).Return([]schemas.OutboxEvent{prEvent(t, 201, "u1"), prEvent(t, 202, "u1")}, nil).Once()
+	require.NoError(t, u.Poll(context.Background()))
+	assert.Equal(t, []int64{201}, received(sub))
+
+	repo.AssertExpectations(t)
+}
+
+func TestSubscribe_ReplaysMissedEvents(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	userRepo := new(MockUserRepository)
+	u := started(repo, userRepo, 7)
+	// Авторы всех пропущенных событий загружаются одним запросом
+	userRepo.On("GetByIDs", []string{"u1", "u9"}).Return([]schemas.User{
+		{ID: "u1", TeamName: "backend"}, {ID: "u9", TeamName: "frontend"},
+	}, nil).Once()
+
+	repo.On("After", int64(0), replayLimit+1).Return([]schemas.OutboxEvent{
+		prEvent(t, 6, "u1"),
+		prEvent(t, 7, "u9"),
+	}, nil)
+
+	sub, err := u.Subscribe(context.Background(), Filter{Team: "backend"}, 5)
+
+	require.NoError(t, err)
+	require.Len(t, sub.Backlog, 1)
+	assert.Equal(t, int64(6), sub.Backlog[0].Seq)
+	assert.False(t, sub.Reset)
+	// Пропущенное событие другой команды тоже отмечено, чтобы не прийти повторно из Events
+	assert.True(t, sub.Replayed(6))
+	assert.True(t, sub.Replayed(7))
+	assert.False(t, sub.Replayed(8))
+	userRepo.AssertExpectations(t)
+}
+
+func TestSubscribe_ReplaysGapWindowBeforeLastEventID(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	userRepo := new(MockUserRepository)
+	u := started(repo, userRepo, 300)
+	userRepo.On("GetByIDs", []string{"u1"}).Return([]schemas.User{{ID: "u1", TeamName: "backend"}}, nil)
+
+	// 249 закоммитилось после того, как клиент получил 250
+	repo.On("After", int64(150), replayLimit+1).Return([]schemas.OutboxEvent{
+		prEvent(t, 249, "u1"),
+		prEvent(t, 250, "u1"),
+		prEvent(t, 251, "u1"),
+	}, nil)
+
+	sub, err := u.Subscribe(context.Background(), Filter{}, 250)
+
+	require.NoError(t, err)
+	require.Len(t, sub.Backlog, 3)
+	assert.Equal(t, int64(249), sub.Backlog[0].Seq)
+}
+
+func TestSubscribe_ResetsWhenTooManyMissed(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	u := started(repo, new(MockUserRepository), 5000)
+
+	missed := make([]schemas.OutboxEvent, replayLimit+1)
+	for i := range missed {
+		missed[i] = schemas.OutboxEvent{Seq: int64(i + 1)}
+	}
+	repo.On("After", int64(0), replayLimit+1).Return(missed, nil)
+	repo.On("LastSeq").Return(int64(5000), nil)
+
+	sub, err := u.Subscribe(context.Background(), Filter{}, 1)
+
+	require.NoError(t, err)
+	assert.True(t, sub.Reset)
+	assert.Equal(t, int64(5000), sub.ResetSeq)
+	assert.Empty(t, sub.Backlog)
+}
+
+func TestBroadcast_DropsSlowSubscriber(t *testing.T) {
+	u := NewUsecase(new(MockOutboxRepository), new(MockUserRepository))
+	slow, _ := u.Subscribe(context.Background(), Filter{}, 0)
+	fast, _ := u.Subscribe(context.Background(), Filter{}, 0)
+
+	for i := 1; i <= subscriberBuffer; i++ {
+		u.broadcast(Event{OutboxEvent: schemas.OutboxEvent{Seq: int64(i)}})
+	}
+	assert.Len(t, received(fast), subscriberBuffer)
+	u.broadcast(Event{OutboxEvent: schemas.OutboxEvent{Seq: subscriberBuffer + 1}})
+
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatal("slow subscriber was not disconnected")
+	}
+	assert.NotContains(t, u.subs, slow)
+	assert.Contains(t, u.subs, fast)
+}
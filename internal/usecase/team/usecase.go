@@ -1,6 +1,8 @@
 package team
 
 import (
+	"context"
+
 	"ReviewAssigner/internal/domain/interfaces"
 	"ReviewAssigner/internal/domain/schemas"
 	"ReviewAssigner/internal/pkg/errors"
@@ -14,23 +16,26 @@ func NewUsecase(teamRepo interfaces.TeamRepository) *Usecase {
 	return &Usecase{teamRepo: teamRepo}
 }
 
-func (u *Usecase) CreateTeam(team *schemas.Team) (*schemas.Team, error) {
-	exists, err := u.teamRepo.Exists(team.Name)
+func (u *Usecase) CreateTeam(ctx context.Context, team *schemas.Team) (*schemas.Team, error) {
+	exists, err := u.teamRepo.Exists(ctx, team.Name)
 	if err != nil {
 		return nil, err
 	}
 	if exists {
 		return nil, errors.ErrTeamExists
 	}
-	err = u.teamRepo.Create(team)
+	if team.ReviewersCount <= 0 {
+		team.ReviewersCount = schemas.DefaultReviewersCount
+	}
+	err = u.teamRepo.Create(ctx, team)
 	if err != nil {
 		return nil, err
 	}
-	return u.teamRepo.GetByName(team.Name)
+	return u.teamRepo.GetByName(ctx, team.Name)
 }
 
-func (u *Usecase) GetTeam(name string) (*schemas.Team, error) {
-	team, err := u.teamRepo.GetByName(name)
+func (u *Usecase) GetTeam(ctx context.Context, name string) (*schemas.Team, error) {
+	team, err := u.teamRepo.GetByName(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -39,3 +44,15 @@ func (u *Usecase) GetTeam(name string) (*schemas.Team, error) {
 	}
 	return team, nil
 }
+
+func (u *Usecase) ListTeams(ctx context.Context) ([]schemas.Team, error) {
+	return u.teamRepo.List(ctx)
+}
+
+// GetTeams загружает команды по именам одним запросом; несуществующие пропускаются
+func (u *Usecase) GetTeams(ctx context.Context, names []string) ([]schemas.Team, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return u.teamRepo.GetByNames(ctx, names)
+}
@@ -1,6 +1,7 @@
 package team
 
 import (
+	"context"
 	"testing"
 	"ReviewAssigner/internal/domain/schemas"
 	pkgerrors "ReviewAssigner/internal/pkg/errors"
@@ -14,12 +15,12 @@ type MockTeamRepository struct {
 	mock.Mock
 }
 
-func (m *MockTeamRepository) Create(team *schemas.Team) error {
+func (m *MockTeamRepository) Create(ctx context.Context, team *schemas.Team) error {
 	args := m.Called(team)
 	return args.Error(0)
 }
 
-func (m *MockTeamRepository) GetByName(name string) (*schemas.Team, error) {
+func (m *MockTeamRepository) GetByName(ctx context.Context, name string) (*schemas.Team, error) {
 	args := m.Called(name)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -27,11 +28,26 @@ func (m *MockTeamRepository) GetByName(name string) (*schemas.Team, error) {
 	return args.Get(0).(*schemas.Team), args.Error(1)
 }
 
-func (m *MockTeamRepository) Exists(name string) (bool, error) {
+func (m *MockTeamRepository) GetByNames(ctx context.Context, names []string) ([]schemas.Team, error) {
+	args := m.Called(names)
+	return args.Get(0).([]schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) Exists(ctx context.Context, name string) (bool, error) {
 	args := m.Called(name)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockTeamRepository) List(ctx context.Context) ([]schemas.Team, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) ApplyRoster(ctx context.Context, diff *schemas.RosterDiff) error {
+	args := m.Called(diff)
+	return args.Error(0)
+}
+
 func TestUsecase_CreateTeam_Success(t *testing.T) {
 	mockRepo := &MockTeamRepository{}
 	usecase := NewUsecase(mockRepo)
@@ -41,7 +57,7 @@ func TestUsecase_CreateTeam_Success(t *testing.T) {
 	mockRepo.On("Create", team).Return(nil)
 	mockRepo.On("GetByName", "test").Return(team, nil)
 
-	result, err := usecase.CreateTeam(team)
+	result, err := usecase.CreateTeam(context.Background(), team)
 	assert.NoError(t, err)
 	assert.Equal(t, team, result)
 	mockRepo.AssertExpectations(t)
@@ -53,7 +69,7 @@ func TestUsecase_CreateTeam_Exists(t *testing.T) {
 
 	mockRepo.On("Exists", "test").Return(true, nil)
 
-	_, err := usecase.CreateTeam(&schemas.Team{Name: "test"})
+	_, err := usecase.CreateTeam(context.Background(), &schemas.Team{Name: "test"})
 	assert.Equal(t, pkgerrors.ErrTeamExists, err)
 }
 
@@ -64,7 +80,7 @@ func TestUsecase_GetTeam_Success(t *testing.T) {
 	team := &schemas.Team{Name: "test"}
 	mockRepo.On("GetByName", "test").Return(team, nil)
 
-	result, err := usecase.GetTeam("test")
+	result, err := usecase.GetTeam(context.Background(), "test")
 	assert.NoError(t, err)
 	assert.Equal(t, team, result)
 }
@@ -75,6 +91,6 @@ func TestUsecase_GetTeam_NotFound(t *testing.T) {
 
 	mockRepo.On("GetByName", "test").Return(nil, nil)
 
-	_, err := usecase.GetTeam("test")
+	_, err := usecase.GetTeam(context.Background(), "test")
 	assert.Equal(t, pkgerrors.ErrNotFound, err)
 }
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"context"
+
 	"ReviewAssigner/internal/domain/interfaces"
 	"ReviewAssigner/internal/domain/schemas"
 	"ReviewAssigner/internal/pkg/errors"
@@ -15,8 +17,23 @@ func NewUsecase(userRepo interfaces.UserRepository, prRepo interfaces.PullReques
 	return &Usecase{userRepo: userRepo, prRepo: prRepo}
 }
 
-func (u *Usecase) SetIsActive(userID string, isActive bool) (*schemas.User, error) {
-	user, err := u.userRepo.UpdateIsActive(userID, isActive)
+// SetIsActive меняет активность; событие user.activity_changed сохраняется в outbox вместе с изменением
+func (u *Usecase) SetIsActive(ctx context.Context, userID string, isActive bool) (*schemas.User, error) {
+	current, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, errors.ErrNotFound
+	}
+	updated := *current
+	updated.IsActive = isActive
+	event, err := schemas.NewEvent(schemas.EventUserActivityChanged, schemas.UserEventData{User: &updated})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.UpdateIsActive(ctx, userID, isActive, event)
 	if err != nil {
 		return nil, err
 	}
@@ -26,14 +43,30 @@ func (u *Usecase) SetIsActive(userID string, isActive bool) (*schemas.User, erro
 	return user, nil
 }
 
-func (u *Usecase) GetUserReviews(userID string) (*schemas.User, []schemas.PullRequestShort, error) {
-	user, err := u.userRepo.GetByID(userID)
+func (u *Usecase) GetUserReviews(ctx context.Context, userID string) (*schemas.User, []schemas.PullRequestShort, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, nil, err
 	}
 	if user == nil {
 		return nil, nil, errors.ErrNotFound
 	}
-	prs, err := u.prRepo.GetByReviewerID(userID)
+	prs, err := u.prRepo.GetByReviewerID(ctx, userID)
 	return user, prs, err
 }
+
+// GetUsers загружает пользователей по идентификаторам одним запросом; несуществующие пропускаются
+func (u *Usecase) GetUsers(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	return u.userRepo.GetByIDs(ctx, userIDs)
+}
+
+// GetReviews возвращает PR на ревью у каждого из пользователей одним запросом
+func (u *Usecase) GetReviews(ctx context.Context, userIDs []string) (map[string][]schemas.PullRequestShort, error) {
+	if len(userIDs) == 0 {
+		return map[string][]schemas.PullRequestShort{}, nil
+	}
+	return u.prRepo.GetByReviewerIDs(ctx, userIDs)
+}
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"ReviewAssigner/internal/domain/schemas"
 	pkgerrors "ReviewAssigner/internal/pkg/errors"
@@ -15,7 +17,7 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) GetByID(userID string) (*schemas.User, error) {
+func (m *MockUserRepository) GetByID(ctx context.Context, userID string) (*schemas.User, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -23,30 +25,45 @@ func (m *MockUserRepository) GetByID(userID string) (*schemas.User, error) {
 	return args.Get(0).(*schemas.User), args.Error(1)
 }
 
-func (m *MockUserRepository) UpdateIsActive(userID string, isActive bool) (*schemas.User, error) {
-	args := m.Called(userID, isActive)
+func (m *MockUserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]schemas.User, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool, events ...*schemas.Event) (*schemas.User, error) {
+	args := m.Called(userID, isActive, events)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*schemas.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetActiveByTeam(teamName string, excludeUserID string) ([]schemas.User, error) {
+func (m *MockUserRepository) GetActiveByTeam(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
 	args := m.Called(teamName, excludeUserID)
 	return args.Get(0).([]schemas.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetActiveByTeamForShare(ctx context.Context, teamName string, excludeUserID string) ([]schemas.User, error) {
+	args := m.Called(teamName, excludeUserID)
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(ctx context.Context) ([]schemas.User, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.User), args.Error(1)
+}
+
 // Mock для PullRequestRepository
 type MockPullRequestRepository struct {
 	mock.Mock
 }
 
-func (m *MockPullRequestRepository) Create(pr *schemas.PullRequest) error {
-	args := m.Called(pr)
+func (m *MockPullRequestRepository) Create(ctx context.Context, pr *schemas.PullRequest, events ...*schemas.Event) error {
+	args := m.Called(pr, events)
 	return args.Error(0)
 }
 
-func (m *MockPullRequestRepository) GetByID(id string) (*schemas.PullRequest, error) {
+func (m *MockPullRequestRepository) GetByID(ctx context.Context, id string) (*schemas.PullRequest, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -54,27 +71,50 @@ func (m *MockPullRequestRepository) GetByID(id string) (*schemas.PullRequest, er
 	return args.Get(0).(*schemas.PullRequest), args.Error(1)
 }
 
-func (m *MockPullRequestRepository) UpdateStatus(id string, status string, mergedAt *time.Time) (*schemas.PullRequest, error) {
-	args := m.Called(id, status, mergedAt)
+func (m *MockPullRequestRepository) GetByIDForUpdate(ctx context.Context, id string) (*schemas.PullRequest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).(*schemas.PullRequest), args.Error(1)
 }
 
-func (m *MockPullRequestRepository) UpdateReviewers(id string, reviewers []string) error {
-	args := m.Called(id, reviewers)
+func (m *MockPullRequestRepository) GetByIDs(ctx context.Context, ids []string) ([]schemas.PullRequest, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) UpdateStatus(ctx context.Context, id string, version int, status string, mergedAt *time.Time, events ...*schemas.Event) (*schemas.PullRequest, error) {
+	args := m.Called(id, version, status, mergedAt, events)
+	return args.Get(0).(*schemas.PullRequest), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) UpdateReviewers(ctx context.Context, id string, version int, reviewers []string, events ...*schemas.Event) error {
+	args := m.Called(id, version, reviewers, events)
+	return args.Error(0)
+}
+
+func (m *MockPullRequestRepository) UpdateSCMSync(ctx context.Context, id string, state schemas.SCMSyncState) error {
+	args := m.Called(id, state)
 	return args.Error(0)
 }
 
-func (m *MockPullRequestRepository) GetByReviewerID(userID string) ([]schemas.PullRequestShort, error) {
+func (m *MockPullRequestRepository) GetByReviewerID(ctx context.Context, userID string) ([]schemas.PullRequestShort, error) {
 	args := m.Called(userID)
 	return args.Get(0).([]schemas.PullRequestShort), args.Error(1)
 }
 
-func (m *MockPullRequestRepository) Exists(id string) (bool, error) {
+func (m *MockPullRequestRepository) GetByReviewerIDs(ctx context.Context, userIDs []string) (map[string][]schemas.PullRequestShort, error) {
+	args := m.Called(userIDs)
+	return args.Get(0).(map[string][]schemas.PullRequestShort), args.Error(1)
+}
+
+func (m *MockPullRequestRepository) Exists(ctx context.Context, id string) (bool, error) {
 	args := m.Called(id)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockPullRequestRepository) GetStats() (map[string]int, map[string]int, error) {
+func (m *MockPullRequestRepository) GetStats(ctx context.Context) (map[string]int, map[string]int, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, nil, args.Error(2)
@@ -88,9 +128,13 @@ func TestUsecase_SetIsActive_Success(t *testing.T) {
 	usecase := NewUsecase(mockUserRepo, mockPRRepo)
 
 	user := &schemas.User{ID: "u1", IsActive: true}
-	mockUserRepo.On("UpdateIsActive", "u1", false).Return(user, nil)
+	mockUserRepo.On("GetByID", "u1").Return(&schemas.User{ID: "u1", IsActive: true}, nil)
+	// Событие с новым состоянием пишется вместе с изменением
+	mockUserRepo.On("UpdateIsActive", "u1", false, mock.MatchedBy(func(events []*schemas.Event) bool {
+		return len(events) == 1 && events[0].Type == schemas.EventUserActivityChanged && strings.Contains(string(events[0].Data), `"is_active":false`)
+	})).Return(user, nil)
 
-	result, err := usecase.SetIsActive("u1", false)
+	result, err := usecase.SetIsActive(context.Background(), "u1", false)
 	assert.NoError(t, err)
 	assert.Equal(t, user, result)
 }
@@ -100,9 +144,9 @@ func TestUsecase_SetIsActive_NotFound(t *testing.T) {
 	mockPRRepo := new(MockPullRequestRepository)
 	usecase := NewUsecase(mockUserRepo, mockPRRepo)
 
-	mockUserRepo.On("UpdateIsActive", "u1", false).Return(nil, nil)
+	mockUserRepo.On("GetByID", "u1").Return(nil, nil)
 
-	_, err := usecase.SetIsActive("u1", false)
+	_, err := usecase.SetIsActive(context.Background(), "u1", false)
 	assert.Equal(t, pkgerrors.ErrNotFound, err)
 }
 
@@ -116,8 +160,27 @@ func TestUsecase_GetUserReviews_Success(t *testing.T) {
 	mockUserRepo.On("GetByID", "u1").Return(user, nil)
 	mockPRRepo.On("GetByReviewerID", "u1").Return(prs, nil)
 
-	resultUser, resultPRs, err := usecase.GetUserReviews("u1")
+	resultUser, resultPRs, err := usecase.GetUserReviews(context.Background(), "u1")
 	assert.NoError(t, err)
 	assert.Equal(t, user, resultUser)
 	assert.Equal(t, prs, resultPRs)
 }
+
+func TestUsecase_GetReviews_Batch(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockPRRepo := new(MockPullRequestRepository)
+	usecase := NewUsecase(mockUserRepo, mockPRRepo)
+
+	reviews := map[string][]schemas.PullRequestShort{"u1": {{ID: "pr1"}}}
+	mockPRRepo.On("GetByReviewerIDs", []string{"u1", "u2"}).Return(reviews, nil).Once()
+
+	result, err := usecase.GetReviews(context.Background(), []string{"u1", "u2"})
+	assert.NoError(t, err)
+	assert.Equal(t, reviews, result)
+
+	// Пустой список не доходит до репозитория
+	result, err = usecase.GetReviews(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	mockPRRepo.AssertExpectations(t)
+}
@@ -0,0 +1,298 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"ReviewAssigner/internal/domain/interfaces"
+	"ReviewAssigner/internal/domain/schemas"
+	"ReviewAssigner/internal/pkg/errors"
+)
+
+// Заголовки исходящего вебхука. Подпись — "sha256=" + hex(HMAC-SHA256(secret, body)).
+const (
+	HeaderEvent     = "X-ReviewAssigner-Event"
+	HeaderDelivery  = "X-ReviewAssigner-Delivery"
+	HeaderSignature = "X-ReviewAssigner-Signature"
+)
+
+// claimBatch — сколько доставок воркер забирает за проход
+const claimBatch = 50
+
+// RetryPolicy — повторы неудачных доставок; после MaxAttempts доставка уходит в dead letter
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration // задержка после первой неудачи, дальше удваивается
+	MaxDelay    time.Duration
+}
+
+// Usecase управляет подписками и доставляет события подписчикам.
+// Publish только сохраняет доставки; отправляет их воркер (Run) с повторами.
+type Usecase struct {
+	subRepo      interfaces.WebhookSubscriptionRepository
+	deliveryRepo interfaces.WebhookDeliveryRepository
+	retry        RetryPolicy
+	client       *http.Client
+	wake         chan struct{}
+	now          func() time.Time
+}
+
+func NewUsecase(subRepo interfaces.WebhookSubscriptionRepository, deliveryRepo interfaces.WebhookDeliveryRepository, retry RetryPolicy) *Usecase {
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = 1
+	}
+	return &Usecase{
+		subRepo:      subRepo,
+		deliveryRepo: deliveryRepo,
+		retry:        retry,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		wake:         make(chan struct{}, 1),
+		now:          time.Now,
+	}
+}
+
+// CreateSubscription создаёт подписку; пустой secret генерируется. Секрет возвращается один раз.
+func (u *Usecase) CreateSubscription(ctx context.Context, rawURL, secret string, eventTypes []string, createdBy string) (*schemas.WebhookSubscription, string, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, "", errors.ErrInvalidWebhook
+	}
+	if len(eventTypes) == 0 {
+		return nil, "", errors.ErrInvalidWebhook
+	}
+	for _, t := range eventTypes {
+		if !schemas.IsKnownEventType(t) {
+			return nil, "", errors.ErrInvalidWebhook
+		}
+	}
+	if secret == "" {
+		secret = randomString(32)
+	}
+
+	sub := &schemas.WebhookSubscription{
+		ID:         randomID(),
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		CreatedBy:  createdBy,
+	}
+	if err := u.subRepo.Create(ctx, sub); err != nil {
+		return nil, "", err
+	}
+	return sub, secret, nil
+}
+
+func (u *Usecase) ListSubscriptions(ctx context.Context) ([]schemas.WebhookSubscription, error) {
+	return u.subRepo.List(ctx)
+}
+
+// DeleteSubscription удаляет подписку вместе с её доставками
+func (u *Usecase) DeleteSubscription(ctx context.Context, id string) error {
+	deleted, err := u.subRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// Publish ставит событие в очередь доставки всем подписчикам его типа
+func (u *Usecase) Publish(ctx context.Context, event *schemas.Event) error {
+	subs, err := u.subRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []schemas.WebhookDelivery
+	for _, sub := range subs {
+		if !sub.Subscribes(event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, schemas.WebhookDelivery{
+			ID:             randomID(),
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         schemas.WebhookDeliveryPending,
+			NextAttemptAt:  u.now(),
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := u.deliveryRepo.Create(ctx, deliveries); err != nil {
+		return err
+	}
+	u.notify()
+	return nil
+}
+
+// ListDeliveries — журнал доставок; status=dead — dead letter
+func (u *Usecase) ListDeliveries(ctx context.Context, status string, limit int) ([]schemas.WebhookDelivery, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return u.deliveryRepo.List(ctx, status, limit)
+}
+
+// Redeliver снова ставит доставку в очередь, в том числе из dead letter
+func (u *Usecase) Redeliver(ctx context.Context, id string) error {
+	requeued, err := u.deliveryRepo.Requeue(ctx, id, u.now())
+	if err != nil {
+		return err
+	}
+	if !requeued {
+		return errors.ErrNotFound
+	}
+	u.notify()
+	return nil
+}
+
+// Run доставляет события до отмены ctx: по сигналу Publish/Redeliver и раз в poll
+func (u *Usecase) Run(ctx context.Context, poll time.Duration) {
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		if _, err := u.DeliverDue(ctx); err != nil {
+			log.Printf("webhook delivery: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-u.wake:
+		}
+	}
+}
+
+// DeliverDue отправляет доставки, срок которых наступил, и возвращает их число
+func (u *Usecase) DeliverDue(ctx context.Context) (int, error) {
+	// Аренда с запасом на таймаут клиента: упавший воркер не блокирует доставку надолго
+	deliveries, err := u.deliveryRepo.ClaimDue(ctx, u.now(), claimBatch, 2*u.client.Timeout)
+	if err != nil {
+		return 0, err
+	}
+	subs := map[string]*schemas.WebhookSubscription{}
+	for i := range deliveries {
+		d := &deliveries[i]
+		sub, ok := subs[d.SubscriptionID]
+		if !ok {
+			if sub, err = u.subRepo.GetByID(ctx, d.SubscriptionID); err != nil {
+				return i, err
+			}
+			subs[d.SubscriptionID] = sub
+		}
+		if sub == nil {
+			continue // подписку удалили вместе с доставками
+		}
+		u.attempt(ctx, sub, d)
+		if err := u.deliveryRepo.Update(ctx, d); err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt отправляет доставку и записывает результат в d
+func (u *Usecase) attempt(ctx context.Context, sub *schemas.WebhookSubscription, d *schemas.WebhookDelivery) {
+	d.Attempts++
+	status, err := u.send(ctx, sub, d)
+	d.ResponseStatus = status
+	if err == nil {
+		now := u.now()
+		d.Status = schemas.WebhookDeliveryDelivered
+		d.DeliveredAt = &now
+		d.LastError = ""
+		return
+	}
+
+	d.LastError = err.Error()
+	if d.Attempts >= u.retry.MaxAttempts {
+		d.Status = schemas.WebhookDeliveryDead
+		return
+	}
+	d.Status = schemas.WebhookDeliveryPending
+	d.NextAttemptAt = u.now().Add(u.backoff(d.Attempts))
+}
+
+func (u *Usecase) send(ctx context.Context, sub *schemas.WebhookSubscription, d *schemas.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ReviewAssigner-Webhook")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, d.Payload))
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff — задержка после attempt-й неудачной попытки
+func (u *Usecase) backoff(attempt int) time.Duration {
+	delay := u.retry.BaseDelay << (attempt - 1)
+	if u.retry.MaxDelay > 0 && (delay > u.retry.MaxDelay || delay <= 0) {
+		delay = u.retry.MaxDelay
+	}
+	return delay
+}
+
+func (u *Usecase) notify() {
+	select {
+	case u.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Sign — значение заголовка X-ReviewAssigner-Signature; получатель сверяет его со своим расчётом
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func randomID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
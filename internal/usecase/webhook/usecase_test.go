@@ -0,0 +1,193 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"ReviewAssigner/internal/domain/schemas"
+	pkgerrors "ReviewAssigner/internal/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock для WebhookSubscriptionRepository
+type MockWebhookSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSubscriptionRepository) Create(ctx context.Context, sub *schemas.WebhookSubscription) error {
+	args := m.Called(sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookSubscriptionRepository) GetByID(ctx context.Context, id string) (*schemas.WebhookSubscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemas.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookSubscriptionRepository) List(ctx context.Context) ([]schemas.WebhookSubscription, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookSubscriptionRepository) Delete(ctx context.Context, id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+// Mock для WebhookDeliveryRepository
+type MockWebhookDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookDeliveryRepository) Create(ctx context.Context, deliveries []schemas.WebhookDelivery) error {
+	args := m.Called(deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]schemas.WebhookDelivery, error) {
+	args := m.Called(now, limit, lease)
+	return args.Get(0).([]schemas.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepository) Update(ctx context.Context, delivery *schemas.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) List(ctx context.Context, status string, limit int) ([]schemas.WebhookDelivery, error) {
+	args := m.Called(status, limit)
+	return args.Get(0).([]schemas.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepository) Requeue(ctx context.Context, id string, at time.Time) (bool, error) {
+	args := m.Called(id, at)
+	return args.Bool(0), args.Error(1)
+}
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestUsecase() (*Usecase, *MockWebhookSubscriptionRepository, *MockWebhookDeliveryRepository) {
+	subs := new(MockWebhookSubscriptionRepository)
+	deliveries := new(MockWebhookDeliveryRepository)
+	u := NewUsecase(subs, deliveries, RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Second, MaxDelay: time.Hour})
+	u.now = func() time.Time { return now }
+	return u, subs, deliveries
+}
+
+func TestCreateSubscription_Validation(t *testing.T) {
+	u, subs, _ := newTestUsecase()
+
+	_, _, err := u.CreateSubscription(context.Background(), "ftp://example.com/hook", "", []string{schemas.EventPRCreated}, "admin")
+	assert.Equal(t, pkgerrors.ErrInvalidWebhook, err)
+	_, _, err = u.CreateSubscription(context.Background(), "https://example.com/hook", "", []string{"pr.unknown"}, "admin")
+	assert.Equal(t, pkgerrors.ErrInvalidWebhook, err)
+	_, _, err = u.CreateSubscription(context.Background(), "https://example.com/hook", "", nil, "admin")
+	assert.Equal(t, pkgerrors.ErrInvalidWebhook, err)
+
+	subs.On("Create", mock.AnythingOfType("*schemas.WebhookSubscription")).Return(nil)
+	sub, secret, err := u.CreateSubscription(context.Background(), "https://example.com/hook", "", []string{schemas.EventPRMerged}, "admin")
+	require.NoError(t, err)
+	assert.NotEmpty(t, secret)
+	assert.Equal(t, secret, sub.Secret)
+}
+
+func TestPublish_OnlySubscribedTypes(t *testing.T) {
+	u, subs, deliveries := newTestUsecase()
+	subs.On("List").Return([]schemas.WebhookSubscription{
+		{ID: "s1", EventTypes: []string{schemas.EventPRCreated, schemas.EventPRMerged}},
+		{ID: "s2", EventTypes: []string{schemas.EventUserActivityChanged}},
+	}, nil)
+	deliveries.On("Create", mock.MatchedBy(func(ds []schemas.WebhookDelivery) bool {
+		return len(ds) == 1 && ds[0].SubscriptionID == "s1" && ds[0].Status == schemas.WebhookDeliveryPending &&
+			ds[0].EventType == schemas.EventPRMerged && ds[0].NextAttemptAt.Equal(now)
+	})).Return(nil)
+
+	event, err := schemas.NewEvent(schemas.EventPRMerged, schemas.PREventData{PR: &schemas.PullRequest{ID: "pr1"}})
+	require.NoError(t, err)
+	require.NoError(t, u.Publish(context.Background(), event))
+	deliveries.AssertExpectations(t)
+}
+
+func TestDeliverDue_SignedDelivery(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	u, subs, deliveries := newTestUsecase()
+	payload := json.RawMessage(`{"id":"e1","type":"pr.created","data":{}}`)
+	deliveries.On("ClaimDue", now, claimBatch, mock.Anything).Return([]schemas.WebhookDelivery{
+		{ID: "d1", SubscriptionID: "s1", EventType: schemas.EventPRCreated, Payload: payload, Status: schemas.WebhookDeliveryPending},
+	}, nil)
+	subs.On("GetByID", "s1").Return(&schemas.WebhookSubscription{ID: "s1", URL: receiver.URL, Secret: "s3cret"}, nil)
+	deliveries.On("Update", mock.MatchedBy(func(d *schemas.WebhookDelivery) bool {
+		return d.Status == schemas.WebhookDeliveryDelivered && d.Attempts == 1 && d.ResponseStatus == 204
+	})).Return(nil)
+
+	n, err := u.DeliverDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, string(payload), string(body))
+	assert.Equal(t, Sign("s3cret", payload), got.Header.Get(HeaderSignature))
+	assert.Equal(t, schemas.EventPRCreated, got.Header.Get(HeaderEvent))
+	assert.Equal(t, "d1", got.Header.Get(HeaderDelivery))
+	deliveries.AssertExpectations(t)
+}
+
+func TestDeliverDue_BackoffThenDeadLetter(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	u, subs, deliveries := newTestUsecase()
+	subs.On("GetByID", "s1").Return(&schemas.WebhookSubscription{ID: "s1", URL: receiver.URL, Secret: "s"}, nil)
+
+	var updated schemas.WebhookDelivery
+	deliveries.On("Update", mock.Anything).Run(func(args mock.Arguments) {
+		updated = *args.Get(0).(*schemas.WebhookDelivery)
+	}).Return(nil)
+
+	// Вторая неудача: задержка удваивается
+	deliveries.On("ClaimDue", now, claimBatch, mock.Anything).Return([]schemas.WebhookDelivery{
+		{ID: "d1", SubscriptionID: "s1", Status: schemas.WebhookDeliveryPending, Attempts: 1},
+	}, nil).Once()
+	_, err := u.DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, schemas.WebhookDeliveryPending, updated.Status)
+	assert.Equal(t, 2, updated.Attempts)
+	assert.Equal(t, now.Add(20*time.Second), updated.NextAttemptAt)
+	assert.Equal(t, 503, updated.ResponseStatus)
+
+	// Последняя попытка — в dead letter
+	deliveries.On("ClaimDue", now, claimBatch, mock.Anything).Return([]schemas.WebhookDelivery{updated}, nil).Once()
+	_, err = u.DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, schemas.WebhookDeliveryDead, updated.Status)
+	assert.Equal(t, 3, updated.Attempts)
+	assert.Contains(t, updated.LastError, "503")
+}
+
+func TestRedeliver(t *testing.T) {
+	u, _, deliveries := newTestUsecase()
+	deliveries.On("Requeue", "d1", now).Return(true, nil)
+	deliveries.On("Requeue", "missing", now).Return(false, nil)
+
+	assert.NoError(t, u.Redeliver(context.Background(), "d1"))
+	assert.Equal(t, pkgerrors.ErrNotFound, u.Redeliver(context.Background(), "missing"))
+}
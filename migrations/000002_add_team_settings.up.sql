@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN reviewers_count INTEGER NOT NULL DEFAULT 2 CHECK (reviewers_count > 0);
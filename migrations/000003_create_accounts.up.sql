@@ -0,0 +1,10 @@
+CREATE TABLE accounts (
+    login VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) UNIQUE REFERENCES users(user_id) ON DELETE SET NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL DEFAULT 'user',
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP NULL,
+    password_changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS token_cutoffs;
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,21 @@
+CREATE TABLE refresh_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    login VARCHAR(255) NOT NULL REFERENCES accounts(login) ON DELETE CASCADE,
+    family_id VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_login ON refresh_tokens(login);
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family_id);
+
+CREATE TABLE revoked_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE token_cutoffs (
+    login VARCHAR(255) PRIMARY KEY,
+    issued_before TIMESTAMP NOT NULL
+);
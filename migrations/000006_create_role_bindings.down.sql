@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS role_bindings;
+ALTER TABLE accounts ALTER COLUMN role SET DEFAULT 'user';
+UPDATE accounts SET role = 'user' WHERE role IN ('member', 'read_only');
@@ -0,0 +1,10 @@
+UPDATE accounts SET role = 'member' WHERE role = 'user';
+ALTER TABLE accounts ALTER COLUMN role SET DEFAULT 'member';
+
+CREATE TABLE role_bindings (
+    login VARCHAR(255) NOT NULL REFERENCES accounts(login) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL,
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (login, role, team_name)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS scm_deliveries;
+DROP TABLE IF EXISTS scm_identities;
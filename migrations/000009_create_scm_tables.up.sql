@@ -0,0 +1,17 @@
+CREATE TABLE scm_identities (
+    provider VARCHAR(50) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, username),
+    UNIQUE (provider, user_id)
+);
+
+CREATE TABLE scm_deliveries (
+    provider VARCHAR(50) NOT NULL,
+    delivery_id VARCHAR(255) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, delivery_id)
+);
+
+CREATE INDEX idx_scm_deliveries_processed_at ON scm_deliveries(processed_at);
@@ -0,0 +1,4 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS scm_synced_at;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS scm_sync_attempts;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS scm_sync_error;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS scm_sync_status;
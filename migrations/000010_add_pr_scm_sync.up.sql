@@ -0,0 +1,4 @@
+ALTER TABLE pull_requests ADD COLUMN scm_sync_status VARCHAR(20) NULL;
+ALTER TABLE pull_requests ADD COLUMN scm_sync_error TEXT NULL;
+ALTER TABLE pull_requests ADD COLUMN scm_sync_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE pull_requests ADD COLUMN scm_synced_at TIMESTAMP NULL;
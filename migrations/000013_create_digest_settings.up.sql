@@ -0,0 +1,8 @@
+CREATE TABLE digest_settings (
+    user_id VARCHAR(255) PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    send_at VARCHAR(5) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_on DATE NULL
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS outbox_sinks;
+DROP TABLE IF EXISTS outbox_deliveries;
+DROP TABLE IF EXISTS outbox_events;
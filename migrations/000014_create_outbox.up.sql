@@ -0,0 +1,26 @@
+CREATE TABLE outbox_events (
+    seq BIGSERIAL PRIMARY KEY,
+    event_id VARCHAR(64) NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_outbox_events_occurred_at ON outbox_events(occurred_at);
+
+-- Отметки о доставке событий каждому получателю
+CREATE TABLE outbox_deliveries (
+    sink VARCHAR(64) NOT NULL,
+    seq BIGINT NOT NULL REFERENCES outbox_events(seq) ON DELETE CASCADE,
+    delivered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (sink, seq)
+);
+
+-- Аренда получателя инстансом и последние ошибки доставки
+CREATE TABLE outbox_sinks (
+    sink VARCHAR(64) PRIMARY KEY,
+    locked_by VARCHAR(64) NULL,
+    locked_until TIMESTAMP NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL
+);
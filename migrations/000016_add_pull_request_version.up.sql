@@ -0,0 +1,2 @@
+-- Версия PR для оптимистичных блокировок: растёт при каждой смене статуса или ревьюверов
+ALTER TABLE pull_requests ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
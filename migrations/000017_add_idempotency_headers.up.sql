@@ -0,0 +1,2 @@
+-- Заголовки ответа (ETag, Location, ...), которые повтор запроса получает вместе с телом
+ALTER TABLE idempotency_keys ADD COLUMN headers JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,9 @@
+-- Повторная публикация события из outbox не должна порождать вторую доставку подписчику.
+-- Уже накопившиеся дубли удаляем, оставляя самую раннюю доставку.
+DELETE FROM webhook_deliveries a
+    USING webhook_deliveries b
+    WHERE a.subscription_id = b.subscription_id AND a.event_id = b.event_id
+      AND (a.created_at, a.id) > (b.created_at, b.id);
+
+ALTER TABLE webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_subscription_event_key UNIQUE (subscription_id, event_id);
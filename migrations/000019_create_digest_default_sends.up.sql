@@ -0,0 +1,5 @@
+-- Когда пользователь без своей настройки последний раз получил сводку по расписанию по умолчанию
+CREATE TABLE digest_default_sends (
+    user_id VARCHAR(255) PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    last_sent_on DATE NOT NULL
+);